@@ -0,0 +1,267 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift-online/ocm-sdk-go/logging"
+)
+
+type AddOnsDataSourceType struct {
+}
+
+type AddOnsDataSource struct {
+	logger     logging.Logger
+	collection *cmv1.AddOnsClient
+}
+
+func (t *AddOnsDataSourceType) GetSchema(ctx context.Context) (result tfsdk.Schema,
+	diags diag.Diagnostics) {
+	result = tfsdk.Schema{
+		Description: "List of add-ons that can be installed in clusters.",
+		Attributes: map[string]tfsdk.Attribute{
+			"items": {
+				Description: "Items of the list.",
+				Attributes: tfsdk.ListNestedAttributes(
+					map[string]tfsdk.Attribute{
+						"id": {
+							Description: "Unique identifier of the " +
+								"add-on.",
+							Type:     types.StringType,
+							Computed: true,
+						},
+						"name": {
+							Description: "Short name of the add-on.",
+							Type:        types.StringType,
+							Computed:    true,
+						},
+						"description": {
+							Description: "Description of the add-on.",
+							Type:        types.StringType,
+							Computed:    true,
+						},
+						"enabled": {
+							Description: "Indicates if the add-on can " +
+								"be installed.",
+							Type:     types.BoolType,
+							Computed: true,
+						},
+						"install_mode": {
+							Description: "Install mode of the add-on, " +
+								"for example 'own_namespace' or " +
+								"'all_namespaces'.",
+							Type:     types.StringType,
+							Computed: true,
+						},
+						"target_namespace": {
+							Description: "Namespace where the add-on " +
+								"is installed.",
+							Type:     types.StringType,
+							Computed: true,
+						},
+						"version": {
+							Description: "Identifier of the current " +
+								"version of the add-on.",
+							Type:     types.StringType,
+							Computed: true,
+						},
+						"parameters": {
+							Description: "Parameters that can be " +
+								"passed when installing the add-on.",
+							Attributes: tfsdk.ListNestedAttributes(
+								addOnParameterAttributes(),
+								tfsdk.ListNestedAttributesOptions{},
+							),
+							Computed: true,
+						},
+					},
+					tfsdk.ListNestedAttributesOptions{},
+				),
+				Computed: true,
+			},
+		},
+	}
+	return
+}
+
+func addOnParameterAttributes() map[string]tfsdk.Attribute {
+	return map[string]tfsdk.Attribute{
+		"id": {
+			Description: "Unique identifier of the parameter, used " +
+				"as key of the 'parameters' of the " +
+				"'ocm_cluster_addon' resource.",
+			Type:     types.StringType,
+			Computed: true,
+		},
+		"name": {
+			Description: "Short name of the parameter.",
+			Type:        types.StringType,
+			Computed:    true,
+		},
+		"description": {
+			Description: "Description of the parameter.",
+			Type:        types.StringType,
+			Computed:    true,
+		},
+		"value_type": {
+			Description: "Type of the value of the parameter, " +
+				"for example 'string', 'number', 'boolean' " +
+				"or 'cidr'.",
+			Type:     types.StringType,
+			Computed: true,
+		},
+		"required": {
+			Description: "Indicates if the parameter must be " +
+				"set when installing the add-on.",
+			Type:     types.BoolType,
+			Computed: true,
+		},
+		"editable": {
+			Description: "Indicates if the parameter can be " +
+				"changed after installing the add-on.",
+			Type:     types.BoolType,
+			Computed: true,
+		},
+		"default_value": {
+			Description: "Value used when the parameter " +
+				"isn't set.",
+			Type:     types.StringType,
+			Computed: true,
+		},
+		"validation": {
+			Description: "Regular expression that the value " +
+				"of the parameter must match.",
+			Type:     types.StringType,
+			Computed: true,
+		},
+		"options": {
+			Description: "Values allowed for the parameter. " +
+				"Empty if any value is allowed.",
+			Attributes: tfsdk.ListNestedAttributes(
+				map[string]tfsdk.Attribute{
+					"name": {
+						Description: "Name of the option.",
+						Type:        types.StringType,
+						Computed:    true,
+					},
+					"value": {
+						Description: "Value of the option.",
+						Type:        types.StringType,
+						Computed:    true,
+					},
+				},
+				tfsdk.ListNestedAttributesOptions{},
+			),
+			Computed: true,
+		},
+	}
+}
+
+func (t *AddOnsDataSourceType) NewDataSource(ctx context.Context,
+	p tfsdk.Provider) (result tfsdk.DataSource, diags diag.Diagnostics) {
+	// Cast the provider interface to the specific implementation:
+	parent := p.(*Provider)
+
+	// Get the collection of add-ons:
+	collection := parent.connection.ClustersMgmt().V1().Addons()
+
+	// Create the resource:
+	result = &AddOnsDataSource{
+		logger:     parent.logger,
+		collection: collection,
+	}
+	return
+}
+
+func (s *AddOnsDataSource) Read(ctx context.Context, request tfsdk.ReadDataSourceRequest,
+	response *tfsdk.ReadDataSourceResponse) {
+	// Fetch the complete list of add-ons:
+	var listItems []*cmv1.AddOn
+	listSize := 10
+	listPage := 1
+	listRequest := s.collection.List().Size(listSize)
+	for {
+		listResponse, err := listRequest.SendContext(ctx)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't list add-ons",
+				err.Error(),
+			)
+			return
+		}
+		if listItems == nil {
+			listItems = make([]*cmv1.AddOn, 0, listResponse.Total())
+		}
+		listResponse.Items().Each(func(listItem *cmv1.AddOn) bool {
+			listItems = append(listItems, listItem)
+			return true
+		})
+		if listResponse.Size() < listSize {
+			break
+		}
+		listPage++
+		listRequest.Page(listPage)
+	}
+
+	// Populate the state:
+	state := &AddOnsState{
+		Items: make([]*AddOnState, len(listItems)),
+	}
+	for i, listItem := range listItems {
+		parameters := []*AddOnParameterState{}
+		listItem.Parameters().Each(func(parameter *cmv1.AddOnParameter) bool {
+			options := []*AddOnParameterOptionState{}
+			for _, option := range parameter.Options() {
+				options = append(options, &AddOnParameterOptionState{
+					Name:  option.Name(),
+					Value: option.Value(),
+				})
+			}
+			parameters = append(parameters, &AddOnParameterState{
+				ID:           parameter.ID(),
+				Name:         parameter.Name(),
+				Description:  parameter.Description(),
+				ValueType:    parameter.ValueType(),
+				Required:     parameter.Required(),
+				Editable:     parameter.Editable(),
+				DefaultValue: parameter.DefaultValue(),
+				Validation:   parameter.Validation(),
+				Options:      options,
+			})
+			return true
+		})
+		state.Items[i] = &AddOnState{
+			ID:              listItem.ID(),
+			Name:            listItem.Name(),
+			Description:     listItem.Description(),
+			Enabled:         listItem.Enabled(),
+			InstallMode:     string(listItem.InstallMode()),
+			TargetNamespace: listItem.TargetNamespace(),
+			Version:         listItem.Version().ID(),
+			Parameters:      parameters,
+		}
+	}
+
+	// Save the state:
+	diags := response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
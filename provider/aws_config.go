@@ -0,0 +1,147 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// AWSConfig contains the AWS credentials used by the provider for the checks that it runs before
+// creating clusters, like the validation of the account roles.
+type AWSConfig struct {
+	Profile           types.String         `tfsdk:"profile"`
+	SharedConfigFiles types.List           `tfsdk:"shared_config_files"`
+	AssumeRole        *AWSAssumeRoleConfig `tfsdk:"assume_role"`
+	Endpoint          types.String         `tfsdk:"endpoint"`
+}
+
+// AWSAssumeRoleConfig contains the role that the provider assumes with the AWS credentials.
+type AWSAssumeRoleConfig struct {
+	RoleARN     types.String `tfsdk:"role_arn"`
+	ExternalID  types.String `tfsdk:"external_id"`
+	SessionName types.String `tfsdk:"session_name"`
+}
+
+// awsSessionOptions contains the options used to create AWS sessions. Empty values mean that
+// the defaults of the AWS SDK, taken from the environment, are used.
+type awsSessionOptions struct {
+	profile           string
+	sharedConfigFiles []string
+	roleARN           string
+	externalID        string
+	sessionName       string
+	endpoint          string
+	httpClient        *http.Client
+}
+
+// newAWSSessionOptions creates the AWS session options from the 'aws' block of the provider
+// configuration, which may be nil. It returns an error message if the configuration isn't valid.
+func newAWSSessionOptions(config *AWSConfig, httpClient *http.Client) (result *awsSessionOptions,
+	errMsg string) {
+	result = &awsSessionOptions{
+		httpClient: httpClient,
+	}
+	if config == nil {
+		return
+	}
+	if !config.Profile.Unknown && !config.Profile.Null {
+		result.profile = config.Profile.Value
+	}
+	if !config.SharedConfigFiles.Unknown && !config.SharedConfigFiles.Null {
+		for _, file := range config.SharedConfigFiles.Elems {
+			result.sharedConfigFiles = append(result.sharedConfigFiles, file.(types.String).Value)
+		}
+	}
+	if !config.Endpoint.Unknown && !config.Endpoint.Null {
+		endpoint, err := url.Parse(config.Endpoint.Value)
+		if err != nil || endpoint.Scheme == "" || endpoint.Host == "" {
+			errMsg = fmt.Sprintf("Expected a valid URL for the AWS endpoint, got '%s'", config.Endpoint.Value)
+			return
+		}
+		result.endpoint = config.Endpoint.Value
+	}
+	if config.AssumeRole != nil {
+		_, err := arn.Parse(config.AssumeRole.RoleARN.Value)
+		if err != nil {
+			errMsg = fmt.Sprintf(
+				"Expected a valid ARN for the role to assume, got '%s'",
+				config.AssumeRole.RoleARN.Value,
+			)
+			return
+		}
+		result.roleARN = config.AssumeRole.RoleARN.Value
+		if !config.AssumeRole.ExternalID.Unknown && !config.AssumeRole.ExternalID.Null {
+			result.externalID = config.AssumeRole.ExternalID.Value
+		}
+		if !config.AssumeRole.SessionName.Unknown && !config.AssumeRole.SessionName.Null {
+			result.sessionName = config.AssumeRole.SessionName.Value
+		}
+	}
+	return
+}
+
+// newSession creates an AWS session for the given region using these options. If a role to
+// assume is configured, the credentials of the session are the ones of that role.
+func (o *awsSessionOptions) newSession(region string) (*session.Session, error) {
+	httpClient := o.httpClient
+	if httpClient == nil {
+		httpClient = &http.Client{
+			Transport: http.DefaultTransport,
+		}
+	}
+	config := aws.Config{
+		CredentialsChainVerboseErrors: aws.Bool(true),
+		Region:                        &region,
+		Retryer:                       buildCustomRetryer(),
+		HTTPClient:                    httpClient,
+	}
+	if o.endpoint != "" {
+		config.Endpoint = aws.String(o.endpoint)
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{
+		SharedConfigState: session.SharedConfigEnable,
+		Profile:           o.profile,
+		SharedConfigFiles: o.sharedConfigFiles,
+		Config:            config,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if o.roleARN != "" {
+		credentials := stscreds.NewCredentials(sess, o.roleARN,
+			func(provider *stscreds.AssumeRoleProvider) {
+				if o.externalID != "" {
+					provider.ExternalID = aws.String(o.externalID)
+				}
+				if o.sessionName != "" {
+					provider.RoleSessionName = o.sessionName
+				}
+			})
+		sess = sess.Copy(&aws.Config{
+			Credentials: credentials,
+		})
+	}
+	return sess, nil
+}
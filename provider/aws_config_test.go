@@ -0,0 +1,134 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+)
+
+var _ = Describe("AWS configuration", func() {
+	var tmpDir string
+	var configFile string
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "aws-config-*.d")
+		Expect(err).ToNot(HaveOccurred())
+		configFile = filepath.Join(tmpDir, "config")
+		err = os.WriteFile(configFile, []byte(
+			"[profile pipeline]\n"+
+				"aws_access_key_id = AKIAPIPELINE\n"+
+				"aws_secret_access_key = pipeline-secret\n",
+		), 0600)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(tmpDir)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	newConfig := func() *AWSConfig {
+		return &AWSConfig{
+			Profile: types.String{Value: "pipeline"},
+			SharedConfigFiles: types.List{
+				ElemType: types.StringType,
+				Elems: []attr.Value{
+					types.String{Value: configFile},
+				},
+			},
+			Endpoint: types.String{Null: true},
+		}
+	}
+
+	It("Uses the credentials of the configured profile", func() {
+		options, errMsg := newAWSSessionOptions(newConfig(), nil)
+		Expect(errMsg).To(BeEmpty())
+		sess, err := options.newSession("us-east-1")
+		Expect(err).ToNot(HaveOccurred())
+		credentials, err := sess.Config.Credentials.Get()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(credentials.AccessKeyID).To(Equal("AKIAPIPELINE"))
+		Expect(credentials.SecretAccessKey).To(Equal("pipeline-secret"))
+	})
+
+	It("Assumes the configured role using the custom endpoint", func() {
+		var form url.Values
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			err := r.ParseForm()
+			Expect(err).ToNot(HaveOccurred())
+			form = r.PostForm
+			w.Header().Set("Content-Type", "text/xml")
+			_, err = w.Write([]byte(`<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+			  <AssumeRoleResult>
+			    <Credentials>
+			      <AccessKeyId>ASIAASSUMED</AccessKeyId>
+			      <SecretAccessKey>assumed-secret</SecretAccessKey>
+			      <SessionToken>assumed-token</SessionToken>
+			      <Expiration>2100-01-01T00:00:00Z</Expiration>
+			    </Credentials>
+			  </AssumeRoleResult>
+			</AssumeRoleResponse>`))
+			Expect(err).ToNot(HaveOccurred())
+		}))
+		defer server.Close()
+
+		config := newConfig()
+		config.Endpoint = types.String{Value: server.URL}
+		config.AssumeRole = &AWSAssumeRoleConfig{
+			RoleARN:     types.String{Value: "arn:aws:iam::123456789012:role/preflight"},
+			ExternalID:  types.String{Value: "my-external-id"},
+			SessionName: types.String{Value: "terraform"},
+		}
+		options, errMsg := newAWSSessionOptions(config, nil)
+		Expect(errMsg).To(BeEmpty())
+		sess, err := options.newSession("us-east-1")
+		Expect(err).ToNot(HaveOccurred())
+		credentials, err := sess.Config.Credentials.Get()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(credentials.AccessKeyID).To(Equal("ASIAASSUMED"))
+		Expect(form.Get("Action")).To(Equal("AssumeRole"))
+		Expect(form.Get("RoleArn")).To(Equal("arn:aws:iam::123456789012:role/preflight"))
+		Expect(form.Get("ExternalId")).To(Equal("my-external-id"))
+		Expect(form.Get("RoleSessionName")).To(Equal("terraform"))
+	})
+
+	It("Rejects invalid configurations", func() {
+		config := newConfig()
+		config.Endpoint = types.String{Value: "localhost"}
+		_, errMsg := newAWSSessionOptions(config, nil)
+		Expect(errMsg).To(ContainSubstring("endpoint"))
+
+		config = newConfig()
+		config.AssumeRole = &AWSAssumeRoleConfig{
+			RoleARN:     types.String{Value: "preflight"},
+			ExternalID:  types.String{Null: true},
+			SessionName: types.String{Null: true},
+		}
+		_, errMsg = newAWSSessionOptions(config, nil)
+		Expect(errMsg).To(ContainSubstring("role to assume"))
+	})
+})
@@ -0,0 +1,675 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift-online/ocm-sdk-go/logging"
+	"github.com/terraform-redhat/terraform-provider-ocm/provider/common"
+)
+
+const (
+	clusterAddOnImportIDFormat = "<cluster_id>,<addon_id>"
+	addOnPollingInterval       = 30 * time.Second
+)
+
+type ClusterAddOnResourceType struct {
+}
+
+type ClusterAddOnResource struct {
+	logger     logging.Logger
+	collection *cmv1.ClustersClient
+	addOns     *cmv1.AddOnsClient
+}
+
+func (t *ClusterAddOnResourceType) GetSchema(ctx context.Context) (result tfsdk.Schema,
+	diags diag.Diagnostics) {
+	result = tfsdk.Schema{
+		Description: "Add-on installed in a cluster.",
+		Attributes: map[string]tfsdk.Attribute{
+			"cluster": {
+				Description: "Identifier of the cluster.",
+				Type:        types.StringType,
+				Required:    true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					tfsdk.RequiresReplace(),
+				},
+			},
+			"id": {
+				Description: "Unique identifier of the add-on installation.",
+				Type:        types.StringType,
+				Computed:    true,
+			},
+			"addon": {
+				Description: "Identifier of the add-on. Use the 'ocm_addons' data " +
+					"source to find the possible values.",
+				Type:     types.StringType,
+				Required: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					tfsdk.RequiresReplace(),
+				},
+			},
+			"parameters": {
+				Description: "Values of the parameters of the add-on, indexed by parameter " +
+					"identifier. Values are checked against the type, validation and options of " +
+					"the parameter, for example numbers and booleans are given as '10' or 'true'. " +
+					"Use the 'ocm_addons' data source to find the parameters of an add-on.",
+				Type: types.MapType{
+					ElemType: types.StringType,
+				},
+				Optional: true,
+			},
+			"timeout": {
+				Description: "Timeout in minutes to wait till the add-on is installed. " +
+					"The default value is 60 minutes.",
+				Type:     types.Int64Type,
+				Optional: true,
+			},
+			"state": {
+				Description: "State of the add-on installation.",
+				Type:        types.StringType,
+				Computed:    true,
+			},
+		},
+	}
+	return
+}
+
+func (t *ClusterAddOnResourceType) NewResource(ctx context.Context,
+	p tfsdk.Provider) (result tfsdk.Resource, diags diag.Diagnostics) {
+	// Cast the provider interface to the specific implementation: use it directly when needed.
+	parent := p.(*Provider)
+
+	// Get the collections of clusters and add-ons:
+	collection := parent.connection.ClustersMgmt().V1().Clusters()
+	addOns := parent.connection.ClustersMgmt().V1().Addons()
+
+	// Create the resource:
+	result = &ClusterAddOnResource{
+		logger:     parent.logger,
+		collection: collection,
+		addOns:     addOns,
+	}
+
+	return
+}
+
+func (r *ClusterAddOnResource) Create(ctx context.Context,
+	request tfsdk.CreateResourceRequest, response *tfsdk.CreateResourceResponse) {
+	// Get the plan:
+	state := &ClusterAddOnState{}
+	diags := request.Plan.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+
+	// Wait till the cluster is ready:
+	resource := r.collection.Cluster(state.Cluster.Value)
+	pollCtx, cancel := context.WithTimeout(ctx, 1*time.Hour)
+	defer cancel()
+	_, err := resource.Poll().
+		Interval(30 * time.Second).
+		Predicate(func(get *cmv1.ClusterGetResponse) bool {
+			return get.Body().State() == cmv1.ClusterStateReady
+		}).
+		StartContext(pollCtx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't poll cluster state",
+			fmt.Sprintf(
+				"Can't poll state of cluster with identifier '%s': %v",
+				state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+
+	// Check the parameters against the definition of the add-on:
+	parameters := getAddOnParameters(state)
+	addOn, err := r.getAddOn(ctx, state.AddOn.Value)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't find add-on",
+			fmt.Sprintf(
+				"Can't find add-on with identifier '%s': %v",
+				state.AddOn.Value, err,
+			),
+		)
+		return
+	}
+	errMsg := validateAddOnParameters(addOn, parameters, nil)
+	if errMsg != "" {
+		response.Diagnostics.AddError(
+			"Invalid add-on parameters",
+			fmt.Sprintf(
+				"Invalid parameters for add-on '%s': %s",
+				state.AddOn.Value, errMsg,
+			),
+		)
+		return
+	}
+
+	// Install the add-on:
+	object, err := cmv1.NewAddOnInstallation().
+		Addon(cmv1.NewAddOn().ID(state.AddOn.Value)).
+		Parameters(buildAddOnInstallationParameters(parameters)).
+		Build()
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't build add-on installation",
+			fmt.Sprintf(
+				"Can't build installation of add-on '%s' for cluster '%s': %v",
+				state.AddOn.Value, state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+	add, err := resource.Addons().Add().Body(object).SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't install add-on",
+			fmt.Sprintf(
+				"Can't install add-on '%s' in cluster '%s': %v",
+				state.AddOn.Value, state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+	object = add.Body()
+
+	// Save the state before waiting, so that the add-on isn't left outside of the state if the
+	// waiting fails, and Terraform marks the resource as tainted because of the error:
+	r.populateState(object, state)
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// Wait till the add-on is installed:
+	object, err = r.waitForAddOnInstallation(ctx, state, object.ID())
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't install add-on",
+			fmt.Sprintf(
+				"Can't install add-on '%s' in cluster '%s': %v",
+				state.AddOn.Value, state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+
+	// Save the state:
+	r.populateState(object, state)
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *ClusterAddOnResource) Read(ctx context.Context, request tfsdk.ReadResourceRequest,
+	response *tfsdk.ReadResourceResponse) {
+	// Get the current state:
+	state := &ClusterAddOnState{}
+	diags := request.State.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+	ctx = withObjectLogField(ctx, state.ID.Value)
+
+	// Find the add-on installation:
+	get, err := r.collection.Cluster(state.Cluster.Value).
+		Addons().
+		Addoninstallation(state.ID.Value).
+		Get().
+		SendContext(ctx)
+	if err != nil {
+		if common.IsStatusNotFound(err) {
+			r.logger.Warn(ctx, "Add-on installation wasn't found, removing it from the state")
+			response.State.RemoveResource(ctx)
+			return
+		}
+		response.Diagnostics.AddError(
+			"Can't find add-on installation",
+			fmt.Sprintf(
+				"Can't find installation of add-on '%s' for cluster '%s': %v",
+				state.ID.Value, state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+	object := get.Body()
+
+	// Save the state:
+	r.populateState(object, state)
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *ClusterAddOnResource) Update(ctx context.Context, request tfsdk.UpdateResourceRequest,
+	response *tfsdk.UpdateResourceResponse) {
+	var diags diag.Diagnostics
+
+	// Get the state:
+	state := &ClusterAddOnState{}
+	diags = request.State.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+	ctx = withObjectLogField(ctx, state.ID.Value)
+
+	// Get the plan:
+	plan := &ClusterAddOnState{}
+	diags = request.Plan.Get(ctx, plan)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// The add-on and the cluster can't change, as that requires replacing the installation,
+	// so only the parameters are updated:
+	parameters := getAddOnParameters(plan)
+	if !plan.Parameters.Equal(state.Parameters) {
+		addOn, err := r.getAddOn(ctx, state.AddOn.Value)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't find add-on",
+				fmt.Sprintf(
+					"Can't find add-on with identifier '%s': %v",
+					state.AddOn.Value, err,
+				),
+			)
+			return
+		}
+		errMsg := validateAddOnParameters(addOn, parameters, getAddOnParameters(state))
+		if errMsg != "" {
+			response.Diagnostics.AddError(
+				"Invalid add-on parameters",
+				fmt.Sprintf(
+					"Invalid parameters for add-on '%s': %s",
+					state.AddOn.Value, errMsg,
+				),
+			)
+			return
+		}
+		object, err := cmv1.NewAddOnInstallation().
+			Parameters(buildAddOnInstallationParameters(parameters)).
+			Build()
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't build add-on installation",
+				fmt.Sprintf(
+					"Can't build installation of add-on '%s' for cluster '%s': %v",
+					state.AddOn.Value, state.Cluster.Value, err,
+				),
+			)
+			return
+		}
+		_, err = r.collection.Cluster(state.Cluster.Value).
+			Addons().
+			Addoninstallation(state.ID.Value).
+			Update().
+			Body(object).
+			SendContext(ctx)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't update add-on installation",
+				fmt.Sprintf(
+					"Can't update parameters of add-on '%s' in cluster '%s': %v",
+					state.AddOn.Value, state.Cluster.Value, err,
+				),
+			)
+			return
+		}
+	}
+
+	// Save the state before waiting, so that the parameters that were applied aren't lost if
+	// the waiting fails:
+	state.Parameters = plan.Parameters
+	state.Timeout = plan.Timeout
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// Wait till the changes are applied:
+	object, err := r.waitForAddOnInstallation(ctx, state, state.ID.Value)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't update add-on installation",
+			fmt.Sprintf(
+				"Can't update parameters of add-on '%s' in cluster '%s': %v",
+				state.AddOn.Value, state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+
+	// Save the state:
+	r.populateState(object, state)
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *ClusterAddOnResource) Delete(ctx context.Context, request tfsdk.DeleteResourceRequest,
+	response *tfsdk.DeleteResourceResponse) {
+	// Get the state:
+	state := &ClusterAddOnState{}
+	diags := request.State.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+	ctx = withObjectLogField(ctx, state.ID.Value)
+
+	// Send the request to uninstall the add-on:
+	_, err := r.collection.Cluster(state.Cluster.Value).
+		Addons().
+		Addoninstallation(state.ID.Value).
+		Delete().
+		SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't uninstall add-on",
+			fmt.Sprintf(
+				"Can't uninstall add-on '%s' from cluster '%s': %v",
+				state.ID.Value, state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+
+	// Remove the state:
+	response.State.RemoveResource(ctx)
+}
+
+func (r *ClusterAddOnResource) ImportState(ctx context.Context, request tfsdk.ImportResourceStateRequest,
+	response *tfsdk.ImportResourceStateResponse) {
+	fields, err := common.SplitImportID(request.ID, clusterAddOnImportIDFormat)
+	if err != nil {
+		response.Diagnostics.AddError("Invalid import identifier", err.Error())
+		return
+	}
+	clusterID := fields[0]
+	addOnID := fields[1]
+
+	// Try to retrieve the object:
+	get, err := r.collection.Cluster(clusterID).
+		Addons().
+		Addoninstallation(addOnID).
+		Get().
+		SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't find add-on installation",
+			fmt.Sprintf(
+				"Can't find installation of add-on '%s' for cluster '%s': %v",
+				addOnID, clusterID, err,
+			),
+		)
+		return
+	}
+	object := get.Body()
+
+	// Save the state, all the parameters of the installation are imported:
+	state := &ClusterAddOnState{
+		Cluster: types.String{
+			Value: clusterID,
+		},
+		Parameters: types.Map{
+			ElemType: types.StringType,
+			Null:     true,
+		},
+		Timeout: types.Int64{
+			Null: true,
+		},
+	}
+	parameters := object.Parameters()
+	if parameters.Len() > 0 {
+		state.Parameters = types.Map{
+			ElemType: types.StringType,
+			Elems:    map[string]attr.Value{},
+		}
+		parameters.Each(func(parameter *cmv1.AddOnInstallationParameter) bool {
+			state.Parameters.Elems[parameter.ID()] = types.String{
+				Value: parameter.Value(),
+			}
+			return true
+		})
+	}
+	r.populateState(object, state)
+	diags := response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+// getAddOn retrieves the definition of an add-on, including its parameters.
+func (r *ClusterAddOnResource) getAddOn(ctx context.Context, id string) (*cmv1.AddOn, error) {
+	get, err := r.addOns.Addon(id).Get().SendContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return get.Body(), nil
+}
+
+// waitForAddOnInstallation waits till the add-on installation is ready, and returns an error if
+// it fails or if it isn't ready before the timeout.
+func (r *ClusterAddOnResource) waitForAddOnInstallation(ctx context.Context, state *ClusterAddOnState,
+	id string) (*cmv1.AddOnInstallation, error) {
+	timeout := defaultTimeoutInMinutes
+	if !state.Timeout.Unknown && !state.Timeout.Null && state.Timeout.Value > 0 {
+		timeout = state.Timeout.Value
+	}
+	pollCtx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Minute)
+	defer cancel()
+	var object *cmv1.AddOnInstallation
+	_, err := r.collection.Cluster(state.Cluster.Value).
+		Addons().
+		Addoninstallation(id).
+		Poll().
+		Interval(addOnPollingInterval).
+		Predicate(func(get *cmv1.AddOnInstallationGetResponse) bool {
+			object = get.Body()
+			r.logger.Debug(withLogField(ctx, logFieldState, string(object.State())),
+				"Polled add-on installation")
+			switch object.State() {
+			case cmv1.AddOnInstallationStateReady,
+				cmv1.AddOnInstallationStateFailed:
+				return true
+			}
+			return false
+		}).
+		StartContext(pollCtx)
+	if err != nil {
+		return nil, err
+	}
+	if object.State() == cmv1.AddOnInstallationStateFailed {
+		return nil, fmt.Errorf("installation failed: %s", object.StateDescription())
+	}
+	return object, nil
+}
+
+// populateState copies the data from the API object to the Terraform state. Only the parameters
+// that are already in the state are refreshed, as the API also returns the default values of the
+// parameters that weren't set.
+func (r *ClusterAddOnResource) populateState(object *cmv1.AddOnInstallation, state *ClusterAddOnState) {
+	state.ID = types.String{
+		Value: object.ID(),
+	}
+	addOnID, ok := object.Addon().GetID()
+	if !ok {
+		addOnID = object.ID()
+	}
+	state.AddOn = types.String{
+		Value: addOnID,
+	}
+	state.State = types.String{
+		Value: string(object.State()),
+	}
+	if !state.Parameters.Unknown && !state.Parameters.Null {
+		values := map[string]string{}
+		object.Parameters().Each(func(parameter *cmv1.AddOnInstallationParameter) bool {
+			values[parameter.ID()] = parameter.Value()
+			return true
+		})
+		for key := range state.Parameters.Elems {
+			value, ok := values[key]
+			if !ok {
+				delete(state.Parameters.Elems, key)
+				continue
+			}
+			state.Parameters.Elems[key] = types.String{
+				Value: value,
+			}
+		}
+	}
+}
+
+func getAddOnParameters(state *ClusterAddOnState) map[string]string {
+	parameters := map[string]string{}
+	if !state.Parameters.Unknown && !state.Parameters.Null {
+		for k, v := range state.Parameters.Elems {
+			parameters[k] = v.(types.String).Value
+		}
+	}
+	return parameters
+}
+
+func buildAddOnInstallationParameters(parameters map[string]string) *cmv1.AddOnInstallationParameterListBuilder {
+	keys := make([]string, 0, len(parameters))
+	for key := range parameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	items := make([]*cmv1.AddOnInstallationParameterBuilder, len(keys))
+	for i, key := range keys {
+		items[i] = cmv1.NewAddOnInstallationParameter().ID(key).Value(parameters[key])
+	}
+	return cmv1.NewAddOnInstallationParameterList().Items(items...)
+}
+
+// validateAddOnParameters checks the parameters against their definitions in the add-on, and
+// returns an error message if they aren't valid. The current parameters are nil when the add-on
+// is being installed, and are used to check that only editable parameters are changed or removed
+// otherwise.
+func validateAddOnParameters(addOn *cmv1.AddOn, parameters, current map[string]string) (errMsg string) {
+	definitions := map[string]*cmv1.AddOnParameter{}
+	addOn.Parameters().Each(func(definition *cmv1.AddOnParameter) bool {
+		definitions[definition.ID()] = definition
+		return true
+	})
+
+	keys := make([]string, 0, len(parameters))
+	for key := range parameters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := parameters[key]
+		definition, ok := definitions[key]
+		if !ok {
+			return fmt.Sprintf("unknown parameter '%s'", key)
+		}
+		if current != nil && !definition.Editable() && current[key] != value {
+			return fmt.Sprintf("parameter '%s' can't be changed after installing the add-on", key)
+		}
+		switch definition.ValueType() {
+		case "number":
+			if _, err := strconv.ParseFloat(value, 64); err != nil {
+				return fmt.Sprintf("expected a number for parameter '%s', got '%s'", key, value)
+			}
+		case "boolean":
+			if _, err := strconv.ParseBool(value); err != nil {
+				return fmt.Sprintf("expected a boolean for parameter '%s', got '%s'", key, value)
+			}
+		case "cidr":
+			if _, _, err := net.ParseCIDR(value); err != nil {
+				return fmt.Sprintf("expected a CIDR block for parameter '%s', got '%s'", key, value)
+			}
+		}
+		if validation := definition.Validation(); validation != "" {
+			re, err := regexp.Compile(validation)
+			if err == nil && !re.MatchString(value) {
+				if definition.ValidationErrMsg() != "" {
+					return fmt.Sprintf("invalid value '%s' for parameter '%s': %s", value, key,
+						definition.ValidationErrMsg())
+				}
+				return fmt.Sprintf("expected a value matching '%s' for parameter '%s', got '%s'",
+					validation, key, value)
+			}
+		}
+		if options := definition.Options(); len(options) > 0 {
+			allowed := false
+			for _, option := range options {
+				if option.Value() == value {
+					allowed = true
+					break
+				}
+			}
+			if !allowed {
+				return fmt.Sprintf("value '%s' isn't one of the options of parameter '%s'", value, key)
+			}
+		}
+	}
+
+	if current != nil {
+		keys = make([]string, 0, len(current))
+		for key := range current {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			_, ok := parameters[key]
+			definition, defined := definitions[key]
+			if !ok && defined && !definition.Editable() {
+				return fmt.Sprintf("parameter '%s' can't be removed after installing the add-on", key)
+			}
+		}
+	}
+
+	if current == nil {
+		required := []string{}
+		for key, definition := range definitions {
+			_, ok := parameters[key]
+			if definition.Required() && !ok && definition.DefaultValue() == "" {
+				required = append(required, key)
+			}
+		}
+		if len(required) > 0 {
+			sort.Strings(required)
+			return fmt.Sprintf("missing required parameters %v", required)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,82 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+var _ = Describe("Add-on parameters validation", func() {
+	var addOn *cmv1.AddOn
+
+	BeforeEach(func() {
+		var err error
+		addOn, err = cmv1.NewAddOn().
+			ID("my-addon").
+			Parameters(cmv1.NewAddOnParameterList().Items(
+				cmv1.NewAddOnParameter().
+					ID("size").
+					ValueType("number").
+					Required(true).
+					Editable(true),
+				cmv1.NewAddOnParameter().
+					ID("tier").
+					ValueType("string").
+					Editable(false),
+			)).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Accepts changes of editable parameters", func() {
+		errMsg := validateAddOnParameters(
+			addOn,
+			map[string]string{"size": "8", "tier": "basic"},
+			map[string]string{"size": "4", "tier": "basic"},
+		)
+		Expect(errMsg).To(BeEmpty())
+	})
+
+	It("Rejects changes of parameters that aren't editable", func() {
+		errMsg := validateAddOnParameters(
+			addOn,
+			map[string]string{"size": "4", "tier": "premium"},
+			map[string]string{"size": "4", "tier": "basic"},
+		)
+		Expect(errMsg).To(Equal("parameter 'tier' can't be changed after installing the add-on"))
+	})
+
+	It("Rejects removing parameters that aren't editable", func() {
+		errMsg := validateAddOnParameters(
+			addOn,
+			map[string]string{"size": "4"},
+			map[string]string{"size": "4", "tier": "basic"},
+		)
+		Expect(errMsg).To(Equal("parameter 'tier' can't be removed after installing the add-on"))
+	})
+
+	It("Accepts removing editable parameters", func() {
+		errMsg := validateAddOnParameters(
+			addOn,
+			map[string]string{"tier": "basic"},
+			map[string]string{"size": "4", "tier": "basic"},
+		)
+		Expect(errMsg).To(BeEmpty())
+	})
+})
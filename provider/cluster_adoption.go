@@ -0,0 +1,217 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+// findAdoptableCluster returns the cluster that has the name and the AWS account of the given
+// planned cluster, or nil if there is no such cluster. Clusters that are being uninstalled are
+// ignored, as they can't be adopted.
+func findAdoptableCluster(ctx context.Context, collection *cmv1.ClustersClient,
+	planned *cmv1.Cluster) (result *cmv1.Cluster, err error) {
+	query := fmt.Sprintf(
+		"name = '%s' AND aws.account_id = '%s' AND state != 'uninstalling'",
+		strings.ReplaceAll(planned.Name(), "'", "''"),
+		strings.ReplaceAll(planned.AWS().AccountID(), "'", "''"),
+	)
+	response, err := collection.List().Search(query).Size(2).SendContext(ctx)
+	if err != nil {
+		return
+	}
+	switch response.Items().Len() {
+	case 0:
+	case 1:
+		result = response.Items().Get(0)
+	default:
+		err = fmt.Errorf(
+			"there are %d clusters with name '%s' in AWS account '%s'",
+			response.Total(), planned.Name(), planned.AWS().AccountID(),
+		)
+	}
+	return
+}
+
+// adoptCluster checks if there is an existing cluster that matches the given planned cluster and
+// can be adopted instead of creating a new one. It returns the existing cluster, or nil if there
+// is no such cluster. When there is a cluster with the same name that doesn't match the planned
+// one the differences are added to the diagnostics.
+func adoptCluster(ctx context.Context, collection *cmv1.ClustersClient, planned *cmv1.Cluster,
+	diags *diag.Diagnostics) *cmv1.Cluster {
+	existing, err := findAdoptableCluster(ctx, collection, planned)
+	if err != nil {
+		diags.AddError(
+			"Can't adopt cluster",
+			fmt.Sprintf(
+				"Can't find existing cluster with name '%s': %v",
+				planned.Name(), err,
+			),
+		)
+		return nil
+	}
+	if existing == nil {
+		return nil
+	}
+	differences := clusterDifferences(planned, existing)
+	if len(differences) > 0 {
+		diags.AddError(
+			"Can't adopt cluster",
+			fmt.Sprintf(
+				"Cluster with name '%s' already exists in AWS account '%s' with "+
+					"identifier '%s', but it doesn't match the plan:\n  - %s",
+				existing.Name(), existing.AWS().AccountID(), existing.ID(),
+				strings.Join(differences, "\n  - "),
+			),
+		)
+		return nil
+	}
+	return existing
+}
+
+// clusterDifferences compares the attributes that are explicitly set in the planned cluster with
+// the ones of the existing cluster, and returns a description of each difference. Attributes that
+// aren't set in the planned cluster are calculated by the server, so they aren't compared.
+func clusterDifferences(planned, existing *cmv1.Cluster) []string {
+	var result []string
+	compare := func(attribute string, plannedValue interface{}, set bool,
+		existingValue interface{}) {
+		if set && !reflect.DeepEqual(plannedValue, existingValue) {
+			result = append(result, fmt.Sprintf(
+				"'%s' is %s in the plan but %s in the existing cluster",
+				attribute, formatDifference(plannedValue), formatDifference(existingValue),
+			))
+		}
+	}
+	compareSubset := func(attribute string, plannedValues map[string]string, set bool,
+		existingValues map[string]string) {
+		keys := make([]string, 0, len(plannedValues))
+		for key := range plannedValues {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			compare(fmt.Sprintf("%s.%s", attribute, key), plannedValues[key], set,
+				existingValues[key])
+		}
+	}
+
+	compare("cloud_region", planned.Region().ID(), true, existing.Region().ID())
+	value, ok := planned.GetMultiAZ()
+	compare("multi_az", value, ok, existing.MultiAZ())
+	text, ok := planned.Version().GetID()
+	compare("version", text, ok, existing.Version().ID())
+	text, ok = planned.GetExternalID()
+	compare("external_id", text, ok, existing.ExternalID())
+	value, ok = planned.GetEtcdEncryption()
+	compare("etcd_encryption", value, ok, existing.EtcdEncryption())
+	value, ok = planned.GetFIPS()
+	compare("fips", value, ok, existing.FIPS())
+	value, ok = planned.GetDisableUserWorkloadMonitoring()
+	compare("disable_workload_monitoring", value, ok,
+		existing.DisableUserWorkloadMonitoring())
+	values, ok := planned.GetProperties()
+	compareSubset("properties", values, ok, existing.Properties())
+
+	// Nodes:
+	text, ok = planned.Nodes().ComputeMachineType().GetID()
+	compare("compute_machine_type", text, ok, existing.Nodes().ComputeMachineType().ID())
+	number, ok := planned.Nodes().GetCompute()
+	compare("replicas", number, ok, existing.Nodes().Compute())
+	number, ok = planned.Nodes().AutoscaleCompute().GetMinReplicas()
+	compare("min_replicas", number, ok, existing.Nodes().AutoscaleCompute().MinReplicas())
+	number, ok = planned.Nodes().AutoscaleCompute().GetMaxReplicas()
+	compare("max_replicas", number, ok, existing.Nodes().AutoscaleCompute().MaxReplicas())
+	list, ok := planned.Nodes().GetAvailabilityZones()
+	compare("availability_zones", sortedCopy(list), ok,
+		sortedCopy(existing.Nodes().AvailabilityZones()))
+	values, ok = planned.Nodes().GetComputeLabels()
+	compare("default_mp_labels", values, ok, existing.Nodes().ComputeLabels())
+
+	// AWS:
+	list, ok = planned.AWS().GetSubnetIDs()
+	compare("aws_subnet_ids", sortedCopy(list), ok, sortedCopy(existing.AWS().SubnetIDs()))
+	value, ok = planned.AWS().GetPrivateLink()
+	compare("aws_private_link", value, ok, existing.AWS().PrivateLink())
+	text, ok = planned.AWS().GetKMSKeyArn()
+	compare("kms_key_arn", text, ok, existing.AWS().KMSKeyArn())
+	values, ok = planned.AWS().GetTags()
+	compareSubset("tags", values, ok, existing.AWS().Tags())
+	text, ok = planned.AWS().STS().GetRoleARN()
+	compare("sts.role_arn", text, ok, existing.AWS().STS().RoleARN())
+	text, ok = planned.AWS().STS().GetSupportRoleARN()
+	compare("sts.support_role_arn", text, ok, existing.AWS().STS().SupportRoleARN())
+	text, ok = planned.AWS().STS().GetOperatorRolePrefix()
+	compare("sts.operator_role_prefix", text, ok, existing.AWS().STS().OperatorRolePrefix())
+	text, ok = planned.AWS().STS().OidcConfig().GetID()
+	compare("sts.oidc_config_id", text, ok, existing.AWS().STS().OidcConfig().ID())
+	text, ok = planned.AWS().STS().InstanceIAMRoles().GetMasterRoleARN()
+	compare("sts.instance_iam_roles.master_role_arn", text, ok,
+		existing.AWS().STS().InstanceIAMRoles().MasterRoleARN())
+	text, ok = planned.AWS().STS().InstanceIAMRoles().GetWorkerRoleARN()
+	compare("sts.instance_iam_roles.worker_role_arn", text, ok,
+		existing.AWS().STS().InstanceIAMRoles().WorkerRoleARN())
+
+	// Network:
+	text, ok = planned.Network().GetMachineCIDR()
+	compare("machine_cidr", text, ok, existing.Network().MachineCIDR())
+	text, ok = planned.Network().GetServiceCIDR()
+	compare("service_cidr", text, ok, existing.Network().ServiceCIDR())
+	text, ok = planned.Network().GetPodCIDR()
+	compare("pod_cidr", text, ok, existing.Network().PodCIDR())
+	number, ok = planned.Network().GetHostPrefix()
+	compare("host_prefix", number, ok, existing.Network().HostPrefix())
+
+	// Proxy:
+	text, ok = planned.Proxy().GetHTTPProxy()
+	compare("proxy.http_proxy", text, ok, existing.Proxy().HTTPProxy())
+	text, ok = planned.Proxy().GetHTTPSProxy()
+	compare("proxy.https_proxy", text, ok, existing.Proxy().HTTPSProxy())
+
+	return result
+}
+
+// formatDifference formats a value for the description of a difference between clusters.
+func formatDifference(value interface{}) string {
+	switch typed := value.(type) {
+	case string:
+		return fmt.Sprintf("'%s'", typed)
+	case []string:
+		if len(typed) == 0 {
+			return "[]"
+		}
+		return fmt.Sprintf("['%s']", strings.Join(typed, "', '"))
+	default:
+		return fmt.Sprintf("%v", typed)
+	}
+}
+
+// sortedCopy returns a sorted copy of the given list, so that lists can be compared ignoring the
+// order. Nil and empty lists are both returned as empty lists.
+func sortedCopy(list []string) []string {
+	result := make([]string, len(list))
+	copy(result, list)
+	sort.Strings(result)
+	return result
+}
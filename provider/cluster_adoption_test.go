@@ -0,0 +1,91 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+var _ = Describe("Cluster adoption", func() {
+	build := func(builder *cmv1.ClusterBuilder) *cmv1.Cluster {
+		object, err := builder.Build()
+		Expect(err).ToNot(HaveOccurred())
+		return object
+	}
+
+	planned := build(cmv1.NewCluster().
+		Name("my-cluster").
+		Region(cmv1.NewCloudRegion().ID("us-east-1")).
+		MultiAZ(true).
+		Properties(map[string]string{
+			"owner": "me",
+		}).
+		Nodes(cmv1.NewClusterNodes().
+			AvailabilityZones("us-east-1b", "us-east-1a").
+			ComputeMachineType(cmv1.NewMachineType().ID("m5.xlarge"))).
+		AWS(cmv1.NewAWS().
+			AccountID("123").
+			SubnetIDs("subnet-1", "subnet-2").
+			STS(cmv1.NewSTS().RoleARN("arn:aws:iam::123:role/installer"))))
+
+	It("Accepts clusters that match the plan", func() {
+		existing := build(cmv1.NewCluster().
+			ID("456").
+			Name("my-cluster").
+			Region(cmv1.NewCloudRegion().ID("us-east-1")).
+			MultiAZ(true).
+			Properties(map[string]string{
+				"owner":            "me",
+				"rosa_creator_arn": "arn:aws:iam::123:user/me",
+			}).
+			Nodes(cmv1.NewClusterNodes().
+				Compute(3).
+				AvailabilityZones("us-east-1a", "us-east-1b").
+				ComputeMachineType(cmv1.NewMachineType().ID("m5.xlarge"))).
+			AWS(cmv1.NewAWS().
+				AccountID("123").
+				SubnetIDs("subnet-2", "subnet-1").
+				STS(cmv1.NewSTS().RoleARN("arn:aws:iam::123:role/installer"))).
+			Network(cmv1.NewNetwork().MachineCIDR("10.0.0.0/16")))
+		Expect(clusterDifferences(planned, existing)).To(BeEmpty())
+	})
+
+	It("Reports the differences with the plan", func() {
+		existing := build(cmv1.NewCluster().
+			ID("456").
+			Name("my-cluster").
+			Region(cmv1.NewCloudRegion().ID("us-east-1")).
+			MultiAZ(false).
+			Nodes(cmv1.NewClusterNodes().
+				AvailabilityZones("us-east-1a").
+				ComputeMachineType(cmv1.NewMachineType().ID("m5.xlarge"))).
+			AWS(cmv1.NewAWS().
+				AccountID("123").
+				SubnetIDs("subnet-1", "subnet-2").
+				STS(cmv1.NewSTS().RoleARN("arn:aws:iam::123:role/other"))))
+		Expect(clusterDifferences(planned, existing)).To(Equal([]string{
+			"'multi_az' is true in the plan but false in the existing cluster",
+			"'properties.owner' is 'me' in the plan but '' in the existing cluster",
+			"'availability_zones' is ['us-east-1a', 'us-east-1b'] in the plan but " +
+				"['us-east-1a'] in the existing cluster",
+			"'sts.role_arn' is 'arn:aws:iam::123:role/installer' in the plan but " +
+				"'arn:aws:iam::123:role/other' in the existing cluster",
+		}))
+	})
+})
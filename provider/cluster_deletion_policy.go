@@ -0,0 +1,81 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// Values of the 'deletion_policy' attribute of clusters:
+const (
+	deletionPolicyDelete  = "delete"
+	deletionPolicyOrphan  = "orphan"
+	deletionPolicyProtect = "protect"
+)
+
+var deletionPolicies = []string{
+	deletionPolicyDelete,
+	deletionPolicyOrphan,
+	deletionPolicyProtect,
+}
+
+// deletionPolicyAttribute returns the schema of the 'deletion_policy' attribute, shared by the
+// cluster resources.
+func deletionPolicyAttribute() tfsdk.Attribute {
+	return tfsdk.Attribute{
+		Description: "What to do with the cluster when the resource is destroyed. With 'delete' the " +
+			"cluster is deleted, with 'orphan' it is only removed from the Terraform state, and " +
+			"with 'protect' destroying the resource fails. Default value is 'delete'.",
+		Type:     types.StringType,
+		Optional: true,
+		Validators: []tfsdk.AttributeValidator{
+			stringAttributeValidator("Validate deletion policy", func(value string) string {
+				for _, policy := range deletionPolicies {
+					if value == policy {
+						return ""
+					}
+				}
+				return fmt.Sprintf("Expected one of '%s' for 'deletion_policy', got '%s'",
+					strings.Join(deletionPolicies, "', '"), value)
+			}),
+		},
+	}
+}
+
+// getDeletionPolicy returns the deletion policy of the state, or 'delete' if it isn't set.
+func getDeletionPolicy(value types.String) string {
+	if value.Unknown || value.Null || value.Value == "" {
+		return deletionPolicyDelete
+	}
+	return value.Value
+}
+
+// protectedClusterError returns the summary and the detail of the error reported when destroying
+// a cluster that has the 'protect' deletion policy.
+func protectedClusterError(id string) (summary, detail string) {
+	summary = "Can't delete protected cluster"
+	detail = fmt.Sprintf(
+		"Can't delete cluster with identifier '%s' because its 'deletion_policy' is '%s', "+
+			"change it to '%s' or '%s' and apply that change before destroying the resource",
+		id, deletionPolicyProtect, deletionPolicyDelete, deletionPolicyOrphan,
+	)
+	return
+}
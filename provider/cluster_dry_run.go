@@ -0,0 +1,193 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift-online/ocm-sdk-go/errors"
+	"github.com/openshift-online/ocm-sdk-go/logging"
+)
+
+// dryRunErrorAttribute associates the words that appear in the errors returned by the dry run of
+// a cluster with the attributes that most likely caused them. The first attribute that exists in
+// the schema of the resource is used.
+type dryRunErrorAttribute struct {
+	keywords   []string
+	attributes []string
+}
+
+// dryRunErrorAttributes are checked in order, so more specific keywords need to go first.
+var dryRunErrorAttributes = []dryRunErrorAttribute{
+	{
+		keywords:   []string{"machine cidr", "machine_cidr", "machine network"},
+		attributes: []string{"machine_cidr"},
+	},
+	{
+		keywords:   []string{"service cidr", "service_cidr", "service network"},
+		attributes: []string{"service_cidr"},
+	},
+	{
+		keywords:   []string{"pod cidr", "pod_cidr", "cluster network"},
+		attributes: []string{"pod_cidr"},
+	},
+	{
+		keywords:   []string{"host prefix", "host_prefix"},
+		attributes: []string{"host_prefix"},
+	},
+	{
+		keywords:   []string{"subnet"},
+		attributes: []string{"aws_subnet_ids"},
+	},
+	{
+		keywords:   []string{"availability zone"},
+		attributes: []string{"availability_zones"},
+	},
+	{
+		keywords:   []string{"kms"},
+		attributes: []string{"kms_key_arn"},
+	},
+	{
+		keywords:   []string{"billing account"},
+		attributes: []string{"aws_billing_account_id"},
+	},
+	{
+		keywords:   []string{"private link", "privatelink"},
+		attributes: []string{"aws_private_link"},
+	},
+	{
+		keywords:   []string{"machine type", "instance type"},
+		attributes: []string{"compute_machine_type"},
+	},
+	{
+		keywords:   []string{"replicas", "compute nodes"},
+		attributes: []string{"replicas", "compute_nodes"},
+	},
+	{
+		keywords:   []string{"role arn", "installer role", "support role", "operator role", "oidc"},
+		attributes: []string{"sts"},
+	},
+	{
+		keywords:   []string{"version"},
+		attributes: []string{"version"},
+	},
+	{
+		keywords:   []string{"region"},
+		attributes: []string{"cloud_region"},
+	},
+	{
+		keywords:   []string{"multi-az", "multi az", "multiaz"},
+		attributes: []string{"multi_az"},
+	},
+	{
+		keywords:   []string{"cluster name"},
+		attributes: []string{"name"},
+	},
+}
+
+// shouldDryRunCluster checks if the plan describes a cluster that will be created and that can
+// be sent to the API in dry run mode. Clusters whose configuration depends on values that are
+// still unknown, for example the identifiers of subnets created in the same apply, can't be
+// checked.
+func shouldDryRunCluster(ctx context.Context, logger logging.Logger,
+	request tfsdk.ModifyResourcePlanRequest) bool {
+	if !request.State.Raw.IsNull() || request.Plan.Raw.IsNull() {
+		return false
+	}
+	if !request.Config.Raw.IsFullyKnown() {
+		logger.Debug(ctx, "Skipping dry run of cluster because its configuration contains unknown values")
+		return false
+	}
+	return true
+}
+
+// dryRunCluster sends the given cluster to the API in dry run mode, so that it is validated but
+// not created. Validation errors are added to the diagnostics, associated to the attribute that
+// caused them when it can be guessed from the error.
+func dryRunCluster(ctx context.Context, collection *cmv1.ClustersClient, object *cmv1.Cluster,
+	schema tfsdk.Schema, diags *diag.Diagnostics) {
+	response, err := collection.Add().Parameter("dryRun", true).Body(object).SendContext(ctx)
+	var status int
+	var reason string
+	switch typed := err.(type) {
+	case nil:
+		status = response.Status()
+	case *errors.Error:
+		status = typed.Status()
+		reason = typed.Reason()
+	}
+	if err == nil && status < http.StatusBadRequest {
+		return
+	}
+
+	// Errors that aren't caused by the cluster, like connection errors or internal errors of
+	// the server, don't mean that the cluster is invalid, so they shouldn't block the plan:
+	if status == 0 || status >= http.StatusInternalServerError {
+		diags.AddWarning(
+			"Can't validate cluster",
+			fmt.Sprintf(
+				"Can't validate cluster with name '%s' in dry run mode: %v",
+				object.Name(), err,
+			),
+		)
+		return
+	}
+
+	summary := "Invalid cluster"
+	detail := fmt.Sprintf(
+		"Cluster with name '%s' was rejected by the server with status code %d",
+		object.Name(), status,
+	)
+	if reason != "" {
+		detail = fmt.Sprintf(
+			"Cluster with name '%s' was rejected by the server: %s",
+			object.Name(), reason,
+		)
+	}
+	path := dryRunErrorPath(reason, schema)
+	if path != nil {
+		diags.AddAttributeError(path, summary, detail)
+	} else {
+		diags.AddError(summary, detail)
+	}
+}
+
+// dryRunErrorPath returns the path of the attribute that most likely caused the given dry run
+// error, or nil if it can't be guessed.
+func dryRunErrorPath(reason string, schema tfsdk.Schema) *tftypes.AttributePath {
+	reason = strings.ToLower(reason)
+	for _, candidate := range dryRunErrorAttributes {
+		for _, keyword := range candidate.keywords {
+			if !strings.Contains(reason, keyword) {
+				continue
+			}
+			for _, attribute := range candidate.attributes {
+				if _, ok := schema.Attributes[attribute]; ok {
+					return tftypes.NewAttributePath().WithAttributeName(attribute)
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+)
+
+var _ = Describe("Cluster dry run errors", func() {
+	var schema tfsdk.Schema
+
+	BeforeEach(func() {
+		var diags diag.Diagnostics
+		schema, diags = (&ClusterRosaClassicResourceType{}).GetSchema(context.Background())
+		Expect(diags.HasError()).To(BeFalse())
+	})
+
+	It("Associates subnet errors to the subnet identifiers", func() {
+		path := dryRunErrorPath("The subnet 'subnet-1' doesn't exist", schema)
+		Expect(path).To(Equal(tftypes.NewAttributePath().WithAttributeName("aws_subnet_ids")))
+	})
+
+	It("Prefers the more specific keywords", func() {
+		path := dryRunErrorPath("Machine CIDR '10.0.0.0/16' overlaps with the subnet", schema)
+		Expect(path).To(Equal(tftypes.NewAttributePath().WithAttributeName("machine_cidr")))
+	})
+
+	It("Uses the attribute that exists in the schema", func() {
+		path := dryRunErrorPath("Number of compute nodes must be at least 2", schema)
+		Expect(path).To(Equal(tftypes.NewAttributePath().WithAttributeName("replicas")))
+	})
+
+	It("Returns nil for errors not associated to an attribute", func() {
+		path := dryRunErrorPath("Insufficient quota for the cluster", schema)
+		Expect(path).To(BeNil())
+	})
+})
@@ -0,0 +1,124 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift-online/ocm-sdk-go/logging"
+)
+
+// hibernatingAttribute returns the schema of the attribute used to hibernate and resume a cluster.
+func hibernatingAttribute() tfsdk.Attribute {
+	return tfsdk.Attribute{
+		Description: "Set to 'true' to hibernate the cluster and to 'false' to resume it. " +
+			"The apply waits till the cluster is hibernating or ready again. Clusters " +
+			"can't be created hibernating.",
+		Type:     types.BoolType,
+		Optional: true,
+		Computed: true,
+	}
+}
+
+// hibernatingState returns the value of the 'hibernating' attribute for the given cluster. Clusters
+// that are powering down are considered hibernating, and clusters that are resuming aren't.
+func hibernatingState(object *cmv1.Cluster) types.Bool {
+	switch object.State() {
+	case cmv1.ClusterStateHibernating, cmv1.ClusterStatePoweringDown:
+		return types.Bool{
+			Value: true,
+		}
+	default:
+		return types.Bool{
+			Value: false,
+		}
+	}
+}
+
+// checkNotCreatedHibernating adds an error to the diagnostics if the given plan of a cluster that
+// is being created requests it to be hibernating. Clusters can't be created hibernating, they need
+// to be hibernated once they are ready.
+func checkNotCreatedHibernating(ctx context.Context, plan tfsdk.Plan, diags *diag.Diagnostics) {
+	hibernating := types.Bool{}
+	getDiags := plan.GetAttribute(ctx, tftypes.NewAttributePath().WithAttributeName("hibernating"),
+		&hibernating)
+	if getDiags.HasError() || hibernating.Unknown || hibernating.Null || !hibernating.Value {
+		return
+	}
+	name := types.String{}
+	plan.GetAttribute(ctx, tftypes.NewAttributePath().WithAttributeName("name"), &name)
+	diags.AddError(
+		"Can't create cluster",
+		fmt.Sprintf(
+			"Can't create cluster with name '%s' hibernating, create it first and then "+
+				"set 'hibernating' to true",
+			name.Value,
+		),
+	)
+}
+
+// shouldChangeHibernation checks if the plan requests a different value for the 'hibernating'
+// attribute than the one in the state.
+func shouldChangeHibernation(state, plan types.Bool) (hibernate bool, ok bool) {
+	if plan.Unknown || plan.Null {
+		return
+	}
+	if state.Unknown || state.Null || plan.Value != state.Value {
+		hibernate = plan.Value
+		ok = true
+	}
+	return
+}
+
+// changeClusterHibernation hibernates or resumes the cluster with the given identifier, and waits
+// till the cluster is hibernating or ready. It returns the last version of the cluster retrieved
+// from the server.
+func changeClusterHibernation(ctx context.Context, collection *cmv1.ClustersClient,
+	clusterID string, hibernate bool, logger logging.Logger) (*cmv1.Cluster, error) {
+	resource := collection.Cluster(clusterID)
+	target := &clusterWaitTarget{}
+	ctx = withClusterLogField(ctx, clusterID)
+	var err error
+	if hibernate {
+		logger.Info(ctx, "Hibernating cluster")
+		_, err = resource.Hibernate().SendContext(ctx)
+		target.states = []cmv1.ClusterState{
+			cmv1.ClusterStateHibernating,
+		}
+	} else {
+		logger.Info(ctx, "Resuming cluster")
+		_, err = resource.Resume().SendContext(ctx)
+		target.states = readyClusterWaitTarget.states
+	}
+	if err != nil {
+		return nil, err
+	}
+	object, err := isClusterReady(clusterID, ctx, defaultTimeoutInMinutes, target, collection, logger)
+	if err != nil {
+		return nil, err
+	}
+	if object.State() == cmv1.ClusterStateError {
+		return object, fmt.Errorf("cluster is in error state")
+	}
+	return object, nil
+}
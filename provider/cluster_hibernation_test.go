@@ -0,0 +1,108 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+var _ = Describe("Cluster hibernation", func() {
+	build := func(state cmv1.ClusterState) *cmv1.Cluster {
+		object, err := cmv1.NewCluster().State(state).Build()
+		Expect(err).ToNot(HaveOccurred())
+		return object
+	}
+
+	It("Considers hibernating and powering down clusters hibernating", func() {
+		Expect(hibernatingState(build(cmv1.ClusterStateHibernating)).Value).To(BeTrue())
+		Expect(hibernatingState(build(cmv1.ClusterStatePoweringDown)).Value).To(BeTrue())
+	})
+
+	It("Doesn't consider resuming and ready clusters hibernating", func() {
+		Expect(hibernatingState(build(cmv1.ClusterStateResuming)).Value).To(BeFalse())
+		Expect(hibernatingState(build(cmv1.ClusterStateReady)).Value).To(BeFalse())
+	})
+
+	It("Changes the hibernation when the plan differs from the state", func() {
+		hibernate, ok := shouldChangeHibernation(types.Bool{Value: false}, types.Bool{Value: true})
+		Expect(ok).To(BeTrue())
+		Expect(hibernate).To(BeTrue())
+		hibernate, ok = shouldChangeHibernation(types.Bool{Value: true}, types.Bool{Value: false})
+		Expect(ok).To(BeTrue())
+		Expect(hibernate).To(BeFalse())
+	})
+
+	It("Doesn't change the hibernation when the plan doesn't set it", func() {
+		_, ok := shouldChangeHibernation(types.Bool{Value: true}, types.Bool{Unknown: true})
+		Expect(ok).To(BeFalse())
+		_, ok = shouldChangeHibernation(types.Bool{Value: true}, types.Bool{Null: true})
+		Expect(ok).To(BeFalse())
+		_, ok = shouldChangeHibernation(types.Bool{Value: true}, types.Bool{Value: true})
+		Expect(ok).To(BeFalse())
+	})
+
+	Context("Creation of hibernating clusters", func() {
+		schema := tfsdk.Schema{
+			Attributes: map[string]tfsdk.Attribute{
+				"name": {
+					Type:     types.StringType,
+					Required: true,
+				},
+				"hibernating": hibernatingAttribute(),
+			},
+		}
+		objectType := tftypes.Object{
+			AttributeTypes: map[string]tftypes.Type{
+				"name":        tftypes.String,
+				"hibernating": tftypes.Bool,
+			},
+		}
+
+		check := func(hibernating interface{}) diag.Diagnostics {
+			plan := tfsdk.Plan{
+				Schema: schema,
+				Raw: tftypes.NewValue(objectType, map[string]tftypes.Value{
+					"name":        tftypes.NewValue(tftypes.String, "my-cluster"),
+					"hibernating": tftypes.NewValue(tftypes.Bool, hibernating),
+				}),
+			}
+			diags := diag.Diagnostics{}
+			checkNotCreatedHibernating(context.Background(), plan, &diags)
+			return diags
+		}
+
+		It("Rejects clusters created hibernating", func() {
+			diags := check(true)
+			Expect(diags.HasError()).To(BeTrue())
+			Expect(diags[0].Detail()).To(ContainSubstring("my-cluster"))
+		})
+
+		It("Accepts clusters that aren't hibernating", func() {
+			Expect(check(false).HasError()).To(BeFalse())
+			Expect(check(nil).HasError()).To(BeFalse())
+			Expect(check(tftypes.UnknownValue).HasError()).To(BeFalse())
+		})
+	})
+})
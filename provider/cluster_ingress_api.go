@@ -0,0 +1,137 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	sdk "github.com/openshift-online/ocm-sdk-go"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	ocm_errors "github.com/openshift-online/ocm-sdk-go/errors"
+)
+
+// Values of the route wildcard policy of ingresses:
+const (
+	wildcardPolicyDisallowed = "WildcardsDisallowed"
+	wildcardPolicyAllowed    = "WildcardsAllowed"
+)
+
+var wildcardPolicies = []string{
+	wildcardPolicyDisallowed,
+	wildcardPolicyAllowed,
+}
+
+// Values of the route namespace ownership policy of ingresses:
+const (
+	namespaceOwnershipPolicyStrict                = "Strict"
+	namespaceOwnershipPolicyInterNamespaceAllowed = "InterNamespaceAllowed"
+)
+
+var namespaceOwnershipPolicies = []string{
+	namespaceOwnershipPolicyStrict,
+	namespaceOwnershipPolicyInterNamespaceAllowed,
+}
+
+// ingressPolicies contains the attributes of ingresses that the version of the SDK used by the
+// provider doesn't support yet.
+type ingressPolicies struct {
+	ExcludedNamespaces            []string `json:"excluded_namespaces,omitempty"`
+	RouteWildcardPolicy           string   `json:"route_wildcard_policy,omitempty"`
+	RouteNamespaceOwnershipPolicy string   `json:"route_namespace_ownership_policy,omitempty"`
+}
+
+// clusterIngress is an ingress of a cluster, as returned by the server.
+type clusterIngress struct {
+	object   *cmv1.Ingress
+	policies *ingressPolicies
+}
+
+// ingressClient retrieves and updates the ingresses of clusters. The SDK doesn't support the
+// excluded namespaces and the route policies of ingresses, so the requests are sent with the raw
+// methods of the connection, and the responses are parsed both with the SDK types and as plain
+// JSON.
+type ingressClient struct {
+	connection *sdk.Connection
+}
+
+// get retrieves the ingress with the given identifier.
+func (c *ingressClient) get(ctx context.Context, clusterID, ingressID string) (*clusterIngress,
+	error) {
+	response, err := c.connection.Get().
+		Path(ingressPath(clusterID, ingressID)).
+		SendContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return parseIngressResponse(response)
+}
+
+// update sends the given patch to the ingress with the given identifier and returns the result.
+func (c *ingressClient) update(ctx context.Context, clusterID, ingressID string,
+	patch map[string]interface{}) (*clusterIngress, error) {
+	body, err := json.Marshal(patch)
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.connection.Patch().
+		Path(ingressPath(clusterID, ingressID)).
+		Header("Content-Type", "application/json").
+		Bytes(body).
+		SendContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return parseIngressResponse(response)
+}
+
+func ingressPath(clusterID, ingressID string) string {
+	return fmt.Sprintf(
+		"/api/clusters_mgmt/v1/clusters/%s/ingresses/%s",
+		url.PathEscape(clusterID), url.PathEscape(ingressID),
+	)
+}
+
+// parseIngressResponse converts the given response into an ingress, or into an error if the
+// server returned an error. Errors are converted into the same type returned by the SDK clients,
+// so that they can be checked with the usual helpers.
+func parseIngressResponse(response *sdk.Response) (*clusterIngress, error) {
+	body := response.Bytes()
+	if response.Status() >= http.StatusBadRequest {
+		sdkErr, err := ocm_errors.UnmarshalErrorStatus(body, response.Status())
+		if err != nil {
+			return nil, fmt.Errorf("unexpected response status %d", response.Status())
+		}
+		return nil, sdkErr
+	}
+	object, err := cmv1.UnmarshalIngress(body)
+	if err != nil {
+		return nil, err
+	}
+	policies := &ingressPolicies{}
+	err = json.Unmarshal(body, policies)
+	if err != nil {
+		return nil, err
+	}
+	return &clusterIngress{
+		object:   object,
+		policies: policies,
+	}, nil
+}
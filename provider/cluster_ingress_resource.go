@@ -0,0 +1,496 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/terraform-redhat/terraform-provider-ocm/provider/common"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift-online/ocm-sdk-go/logging"
+)
+
+const (
+	clusterIngressImportIDFormat = "<cluster_id>,<ingress_id>"
+)
+
+type ClusterIngressResourceType struct {
+	logger logging.Logger
+}
+
+type ClusterIngressResource struct {
+	logger     logging.Logger
+	collection *cmv1.ClustersClient
+	ingresses  *ingressClient
+}
+
+func (t *ClusterIngressResourceType) GetSchema(ctx context.Context) (result tfsdk.Schema,
+	diags diag.Diagnostics) {
+	result = tfsdk.Schema{
+		Description: "Default ingress of a cluster. The ingress is created together with the " +
+			"cluster, this resource adopts it, updates it, and restores its default settings " +
+			"when it is destroyed. Attributes that aren't set keep the value of the cluster.",
+		Attributes: map[string]tfsdk.Attribute{
+			"cluster": {
+				Description: "Identifier of the cluster.",
+				Type:        types.StringType,
+				Required:    true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
+			"id": {
+				Description: "Unique identifier of the ingress.",
+				Type:        types.StringType,
+				Computed:    true,
+			},
+			"dns_name": {
+				Description: "DNS name of the ingress.",
+				Type:        types.StringType,
+				Computed:    true,
+			},
+			"private": {
+				Description: "Restricts the ingress to the private network of the cluster. " +
+					"Default value is 'false'.",
+				Type:     types.BoolType,
+				Optional: true,
+				Computed: true,
+			},
+			"route_selectors": {
+				Description: "Labels that the routes need to have to be admitted by the " +
+					"ingress. By default all the routes are admitted.",
+				Type: types.MapType{
+					ElemType: types.StringType,
+				},
+				Optional: true,
+				Computed: true,
+			},
+			"excluded_namespaces": {
+				Description: "Namespaces whose routes aren't admitted by the ingress.",
+				Type: types.ListType{
+					ElemType: types.StringType,
+				},
+				Optional: true,
+				Computed: true,
+			},
+			"wildcard_policy": {
+				Description: "Whether routes with wildcard hosts are admitted, one of " +
+					"'WildcardsDisallowed' and 'WildcardsAllowed'. Default value is " +
+					"'WildcardsDisallowed'.",
+				Type:     types.StringType,
+				Optional: true,
+				Computed: true,
+				Validators: []tfsdk.AttributeValidator{
+					stringAttributeValidator("Validate wildcard policy", func(value string) string {
+						return checkIngressPolicy("wildcard_policy", wildcardPolicies, value)
+					}),
+				},
+			},
+			"namespace_ownership_policy": {
+				Description: "Whether routes in different namespaces can claim the same host, " +
+					"one of 'Strict' and 'InterNamespaceAllowed'. Default value is 'Strict'.",
+				Type:     types.StringType,
+				Optional: true,
+				Computed: true,
+				Validators: []tfsdk.AttributeValidator{
+					stringAttributeValidator("Validate namespace ownership policy", func(value string) string {
+						return checkIngressPolicy("namespace_ownership_policy",
+							namespaceOwnershipPolicies, value)
+					}),
+				},
+			},
+		},
+	}
+	return
+}
+
+// checkIngressPolicy checks that the value of a policy attribute is one of the allowed ones, and
+// returns the error message if it isn't.
+func checkIngressPolicy(name string, allowed []string, value string) string {
+	for _, policy := range allowed {
+		if value == policy {
+			return ""
+		}
+	}
+	return fmt.Sprintf("Expected one of '%s' for '%s', got '%s'",
+		strings.Join(allowed, "', '"), name, value)
+}
+
+func (t *ClusterIngressResourceType) NewResource(ctx context.Context,
+	p tfsdk.Provider) (result tfsdk.Resource, diags diag.Diagnostics) {
+	// Cast the provider interface to the specific implementation: use it directly when needed.
+	parent := p.(*Provider)
+
+	// Get the collection of clusters:
+	collection := parent.connection.ClustersMgmt().V1().Clusters()
+
+	// Create the resource:
+	result = &ClusterIngressResource{
+		logger:     parent.logger,
+		collection: collection,
+		ingresses: &ingressClient{
+			connection: parent.connection,
+		},
+	}
+
+	return
+}
+
+func (r *ClusterIngressResource) Create(ctx context.Context,
+	request tfsdk.CreateResourceRequest, response *tfsdk.CreateResourceResponse) {
+	// Get the plan:
+	state := &ClusterIngressState{}
+	diags := request.Plan.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+
+	// Wait till the cluster is ready:
+	resource := r.collection.Cluster(state.Cluster.Value)
+	pollCtx, cancel := context.WithTimeout(ctx, 1*time.Hour)
+	defer cancel()
+	_, err := resource.Poll().
+		Interval(30 * time.Second).
+		Predicate(func(get *cmv1.ClusterGetResponse) bool {
+			return get.Body().State() == cmv1.ClusterStateReady
+		}).
+		StartContext(pollCtx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't poll cluster state",
+			fmt.Sprintf(
+				"Can't poll state of cluster with identifier '%s': %v",
+				state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+
+	// Find the default ingress:
+	list, err := resource.Ingresses().List().SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't find default ingress",
+			fmt.Sprintf(
+				"Can't list ingresses of cluster '%s': %v",
+				state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+	var ingressID string
+	list.Items().Each(func(item *cmv1.Ingress) bool {
+		if item.Default() {
+			ingressID = item.ID()
+			return false
+		}
+		return true
+	})
+	if ingressID == "" {
+		response.Diagnostics.AddError(
+			"Can't find default ingress",
+			fmt.Sprintf(
+				"Cluster '%s' doesn't have a default ingress",
+				state.Cluster.Value,
+			),
+		)
+		return
+	}
+	r.logger.Info(withObjectLogField(ctx, ingressID), "Adopting default ingress of cluster")
+
+	// Apply the configured settings to the ingress:
+	ingress, err := r.ingresses.update(ctx, state.Cluster.Value, ingressID,
+		ingressPatch(nil, state))
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't update ingress",
+			fmt.Sprintf(
+				"Can't update ingress '%s' of cluster '%s': %v",
+				ingressID, state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+
+	// Save the state:
+	populateClusterIngressState(ingress, state)
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *ClusterIngressResource) Read(ctx context.Context, request tfsdk.ReadResourceRequest,
+	response *tfsdk.ReadResourceResponse) {
+	// Get the current state:
+	state := &ClusterIngressState{}
+	diags := request.State.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+	ctx = withObjectLogField(ctx, state.ID.Value)
+
+	// Find the ingress:
+	ingress, err := r.ingresses.get(ctx, state.Cluster.Value, state.ID.Value)
+	if err != nil {
+		if common.IsStatusNotFound(err) {
+			r.logger.Warn(ctx, "Ingress wasn't found, removing it from the state")
+			response.State.RemoveResource(ctx)
+			return
+		}
+		response.Diagnostics.AddError(
+			"Can't find ingress",
+			fmt.Sprintf(
+				"Can't find ingress with identifier '%s' for "+
+					"cluster '%s': %v",
+				state.ID.Value, state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+
+	// Save the state:
+	populateClusterIngressState(ingress, state)
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *ClusterIngressResource) Update(ctx context.Context, request tfsdk.UpdateResourceRequest,
+	response *tfsdk.UpdateResourceResponse) {
+	var diags diag.Diagnostics
+
+	// Get the state:
+	state := &ClusterIngressState{}
+	diags = request.State.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+	ctx = withObjectLogField(ctx, state.ID.Value)
+
+	// Get the plan:
+	plan := &ClusterIngressState{}
+	diags = request.Plan.Get(ctx, plan)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// Send the changes:
+	ingress, err := r.ingresses.update(ctx, state.Cluster.Value, state.ID.Value,
+		ingressPatch(state, plan))
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't update ingress",
+			fmt.Sprintf(
+				"Can't update ingress '%s' of cluster '%s': %v",
+				state.ID.Value, state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+
+	// Save the state:
+	populateClusterIngressState(ingress, state)
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *ClusterIngressResource) Delete(ctx context.Context, request tfsdk.DeleteResourceRequest,
+	response *tfsdk.DeleteResourceResponse) {
+	// Get the state:
+	state := &ClusterIngressState{}
+	diags := request.State.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+	ctx = withObjectLogField(ctx, state.ID.Value)
+
+	// The default ingress can't be deleted, so restore its default settings instead:
+	_, err := r.ingresses.update(ctx, state.Cluster.Value, state.ID.Value, defaultIngressPatch())
+	if err != nil {
+		if common.IsStatusNotFound(err) {
+			r.logger.Warn(ctx, "Ingress wasn't found, removing it from the state")
+			response.State.RemoveResource(ctx)
+			return
+		}
+		response.Diagnostics.AddError(
+			"Can't restore ingress",
+			fmt.Sprintf(
+				"Can't restore default settings of ingress with identifier '%s' for "+
+					"cluster '%s': %v",
+				state.ID.Value, state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+
+	// Remove the state:
+	response.State.RemoveResource(ctx)
+}
+
+func (r *ClusterIngressResource) ImportState(ctx context.Context, request tfsdk.ImportResourceStateRequest,
+	response *tfsdk.ImportResourceStateResponse) {
+	fields, err := common.SplitImportID(request.ID, clusterIngressImportIDFormat)
+	if err != nil {
+		response.Diagnostics.AddError("Invalid import identifier", err.Error())
+		return
+	}
+	clusterID := fields[0]
+	ingressID := fields[1]
+
+	// Try to retrieve the object:
+	ingress, err := r.ingresses.get(ctx, clusterID, ingressID)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't find ingress",
+			fmt.Sprintf(
+				"Can't find ingress with identifier '%s' for "+
+					"cluster '%s': %v",
+				ingressID, clusterID, err,
+			),
+		)
+		return
+	}
+
+	// Save the state:
+	state := &ClusterIngressState{
+		Cluster: types.String{
+			Value: clusterID,
+		},
+	}
+	populateClusterIngressState(ingress, state)
+	diags := response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+// ingressPatch returns the patch that changes the ingress from the given state to the given plan.
+// Attributes that aren't set in the plan aren't changed. When there is no state, for example
+// when the ingress is adopted, all the attributes set in the plan are included.
+func ingressPatch(state, plan *ClusterIngressState) map[string]interface{} {
+	if state == nil {
+		state = &ClusterIngressState{
+			Private: types.Bool{
+				Null: true,
+			},
+			RouteSelectors: types.Map{
+				ElemType: types.StringType,
+				Null:     true,
+			},
+			ExcludedNamespaces: types.List{
+				ElemType: types.StringType,
+				Null:     true,
+			},
+			WildcardPolicy: types.String{
+				Null: true,
+			},
+			NamespaceOwnershipPolicy: types.String{
+				Null: true,
+			},
+		}
+	}
+	patch := map[string]interface{}{}
+	if !plan.Private.Unknown && !plan.Private.Null &&
+		(state.Private.Null || plan.Private.Value != state.Private.Value) {
+		patch["listening"] = listeningMethod(plan.Private.Value)
+	}
+	if selectors, ok := common.ShouldPatchMap(state.RouteSelectors, plan.RouteSelectors); ok {
+		patch["route_selectors"] = selectors
+	}
+	if !plan.ExcludedNamespaces.Unknown && !plan.ExcludedNamespaces.Null {
+		planned, _ := common.StringListToArray(plan.ExcludedNamespaces)
+		current, _ := common.StringListToArray(state.ExcludedNamespaces)
+		if state.ExcludedNamespaces.Null || strings.Join(planned, ",") != strings.Join(current, ",") {
+			patch["excluded_namespaces"] = planned
+		}
+	}
+	if value, ok := common.ShouldPatchString(state.WildcardPolicy, plan.WildcardPolicy); ok {
+		patch["route_wildcard_policy"] = value
+	}
+	if value, ok := common.ShouldPatchString(state.NamespaceOwnershipPolicy,
+		plan.NamespaceOwnershipPolicy); ok {
+		patch["route_namespace_ownership_policy"] = value
+	}
+	return patch
+}
+
+// defaultIngressPatch returns the patch that restores the default settings of an ingress.
+func defaultIngressPatch() map[string]interface{} {
+	return map[string]interface{}{
+		"listening":                        listeningMethod(false),
+		"route_selectors":                  map[string]string{},
+		"excluded_namespaces":              []string{},
+		"route_wildcard_policy":            wildcardPolicyDisallowed,
+		"route_namespace_ownership_policy": namespaceOwnershipPolicyStrict,
+	}
+}
+
+func listeningMethod(private bool) cmv1.ListeningMethod {
+	if private {
+		return cmv1.ListeningMethodInternal
+	}
+	return cmv1.ListeningMethodExternal
+}
+
+// populateClusterIngressState copies the data from the API object to the Terraform state.
+func populateClusterIngressState(ingress *clusterIngress, state *ClusterIngressState) {
+	object := ingress.object
+	state.ID = types.String{
+		Value: object.ID(),
+	}
+	state.DNSName = types.String{
+		Value: object.DNSName(),
+	}
+	state.Private = types.Bool{
+		Value: object.Listening() == cmv1.ListeningMethodInternal,
+	}
+	state.RouteSelectors = types.Map{
+		ElemType: types.StringType,
+		Elems:    map[string]attr.Value{},
+	}
+	for k, v := range object.RouteSelectors() {
+		state.RouteSelectors.Elems[k] = types.String{
+			Value: v,
+		}
+	}
+	state.ExcludedNamespaces = common.StringArrayToList(ingress.policies.ExcludedNamespaces)
+	state.WildcardPolicy = types.String{
+		Value: ingress.policies.RouteWildcardPolicy,
+	}
+	if state.WildcardPolicy.Value == "" {
+		state.WildcardPolicy.Value = wildcardPolicyDisallowed
+	}
+	state.NamespaceOwnershipPolicy = types.String{
+		Value: ingress.policies.RouteNamespaceOwnershipPolicy,
+	}
+	if state.NamespaceOwnershipPolicy.Value == "" {
+		state.NamespaceOwnershipPolicy.Value = namespaceOwnershipPolicyStrict
+	}
+}
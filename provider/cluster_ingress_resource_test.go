@@ -0,0 +1,83 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/terraform-redhat/terraform-provider-ocm/provider/common"
+)
+
+var _ = Describe("Cluster ingress", func() {
+	state := func() *ClusterIngressState {
+		return &ClusterIngressState{
+			Private: types.Bool{Value: false},
+			RouteSelectors: types.Map{
+				ElemType: types.StringType,
+				Elems: map[string]attr.Value{
+					"shard": types.String{Value: "public"},
+				},
+			},
+			ExcludedNamespaces:       common.StringArrayToList([]string{"stage"}),
+			WildcardPolicy:           types.String{Value: wildcardPolicyDisallowed},
+			NamespaceOwnershipPolicy: types.String{Value: namespaceOwnershipPolicyStrict},
+		}
+	}
+
+	It("Includes all the configured attributes when adopting the ingress", func() {
+		plan := state()
+		plan.WildcardPolicy = types.String{Unknown: true}
+		patch := ingressPatch(nil, plan)
+		Expect(patch).To(HaveKeyWithValue("listening", cmv1.ListeningMethodExternal))
+		Expect(patch).To(HaveKeyWithValue("route_selectors", map[string]string{"shard": "public"}))
+		Expect(patch).To(HaveKeyWithValue("excluded_namespaces", []string{"stage"}))
+		Expect(patch).To(HaveKeyWithValue("route_namespace_ownership_policy", namespaceOwnershipPolicyStrict))
+		Expect(patch).ToNot(HaveKey("route_wildcard_policy"))
+	})
+
+	It("Includes only the changed attributes", func() {
+		plan := state()
+		plan.Private = types.Bool{Value: true}
+		plan.ExcludedNamespaces = common.StringArrayToList([]string{"stage", "test"})
+		patch := ingressPatch(state(), plan)
+		Expect(patch).To(HaveLen(2))
+		Expect(patch).To(HaveKeyWithValue("listening", cmv1.ListeningMethodInternal))
+		Expect(patch).To(HaveKeyWithValue("excluded_namespaces", []string{"stage", "test"}))
+	})
+
+	It("Uses the default policies when the server doesn't return them", func() {
+		object, err := cmv1.NewIngress().
+			ID("def").
+			Listening(cmv1.ListeningMethodInternal).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		result := &ClusterIngressState{}
+		populateClusterIngressState(&clusterIngress{
+			object:   object,
+			policies: &ingressPolicies{},
+		}, result)
+		Expect(result.ID.Value).To(Equal("def"))
+		Expect(result.Private.Value).To(BeTrue())
+		Expect(result.RouteSelectors.Elems).To(BeEmpty())
+		Expect(result.ExcludedNamespaces.Elems).To(BeEmpty())
+		Expect(result.WildcardPolicy.Value).To(Equal(wildcardPolicyDisallowed))
+		Expect(result.NamespaceOwnershipPolicy.Value).To(Equal(namespaceOwnershipPolicyStrict))
+	})
+})
@@ -0,0 +1,32 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type ClusterIngressState struct {
+	Cluster                  types.String `tfsdk:"cluster"`
+	ID                       types.String `tfsdk:"id"`
+	DNSName                  types.String `tfsdk:"dns_name"`
+	Private                  types.Bool   `tfsdk:"private"`
+	RouteSelectors           types.Map    `tfsdk:"route_selectors"`
+	ExcludedNamespaces       types.List   `tfsdk:"excluded_namespaces"`
+	WildcardPolicy           types.String `tfsdk:"wildcard_policy"`
+	NamespaceOwnershipPolicy types.String `tfsdk:"namespace_ownership_policy"`
+}
@@ -0,0 +1,111 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift-online/ocm-sdk-go/logging"
+)
+
+// installLogsTail is the number of lines of the install logs included in the error reported when
+// the installation of a cluster fails.
+const installLogsTail = 30
+
+// provisionErrorAttribute returns the schema of the computed attribute that contains the error
+// that caused the installation of a cluster to fail. Updates don't change it, so the plan keeps the
+// value of the state.
+func provisionErrorAttribute() tfsdk.Attribute {
+	return tfsdk.Attribute{
+		Description: "Code and message of the error that caused the installation of the " +
+			"cluster to fail. Empty unless the cluster is in error state.",
+		Type:     types.StringType,
+		Computed: true,
+		PlanModifiers: []tfsdk.AttributePlanModifier{
+			tfsdk.UseStateForUnknown(),
+		},
+	}
+}
+
+// provisionError returns the code and message of the provisioning error of the given cluster
+// status, in the format used by the 'provision_error' attribute, or an empty string if there is
+// no error.
+func provisionError(status *cmv1.ClusterStatus) string {
+	code := status.ProvisionErrorCode()
+	message := status.ProvisionErrorMessage()
+	switch {
+	case code != "" && message != "":
+		return fmt.Sprintf("%s: %s", code, message)
+	case code != "":
+		return code
+	default:
+		return message
+	}
+}
+
+// provisionErrorState returns the value of the 'provision_error' attribute for the given cluster.
+func provisionErrorState(object *cmv1.Cluster) types.String {
+	if object.State() != cmv1.ClusterStateError {
+		return types.String{
+			Null: true,
+		}
+	}
+	return types.String{
+		Value: provisionError(object.Status()),
+	}
+}
+
+// clusterErrorDetails returns a description of the error of a cluster that is in error state. It
+// retrieves the status of the cluster, which contains the provisioning error code and message, and
+// the last lines of the install logs. Failures to retrieve those details are logged and ignored,
+// so that the original error is still reported. The returned status is the one retrieved from the
+// server, or the one of the given cluster if it couldn't be retrieved.
+func clusterErrorDetails(ctx context.Context, collection *cmv1.ClustersClient,
+	object *cmv1.Cluster, logger logging.Logger) (details string, status *cmv1.ClusterStatus) {
+	resource := collection.Cluster(object.ID())
+	ctx = withClusterLogField(ctx, object.ID())
+	status = object.Status()
+	statusResponse, err := resource.Status().Get().SendContext(ctx)
+	if err != nil {
+		logger.Warn(withLogField(ctx, logFieldError, err.Error()), "Can't get status of cluster")
+	} else {
+		status = statusResponse.Body()
+	}
+	details = fmt.Sprintf("Cluster with identifier '%s' is in error state", object.ID())
+	text := provisionError(status)
+	if text == "" {
+		text = status.Description()
+	}
+	if text != "" {
+		details = fmt.Sprintf("%s: %s", details, text)
+	}
+	logsResponse, err := resource.Logs().Install().Get().Tail(installLogsTail).SendContext(ctx)
+	if err != nil {
+		logger.Warn(withLogField(ctx, logFieldError, err.Error()), "Can't get install logs of cluster")
+		return
+	}
+	logs := strings.TrimSpace(logsResponse.Body().Content())
+	if logs != "" {
+		details = fmt.Sprintf("%s\n\nLast lines of the install logs:\n%s", details, logs)
+	}
+	return
+}
@@ -0,0 +1,55 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+var _ = Describe("Cluster provision error", func() {
+	build := func(builder *cmv1.ClusterBuilder) *cmv1.Cluster {
+		object, err := builder.Build()
+		Expect(err).ToNot(HaveOccurred())
+		return object
+	}
+
+	It("Is null for clusters that aren't in error state", func() {
+		object := build(cmv1.NewCluster().
+			State(cmv1.ClusterStateInstalling).
+			Status(cmv1.NewClusterStatus().ProvisionErrorCode("OCM3055")))
+		Expect(provisionErrorState(object).Null).To(BeTrue())
+	})
+
+	It("Contains the code and the message of the error", func() {
+		object := build(cmv1.NewCluster().
+			State(cmv1.ClusterStateError).
+			Status(cmv1.NewClusterStatus().
+				ProvisionErrorCode("OCM3055").
+				ProvisionErrorMessage("Invalid subnet")))
+		Expect(provisionErrorState(object).Value).To(Equal("OCM3055: Invalid subnet"))
+	})
+
+	It("Contains only the message when there is no code", func() {
+		status, err := cmv1.NewClusterStatus().
+			ProvisionErrorMessage("Invalid subnet").
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(provisionError(status)).To(Equal("Invalid subnet"))
+	})
+})
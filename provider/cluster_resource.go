@@ -33,11 +33,13 @@ import (
 )
 
 type ClusterResourceType struct {
+	defaultProperties map[string]string
 }
 
 type ClusterResource struct {
 	logger     logging.Logger
 	collection *cmv1.ClustersClient
+	dryRun     bool
 }
 
 func (t *ClusterResourceType) GetSchema(ctx context.Context) (result tfsdk.Schema,
@@ -81,12 +83,16 @@ func (t *ClusterResourceType) GetSchema(ctx context.Context) (result tfsdk.Schem
 				},
 			},
 			"properties": {
-				Description: "User defined properties.",
+				Description: "User defined properties. The default properties of the " +
+					"provider are added to these.",
 				Type: types.MapType{
 					ElemType: types.StringType,
 				},
 				Optional: true,
 				Computed: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					MergeDefaultsModifier(t.defaultProperties),
+				},
 			},
 			"api_url": {
 				Description: "URL of the API server.",
@@ -217,11 +223,14 @@ func (t *ClusterResourceType) GetSchema(ctx context.Context) (result tfsdk.Schem
 				Type:        types.StringType,
 				Computed:    true,
 			},
+			"provision_error": provisionErrorAttribute(),
+			"hibernating":     hibernatingAttribute(),
 			"wait": {
 				Description: "Wait till the cluster is ready.",
 				Type:        types.BoolType,
 				Optional:    true,
 			},
+			"deletion_policy": deletionPolicyAttribute(),
 		},
 	}
 	return
@@ -239,6 +248,7 @@ func (t *ClusterResourceType) NewResource(ctx context.Context,
 	result = &ClusterResource{
 		logger:     parent.logger,
 		collection: collection,
+		dryRun:     parent.clusterDryRun,
 	}
 
 	return
@@ -352,6 +362,44 @@ func createClusterObject(ctx context.Context,
 	return object, err
 }
 
+// ModifyPlan sends the cluster that will be created to the server in dry run mode, so that
+// validation errors are reported while planning.
+func (r *ClusterResource) ModifyPlan(ctx context.Context, request tfsdk.ModifyResourcePlanRequest,
+	response *tfsdk.ModifyResourcePlanResponse) {
+	// Clusters can't be created hibernating, this is checked even if the dry run is disabled:
+	if request.State.Raw.IsNull() && !request.Plan.Raw.IsNull() {
+		checkNotCreatedHibernating(ctx, request.Plan, &response.Diagnostics)
+		if response.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if !r.dryRun || !shouldDryRunCluster(ctx, r.logger, request) {
+		return
+	}
+
+	// Get the plan:
+	state := &ClusterState{}
+	diags := request.Plan.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	object, err := createClusterObject(ctx, state, diags)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't build cluster",
+			fmt.Sprintf(
+				"Can't build cluster with name '%s': %v",
+				state.Name.Value, err,
+			),
+		)
+		return
+	}
+	dryRunCluster(ctx, r.collection, object, request.Plan.Schema, &response.Diagnostics)
+}
+
 func (r *ClusterResource) Create(ctx context.Context,
 	request tfsdk.CreateResourceRequest, response *tfsdk.CreateResourceResponse) {
 	// Get the plan:
@@ -362,6 +410,12 @@ func (r *ClusterResource) Create(ctx context.Context,
 		return
 	}
 
+	// Clusters can't be created hibernating, they need to be hibernated once they are ready:
+	checkNotCreatedHibernating(ctx, request.Plan, &response.Diagnostics)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
 	object, err := createClusterObject(ctx, state, diags)
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -386,6 +440,7 @@ func (r *ClusterResource) Create(ctx context.Context,
 		return
 	}
 	object = add.Body()
+	ctx = withClusterLogField(ctx, object.ID())
 
 	// Wait till the cluster is ready unless explicitly disabled:
 	wait := state.Wait.Unknown || state.Wait.Null || state.Wait.Value
@@ -397,7 +452,12 @@ func (r *ClusterResource) Create(ctx context.Context,
 			Interval(30 * time.Second).
 			Predicate(func(get *cmv1.ClusterGetResponse) bool {
 				object = get.Body()
-				return object.State() == cmv1.ClusterStateReady
+				switch object.State() {
+				case cmv1.ClusterStateReady,
+					cmv1.ClusterStateError:
+					return true
+				}
+				return false
 			}).
 			StartContext(pollCtx)
 		if err != nil {
@@ -412,8 +472,16 @@ func (r *ClusterResource) Create(ctx context.Context,
 		}
 	}
 
-	// Save the state:
+	// Save the state, also when the installation failed, so that Terraform marks the resource
+	// as tainted:
 	populateClusterState(object, state)
+	if object.State() == cmv1.ClusterStateError {
+		details, status := clusterErrorDetails(ctx, r.collection, object, r.logger)
+		state.ProvisionError = types.String{
+			Value: provisionError(status),
+		}
+		response.Diagnostics.AddError("Cluster installation failed", details)
+	}
 	diags = response.State.Set(ctx, state)
 	response.Diagnostics.Append(diags...)
 }
@@ -427,10 +495,16 @@ func (r *ClusterResource) Read(ctx context.Context, request tfsdk.ReadResourceRe
 	if response.Diagnostics.HasError() {
 		return
 	}
+	ctx = withClusterLogField(ctx, state.ID.Value)
 
 	// Find the cluster:
 	get, err := r.collection.Cluster(state.ID.Value).Get().SendContext(ctx)
 	if err != nil {
+		if common.IsStatusNotFound(err) {
+			r.logger.Warn(ctx, "Cluster wasn't found, removing it from the state")
+			response.State.RemoveResource(ctx)
+			return
+		}
 		response.Diagnostics.AddError(
 			"Can't find cluster",
 			fmt.Sprintf(
@@ -459,6 +533,7 @@ func (r *ClusterResource) Update(ctx context.Context, request tfsdk.UpdateResour
 	if response.Diagnostics.HasError() {
 		return
 	}
+	ctx = withClusterLogField(ctx, state.ID.Value)
 
 	// Get the plan:
 	plan := &ClusterState{}
@@ -468,6 +543,22 @@ func (r *ClusterResource) Update(ctx context.Context, request tfsdk.UpdateResour
 		return
 	}
 
+	// Resume the cluster before updating it, as hibernating clusters can't be changed:
+	hibernate, changeHibernation := shouldChangeHibernation(state.Hibernating, plan.Hibernating)
+	if changeHibernation && !hibernate {
+		_, err := changeClusterHibernation(ctx, r.collection, state.ID.Value, false, r.logger)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't resume cluster",
+				fmt.Sprintf(
+					"Can't resume cluster with identifier '%s': %v",
+					state.ID.Value, err,
+				),
+			)
+			return
+		}
+	}
+
 	// Send request to update the cluster:
 	builder := cmv1.NewCluster()
 	var nodes *cmv1.ClusterNodesBuilder
@@ -504,7 +595,23 @@ func (r *ClusterResource) Update(ctx context.Context, request tfsdk.UpdateResour
 	}
 	object := update.Body()
 
+	// Hibernate the cluster once it has been updated:
+	if changeHibernation && hibernate {
+		object, err = changeClusterHibernation(ctx, r.collection, state.ID.Value, true, r.logger)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't hibernate cluster",
+				fmt.Sprintf(
+					"Can't hibernate cluster with identifier '%s': %v",
+					state.ID.Value, err,
+				),
+			)
+			return
+		}
+	}
+
 	// Update the state:
+	state.DeletionPolicy = plan.DeletionPolicy
 	populateClusterState(object, state)
 	diags = response.State.Set(ctx, state)
 	response.Diagnostics.Append(diags...)
@@ -519,6 +626,18 @@ func (r *ClusterResource) Delete(ctx context.Context, request tfsdk.DeleteResour
 	if response.Diagnostics.HasError() {
 		return
 	}
+	ctx = withClusterLogField(ctx, state.ID.Value)
+
+	switch getDeletionPolicy(state.DeletionPolicy) {
+	case deletionPolicyProtect:
+		response.Diagnostics.AddError(protectedClusterError(state.ID.Value))
+		return
+	case deletionPolicyOrphan:
+		r.logger.Info(ctx, "Cluster has the '%s' deletion policy, removing it from the state without deleting it",
+			deletionPolicyOrphan)
+		response.State.RemoveResource(ctx)
+		return
+	}
 
 	// Send the request to delete the cluster:
 	resource := r.collection.Cluster(state.ID.Value)
@@ -752,5 +871,7 @@ func populateClusterState(object *cmv1.Cluster, state *ClusterState) {
 	state.State = types.String{
 		Value: string(object.State()),
 	}
+	state.ProvisionError = provisionErrorState(object)
+	state.Hibernating = hibernatingState(object)
 
 }
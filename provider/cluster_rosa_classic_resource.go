@@ -44,6 +44,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	ocm_errors "github.com/openshift-online/ocm-sdk-go/errors"
 	"github.com/openshift-online/ocm-sdk-go/logging"
@@ -56,6 +57,9 @@ const (
 	maxClusterNameLength = 15
 	tagsPrefix           = "rosa_"
 	tagsOpenShiftVersion = tagsPrefix + "openshift_version"
+	osdUpgradeType       = "OSD"
+	manualScheduleType   = "manual"
+	upgradeScheduleDelay = 10 * time.Minute
 )
 
 var kmsArnRE = regexp.MustCompile(
@@ -68,13 +72,18 @@ var addTerraformProviderVersionToUserAgent = request.NamedHandler{
 }
 
 type ClusterRosaClassicResourceType struct {
-	logger logging.Logger
+	logger            logging.Logger
+	defaultTags       map[string]string
+	defaultProperties map[string]string
 }
 
 type ClusterRosaClassicResource struct {
 	logger            logging.Logger
 	clusterCollection *cmv1.ClustersClient
 	versionCollection *cmv1.VersionsClient
+	dryRun            bool
+	httpClient        *http.Client
+	awsOptions        *awsSessionOptions
 }
 
 func (t *ClusterRosaClassicResourceType) GetSchema(ctx context.Context) (result tfsdk.Schema,
@@ -129,9 +138,6 @@ func (t *ClusterRosaClassicResourceType) GetSchema(ctx context.Context) (result
 					"Site Reliability Engineer (SRE) platform metrics.",
 				Type:     types.BoolType,
 				Optional: true,
-				PlanModifiers: []tfsdk.AttributePlanModifier{
-					ValueCannotBeChangedModifier(t.logger),
-				},
 			},
 			"disable_scp_checks": {
 				Description: "Enables you to monitor your own projects in isolation from Red Hat " +
@@ -143,20 +149,27 @@ func (t *ClusterRosaClassicResourceType) GetSchema(ctx context.Context) (result
 				},
 			},
 			"properties": {
-				Description: "User defined properties.",
+				Description: "User defined properties. The default properties of the " +
+					"provider are added to these.",
 				Type: types.MapType{
 					ElemType: types.StringType,
 				},
 				Optional: true,
 				Computed: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					MergeDefaultsModifier(t.defaultProperties),
+				},
 			},
 			"tags": {
-				Description: "Apply user defined tags to all resources created in AWS.",
+				Description: "Apply user defined tags to all resources created in AWS. " +
+					"The default tags of the provider are added to these.",
 				Type: types.MapType{
 					ElemType: types.StringType,
 				},
 				Optional: true,
+				Computed: true,
 				PlanModifiers: []tfsdk.AttributePlanModifier{
+					MergeCreateDefaultsModifier(t.defaultTags),
 					ValueCannotBeChangedModifier(t.logger),
 				},
 			},
@@ -318,9 +331,6 @@ func (t *ClusterRosaClassicResourceType) GetSchema(ctx context.Context) (result
 					},
 				}),
 				Optional: true,
-				PlanModifiers: []tfsdk.AttributePlanModifier{
-					ValueCannotBeChangedModifier(t.logger),
-				},
 			},
 			"service_cidr": {
 				Description: "Block of IP addresses for services.",
@@ -354,11 +364,47 @@ func (t *ClusterRosaClassicResourceType) GetSchema(ctx context.Context) (result
 				Type:        types.StringType,
 				Optional:    true,
 				Computed:    true,
-				// TODO: till AWS will support Managed policies we will not support update versions
+			},
+			"current_version": {
+				Description: "Identifier of the version of OpenShift currently running in the cluster. " +
+					"It differs from 'version' while an upgrade is in progress.",
+				Type:     types.StringType,
+				Computed: true,
 				PlanModifiers: []tfsdk.AttributePlanModifier{
-					ValueCannotBeChangedModifier(t.logger),
+					tfsdk.UseStateForUnknown(),
+				},
+			},
+			"upgrade_state": {
+				Description: "State of the last version upgrade scheduled by changing 'version', " +
+					"for example 'scheduled', 'started' or 'completed'.",
+				Type:     types.StringType,
+				Computed: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					tfsdk.UseStateForUnknown(),
 				},
 			},
+			"wait_for_create_complete": {
+				Description: "Wait till the cluster is ready in the create resource. If the " +
+					"installation fails the resource is marked as tainted, if the timeout " +
+					"expires a warning is reported. Default value is false",
+				Type:     types.BoolType,
+				Optional: true,
+			},
+			"create_timeout": {
+				Description: "Timeout in minutes for waiting for the cluster to be ready. Default value is 60 minutes.",
+				Type:        types.Int64Type,
+				Optional:    true,
+			},
+			"wait_for_upgrade_complete": {
+				Description: "Wait for the version upgrade to be completed in the update resource. Default value is false",
+				Type:        types.BoolType,
+				Optional:    true,
+			},
+			"upgrade_timeout": {
+				Description: "Timeout in minutes for waiting for the version upgrade to be completed. Default value is 60 minutes.",
+				Type:        types.Int64Type,
+				Optional:    true,
+			},
 			"disable_waiting_in_destroy": {
 				Description: "Disable addressing cluster state in the destroy resource. Default value is false",
 				Type:        types.BoolType,
@@ -369,11 +415,24 @@ func (t *ClusterRosaClassicResourceType) GetSchema(ctx context.Context) (result
 				Type:        types.Int64Type,
 				Optional:    true,
 			},
+			"deletion_policy": deletionPolicyAttribute(),
+			"adopt_existing": {
+				Description: "When 'true' and a cluster with the same name already exists in the " +
+					"same AWS account, for example because a previous apply was interrupted " +
+					"after sending the request to create it, that cluster is adopted into the " +
+					"state instead of creating a new one. The existing cluster must match " +
+					"the plan, otherwise the differences are reported as an error. Default " +
+					"value is false.",
+				Type:     types.BoolType,
+				Optional: true,
+			},
 			"state": {
 				Description: "State of the cluster.",
 				Type:        types.StringType,
 				Computed:    true,
 			},
+			"provision_error": provisionErrorAttribute(),
+			"hibernating":     hibernatingAttribute(),
 		},
 	}
 	return
@@ -395,6 +454,9 @@ func (t *ClusterRosaClassicResourceType) NewResource(ctx context.Context,
 		logger:            parent.logger,
 		clusterCollection: clusterCollection,
 		versionCollection: versionCollection,
+		dryRun:            parent.clusterDryRun,
+		httpClient:        parent.httpClient,
+		awsOptions:        parent.awsOptions,
 	}
 
 	return
@@ -554,22 +616,14 @@ func createClassicClusterObject(ctx context.Context,
 		builder.FIPS(true)
 	}
 
-	sts := cmv1.NewSTS()
 	var err error
 	if state.Sts != nil {
-		sts.RoleARN(state.Sts.RoleARN.Value)
-		sts.SupportRoleARN(state.Sts.SupportRoleArn.Value)
+		sts := newSTSBuilder(state.Sts.RoleARN, state.Sts.SupportRoleArn, state.Sts.OIDCConfigID,
+			state.Sts.OperatorRolePrefix)
 		instanceIamRoles := cmv1.NewInstanceIAMRoles()
 		instanceIamRoles.MasterRoleARN(state.Sts.InstanceIAMRoles.MasterRoleARN.Value)
 		instanceIamRoles.WorkerRoleARN(state.Sts.InstanceIAMRoles.WorkerRoleARN.Value)
 		sts.InstanceIAMRoles(instanceIamRoles)
-
-		// set OIDC config ID
-		if !state.Sts.OIDCConfigID.Unknown && !state.Sts.OIDCConfigID.Null && state.Sts.OIDCConfigID.Value != "" {
-			sts.OidcConfig(cmv1.NewOidcConfig().ID(state.Sts.OIDCConfigID.Value))
-		}
-
-		sts.OperatorRolePrefix(state.Sts.OperatorRolePrefix.Value)
 		aws.STS(sts)
 	}
 
@@ -604,7 +658,8 @@ func createClassicClusterObject(ctx context.Context,
 		// TODO: update it to support all cluster versions
 		isSupported, err := checkSupportedVersion(state.Version.Value)
 		if err != nil {
-			logger.Error(ctx, "Error validating required cluster version %s\", err)")
+			logger.Error(withLogField(ctx, logFieldError, err.Error()),
+				"Error validating required cluster version")
 			errDecription := fmt.Sprintf(
 				"Can't check if cluster version is supported '%s': %v",
 				state.Version.Value, err,
@@ -618,7 +673,8 @@ func createClassicClusterObject(ctx context.Context,
 		if isSupported {
 			builder.Version(cmv1.NewVersion().ID(state.Version.Value))
 		} else {
-			logger.Error(ctx, "Cluster version %s is not supported", state.Version.Value)
+			logger.Error(withLogField(ctx, logFieldVersion, state.Version.Value),
+				"Cluster version is not supported")
 			errDecription := fmt.Sprintf(
 				"Can't check if cluster version is supported '%s': %v",
 				state.Version.Value, err,
@@ -635,6 +691,9 @@ func createClassicClusterObject(ctx context.Context,
 	if state.Proxy != nil {
 		proxy.HTTPProxy(state.Proxy.HttpProxy.Value)
 		proxy.HTTPSProxy(state.Proxy.HttpsProxy.Value)
+		if !state.Proxy.NoProxy.Unknown && !state.Proxy.NoProxy.Null {
+			proxy.NoProxy(state.Proxy.NoProxy.Value)
+		}
 		if !state.Proxy.AdditionalTrustBundle.Unknown && !state.Proxy.AdditionalTrustBundle.Null {
 			builder.AdditionalTrustBundle(state.Proxy.AdditionalTrustBundle.Value)
 		}
@@ -647,6 +706,9 @@ func createClassicClusterObject(ctx context.Context,
 
 func (r *ClusterRosaClassicResource) validateAccountRoles(ctx context.Context, state *ClusterRosaClassicState) error {
 	r.logger.Debug(ctx, "Validating if cluster version is compatible to account roles' version")
+	if state.Sts == nil {
+		return nil
+	}
 	region := state.CloudRegion.Value
 	version := ""
 	if !state.Version.Unknown && !state.Version.Null {
@@ -661,7 +723,7 @@ func (r *ClusterRosaClassicResource) validateAccountRoles(ctx context.Context, s
 		version = versionList[0]
 	}
 
-	r.logger.Debug(ctx, "Cluster version is %s", version)
+	r.logger.Debug(withLogField(ctx, logFieldVersion, version), "Found cluster version")
 	roleARNs := []string{
 		state.Sts.RoleARN.Value,
 		state.Sts.SupportRoleArn.Value,
@@ -674,7 +736,7 @@ func (r *ClusterRosaClassicResource) validateAccountRoles(ctx context.Context, s
 			continue
 		}
 		// get role from arn
-		role, err := getRoleByARN(ARN, region)
+		role, err := getRoleByARN(ARN, region, r.awsOptions)
 		if err != nil {
 			return fmt.Errorf("Could not get Role '%s' : %v", ARN, err)
 		}
@@ -698,7 +760,8 @@ func (r *ClusterRosaClassicResource) hasCompatibleVersionTags(ctx context.Contex
 	}
 	for _, tag := range iamTags {
 		if aws.StringValue(tag.Key) == tagsOpenShiftVersion {
-			r.logger.Debug(ctx, "role version is %s", aws.StringValue(tag.Value))
+			r.logger.Debug(withLogField(ctx, "ocm_role_version", aws.StringValue(tag.Value)),
+				"Found role version")
 			if version == aws.StringValue(tag.Value) {
 				return true, nil
 			}
@@ -716,7 +779,7 @@ func (r *ClusterRosaClassicResource) hasCompatibleVersionTags(ctx context.Contex
 	return false, nil
 }
 
-func getRoleByARN(roleARN, region string) (*iam.Role, error) {
+func getRoleByARN(roleARN, region string, awsOptions *awsSessionOptions) (*iam.Role, error) {
 	// validate arn
 	parsedARN, err := arn.Parse(roleARN)
 	if err != nil {
@@ -733,7 +796,7 @@ func getRoleByARN(roleARN, region string) (*iam.Role, error) {
 	m := strings.LastIndex(resource, "/")
 	roleName := resource[m+1:]
 
-	sess, err := buildSession(region)
+	sess, err := buildSession(region, awsOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -748,19 +811,13 @@ func getRoleByARN(roleARN, region string) (*iam.Role, error) {
 	return roleOutput.Role, nil
 }
 
-func buildSession(region string) (*session.Session, error) {
-	sess, err := session.NewSessionWithOptions(session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-		Profile:           "",
-		Config: aws.Config{
-			CredentialsChainVerboseErrors: aws.Bool(true),
-			Region:                        &region,
-			Retryer:                       buildCustomRetryer(),
-			HTTPClient: &http.Client{
-				Transport: http.DefaultTransport,
-			},
-		},
-	})
+// buildSession creates the AWS session for the given region. The options are optional, when they
+// are nil the session is created only from the environment.
+func buildSession(region string, options *awsSessionOptions) (*session.Session, error) {
+	if options == nil {
+		options = &awsSessionOptions{}
+	}
+	sess, err := options.newSession(region)
 	if err != nil {
 		return nil, fmt.Errorf("Failed to create session. Check your AWS configuration and try again")
 	}
@@ -849,8 +906,31 @@ func (r *ClusterRosaClassicResource) getVersions(logger logging.Logger, ctx cont
 	return
 }
 
-func (r *ClusterRosaClassicResource) Create(ctx context.Context,
-	request tfsdk.CreateResourceRequest, response *tfsdk.CreateResourceResponse) {
+// ModifyPlan sends the cluster that will be created to the server in dry run mode, so that
+// validation errors are reported while planning.
+func (r *ClusterRosaClassicResource) ModifyPlan(ctx context.Context,
+	request tfsdk.ModifyResourcePlanRequest, response *tfsdk.ModifyResourcePlanResponse) {
+	// Clusters can't be created hibernating, this is checked even if the dry run is disabled:
+	if request.State.Raw.IsNull() && !request.Plan.Raw.IsNull() {
+		checkNotCreatedHibernating(ctx, request.Plan, &response.Diagnostics)
+		if response.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	// Changing the version schedules an upgrade, so the current version and the state of the
+	// upgrade can't be taken from the state:
+	if !request.State.Raw.IsNull() && !request.Plan.Raw.IsNull() {
+		planClusterUpgrade(ctx, request, response)
+		if response.Diagnostics.HasError() {
+			return
+		}
+	}
+
+	if !r.dryRun || !shouldDryRunCluster(ctx, r.logger, request) {
+		return
+	}
+
 	// Get the plan:
 	state := &ClusterRosaClassicState{}
 	diags := request.Plan.Get(ctx, state)
@@ -859,17 +939,65 @@ func (r *ClusterRosaClassicResource) Create(ctx context.Context,
 		return
 	}
 
-	err := r.validateAccountRoles(ctx, state)
+	object, err := createClassicClusterObject(ctx, state, r.logger, diags)
 	if err != nil {
 		response.Diagnostics.AddError(
 			"Can't build cluster",
 			fmt.Sprintf(
-				"Can't build cluster with name '%s', failed while validating account roles: %v",
+				"Can't build cluster with name '%s': %v",
 				state.Name.Value, err,
 			),
 		)
 		return
 	}
+
+	// A cluster that will be adopted doesn't need to be validated, but it needs to match the
+	// plan:
+	if state.AdoptExisting.Value {
+		existing := adoptCluster(ctx, r.clusterCollection, object, &response.Diagnostics)
+		if existing != nil || response.Diagnostics.HasError() {
+			return
+		}
+	}
+	dryRunCluster(ctx, r.clusterCollection, object, request.Plan.Schema, &response.Diagnostics)
+}
+
+// planClusterUpgrade marks the current version and the state of the upgrade as unknown in the plan
+// when the version of the cluster changes, as they are otherwise kept from the state.
+func planClusterUpgrade(ctx context.Context, request tfsdk.ModifyResourcePlanRequest,
+	response *tfsdk.ModifyResourcePlanResponse) {
+	versionPath := tftypes.NewAttributePath().WithAttributeName("version")
+	var stateVersion, planVersion types.String
+	response.Diagnostics.Append(request.State.GetAttribute(ctx, versionPath, &stateVersion)...)
+	response.Diagnostics.Append(request.Plan.GetAttribute(ctx, versionPath, &planVersion)...)
+	if response.Diagnostics.HasError() || planVersion.Equal(stateVersion) {
+		return
+	}
+	for _, name := range []string{"current_version", "upgrade_state"} {
+		response.Diagnostics.Append(response.Plan.SetAttribute(
+			ctx,
+			tftypes.NewAttributePath().WithAttributeName(name),
+			types.String{Unknown: true},
+		)...)
+	}
+}
+
+func (r *ClusterRosaClassicResource) Create(ctx context.Context,
+	request tfsdk.CreateResourceRequest, response *tfsdk.CreateResourceResponse) {
+	// Get the plan:
+	state := &ClusterRosaClassicState{}
+	diags := request.Plan.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// Clusters can't be created hibernating, they need to be hibernated once they are ready:
+	checkNotCreatedHibernating(ctx, request.Plan, &response.Diagnostics)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
 	object, err := createClassicClusterObject(ctx, state, r.logger, diags)
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -882,23 +1010,97 @@ func (r *ClusterRosaClassicResource) Create(ctx context.Context,
 		return
 	}
 
-	add, err := r.clusterCollection.Add().Body(object).SendContext(ctx)
+	// Adopt the existing cluster if there is one that matches the plan, otherwise create it:
+	var existing *cmv1.Cluster
+	if state.AdoptExisting.Value {
+		existing = adoptCluster(ctx, r.clusterCollection, object, &response.Diagnostics)
+		if response.Diagnostics.HasError() {
+			return
+		}
+	}
+	if existing != nil {
+		r.logger.Info(withClusterLogField(ctx, existing.ID()), "Adopting existing cluster")
+		object = existing
+	} else {
+		err = r.validateAccountRoles(ctx, state)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't build cluster",
+				fmt.Sprintf(
+					"Can't build cluster with name '%s', failed while validating account roles: %v",
+					state.Name.Value, err,
+				),
+			)
+			return
+		}
+		add, err := r.clusterCollection.Add().Body(object).SendContext(ctx)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't create cluster",
+				fmt.Sprintf(
+					"Can't create cluster with name '%s': %v",
+					state.Name.Value, err,
+				),
+			)
+			return
+		}
+		object = add.Body()
+	}
+	ctx = withClusterLogField(ctx, object.ID())
+
+	// Save the state:
+	err = populateRosaClassicClusterState(ctx, object, state, r.logger, DefaultHttpClient{r.httpClient})
 	if err != nil {
 		response.Diagnostics.AddError(
-			"Can't create cluster",
+			"Can't populate cluster state",
 			fmt.Sprintf(
-				"Can't create cluster with name '%s': %v",
-				state.Name.Value, err,
+				"Received error %v", err,
 			),
 		)
 		return
 	}
-	object = add.Body()
 
-	// Save the state:
-	err = populateRosaClassicClusterState(ctx, object, state, r.logger, DefaultHttpClient{})
+	// Wait till the cluster is ready if requested. The state is saved even if the installation
+	// fails, so that the cluster isn't left outside of the state, and Terraform marks the resource
+	// as tainted because of the error. Timeouts only result in a warning, as the cluster may still
+	// be installing correctly.
+	if state.WaitForCreateComplete.Value {
+		r.waitTillClusterIsReady(ctx, state, &response.Diagnostics)
+	}
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+// waitTillClusterIsReady waits till the cluster that was just created is ready and updates the
+// state with the result. Only the installation of the cluster failing is added to the diagnostics
+// as an error, failing to poll the state of the cluster, for example because the timeout expired,
+// is added as a warning so that the resource isn't tainted.
+func (r *ClusterRosaClassicResource) waitTillClusterIsReady(ctx context.Context,
+	state *ClusterRosaClassicState, diags *diag.Diagnostics) {
+	timeout := defaultTimeoutInMinutes
+	if !state.CreateTimeout.Unknown && !state.CreateTimeout.Null {
+		if state.CreateTimeout.Value <= 0 {
+			diags.AddWarning(nonPositiveTimeoutSummary, fmt.Sprintf(nonPositiveTimeoutFormat, state.ID.Value))
+		} else {
+			timeout = state.CreateTimeout.Value
+		}
+	}
+	object, err := isClusterReady(state.ID.Value, ctx, timeout, readyClusterWaitTarget,
+		r.clusterCollection, r.logger)
 	if err != nil {
-		response.Diagnostics.AddError(
+		diags.AddWarning(
+			"Can't poll cluster state",
+			fmt.Sprintf(
+				"Can't poll state of cluster with identifier '%s', it may still be "+
+					"installing: %v",
+				state.ID.Value, err,
+			),
+		)
+		return
+	}
+	err = populateRosaClassicClusterState(ctx, object, state, r.logger, DefaultHttpClient{r.httpClient})
+	if err != nil {
+		diags.AddError(
 			"Can't populate cluster state",
 			fmt.Sprintf(
 				"Received error %v", err,
@@ -906,8 +1108,13 @@ func (r *ClusterRosaClassicResource) Create(ctx context.Context,
 		)
 		return
 	}
-	diags = response.State.Set(ctx, state)
-	response.Diagnostics.Append(diags...)
+	if object.State() == cmv1.ClusterStateError {
+		details, status := clusterErrorDetails(ctx, r.clusterCollection, object, r.logger)
+		state.ProvisionError = types.String{
+			Value: provisionError(status),
+		}
+		diags.AddError("Cluster installation failed", details)
+	}
 }
 
 func (r *ClusterRosaClassicResource) Read(ctx context.Context, request tfsdk.ReadResourceRequest,
@@ -919,10 +1126,16 @@ func (r *ClusterRosaClassicResource) Read(ctx context.Context, request tfsdk.Rea
 	if response.Diagnostics.HasError() {
 		return
 	}
+	ctx = withClusterLogField(ctx, state.ID.Value)
 
 	// Find the cluster:
 	get, err := r.clusterCollection.Cluster(state.ID.Value).Get().SendContext(ctx)
 	if err != nil {
+		if common.IsStatusNotFound(err) {
+			r.logger.Warn(ctx, "Cluster wasn't found, removing it from the state")
+			response.State.RemoveResource(ctx)
+			return
+		}
 		response.Diagnostics.AddError(
 			"Can't find cluster",
 			fmt.Sprintf(
@@ -935,7 +1148,8 @@ func (r *ClusterRosaClassicResource) Read(ctx context.Context, request tfsdk.Rea
 	object := get.Body()
 
 	// Save the state:
-	err = populateRosaClassicClusterState(ctx, object, state, r.logger, DefaultHttpClient{})
+	desiredVersion := state.Version
+	err = populateRosaClassicClusterState(ctx, object, state, r.logger, DefaultHttpClient{r.httpClient})
 	if err != nil {
 		response.Diagnostics.AddError(
 			"Can't populate cluster state",
@@ -945,6 +1159,35 @@ func (r *ClusterRosaClassicResource) Read(ctx context.Context, request tfsdk.Rea
 		)
 		return
 	}
+
+	// Refresh the state of an upgrade that is still in progress, and keep the requested version
+	// till it is completed, otherwise the plan would try to schedule it again:
+	if isUpgradeInProgress(state.UpgradeState) {
+		if state.CurrentVersion.Value == desiredVersion.Value {
+			state.UpgradeState = types.String{
+				Value: string(cmv1.UpgradePolicyStateValueCompleted),
+			}
+		} else {
+			upgradeState, err := r.getUpgradeState(ctx, state.ID.Value)
+			if err != nil {
+				response.Diagnostics.AddError(
+					"Can't get upgrade state",
+					fmt.Sprintf(
+						"Can't get upgrade state of cluster with identifier '%s': %v",
+						state.ID.Value, err,
+					),
+				)
+				return
+			}
+			state.UpgradeState = types.String{
+				Value: string(upgradeState),
+			}
+			if isUpgradeInProgress(state.UpgradeState) {
+				state.Version = desiredVersion
+			}
+		}
+	}
+
 	diags = response.State.Set(ctx, state)
 	response.Diagnostics.Append(diags...)
 }
@@ -960,6 +1203,7 @@ func (r *ClusterRosaClassicResource) Update(ctx context.Context, request tfsdk.U
 	if response.Diagnostics.HasError() {
 		return
 	}
+	ctx = withClusterLogField(ctx, state.ID.Value)
 
 	// Get the plan:
 	plan := &ClusterRosaClassicState{}
@@ -969,6 +1213,89 @@ func (r *ClusterRosaClassicResource) Update(ctx context.Context, request tfsdk.U
 		return
 	}
 
+	// Resume the cluster before updating it, as hibernating clusters can't be upgraded or
+	// changed:
+	hibernate, changeHibernation := shouldChangeHibernation(state.Hibernating, plan.Hibernating)
+	if changeHibernation && !hibernate {
+		_, err := changeClusterHibernation(ctx, r.clusterCollection, state.ID.Value, false, r.logger)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't resume cluster",
+				fmt.Sprintf(
+					"Can't resume cluster with identifier '%s': %v",
+					state.ID.Value, err,
+				),
+			)
+			return
+		}
+	}
+
+	// Schedule an upgrade if the version was changed:
+	if _, ok := common.ShouldPatchString(state.Version, plan.Version); ok {
+		policy, err := r.scheduleUpgrade(ctx, state, plan)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't upgrade cluster",
+				fmt.Sprintf(
+					"Can't upgrade cluster with identifier '%s' to version '%s': %v",
+					state.ID.Value, plan.Version.Value, err,
+				),
+			)
+			return
+		}
+
+		isUpgraded := false
+		if !plan.WaitForUpgradeComplete.Unknown && !plan.WaitForUpgradeComplete.Null && plan.WaitForUpgradeComplete.Value {
+			timeout := defaultTimeoutInMinutes
+			if !plan.UpgradeTimeout.Unknown && !plan.UpgradeTimeout.Null {
+				if plan.UpgradeTimeout.Value <= 0 {
+					response.Diagnostics.AddWarning(nonPositiveTimeoutSummary, fmt.Sprintf(nonPositiveTimeoutFormat, state.ID.Value))
+				} else {
+					timeout = plan.UpgradeTimeout.Value
+				}
+			}
+			isUpgraded, err = r.waitTillClusterIsUpgradedWithTimeout(ctx, timeout, state.ID.Value, plan.Version.Value)
+			if err != nil {
+				response.Diagnostics.AddError(
+					"Can't poll cluster upgrade",
+					fmt.Sprintf(
+						"Can't poll upgrade of cluster with identifier '%s': %v",
+						state.ID.Value, err,
+					),
+				)
+				return
+			}
+			if !isUpgraded {
+				response.Diagnostics.AddWarning(
+					"Cluster wasn't upgraded yet",
+					fmt.Sprintf("The cluster with identifier '%s' is not upgraded yet, but the polling finished due to a timeout", state.ID.Value),
+				)
+			}
+		}
+
+		if isUpgraded {
+			state.UpgradeState = types.String{
+				Value: string(cmv1.UpgradePolicyStateValueCompleted),
+			}
+		} else {
+			stateResponse, err := r.clusterCollection.Cluster(state.ID.Value).UpgradePolicies().
+				UpgradePolicy(policy.ID()).State().Get().SendContext(ctx)
+			if err != nil {
+				response.Diagnostics.AddError(
+					"Can't get upgrade state",
+					fmt.Sprintf(
+						"Can't get state of upgrade policy '%s' of cluster with identifier '%s': %v",
+						policy.ID(), state.ID.Value, err,
+					),
+				)
+				return
+			}
+			state.UpgradeState = types.String{
+				Value: string(stateResponse.Body().Value()),
+			}
+		}
+	}
+
 	// Send request to update the cluster:
 	updateNodes := false
 	clusterBuilder := cmv1.NewCluster()
@@ -1005,9 +1332,31 @@ func (r *ClusterRosaClassicResource) Update(ctx context.Context, request tfsdk.U
 		}
 	}
 
+	labels, ok := common.ShouldPatchMap(state.DefaultMPLabels, plan.DefaultMPLabels)
+	if !ok && plan.DefaultMPLabels.Null && len(state.DefaultMPLabels.Elems) > 0 {
+		// The labels were removed from the configuration:
+		labels, ok = map[string]string{}, true
+	}
+	if ok {
+		clusterNodesBuilder = clusterNodesBuilder.ComputeLabels(labels)
+		updateNodes = true
+	}
+
 	if updateNodes {
 		clusterBuilder = clusterBuilder.Nodes(clusterNodesBuilder)
 	}
+
+	properties, ok := common.ShouldPatchMap(state.Properties, plan.Properties)
+	if ok {
+		clusterBuilder = clusterBuilder.Properties(properties)
+	}
+
+	if plan.DisableWorkloadMonitoring.Value != state.DisableWorkloadMonitoring.Value {
+		clusterBuilder = clusterBuilder.DisableUserWorkloadMonitoring(plan.DisableWorkloadMonitoring.Value)
+	}
+
+	patchProxy(state.Proxy, plan.Proxy, clusterBuilder)
+
 	clusterSpec, err := clusterBuilder.Build()
 	if err != nil {
 		response.Diagnostics.AddError(
@@ -1037,11 +1386,38 @@ func (r *ClusterRosaClassicResource) Update(ctx context.Context, request tfsdk.U
 	state.AutoScalingEnabled = plan.AutoScalingEnabled
 	// update the Replicas with the plan value (important for nil and zero value cases)
 	state.Replicas = plan.Replicas
+	// update the values that are only returned by the server when they are set:
+	state.DisableWorkloadMonitoring = plan.DisableWorkloadMonitoring
+	state.DefaultMPLabels = plan.DefaultMPLabels
+	state.Proxy = plan.Proxy
+	state.WaitForCreateComplete = plan.WaitForCreateComplete
+	state.CreateTimeout = plan.CreateTimeout
+	state.WaitForUpgradeComplete = plan.WaitForUpgradeComplete
+	state.UpgradeTimeout = plan.UpgradeTimeout
+	state.DisableWaitingInDestroy = plan.DisableWaitingInDestroy
+	state.DestroyTimeout = plan.DestroyTimeout
+	state.DeletionPolicy = plan.DeletionPolicy
+	state.AdoptExisting = plan.AdoptExisting
 
 	object := update.Body()
 
+	// Hibernate the cluster once it has been updated:
+	if changeHibernation && hibernate {
+		object, err = changeClusterHibernation(ctx, r.clusterCollection, state.ID.Value, true, r.logger)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't hibernate cluster",
+				fmt.Sprintf(
+					"Can't hibernate cluster with identifier '%s': %v",
+					state.ID.Value, err,
+				),
+			)
+			return
+		}
+	}
+
 	// Update the state:
-	err = populateRosaClassicClusterState(ctx, object, state, r.logger, DefaultHttpClient{})
+	err = populateRosaClassicClusterState(ctx, object, state, r.logger, DefaultHttpClient{r.httpClient})
 	if err != nil {
 		response.Diagnostics.AddError(
 			"Can't populate cluster state",
@@ -1051,6 +1427,11 @@ func (r *ClusterRosaClassicResource) Update(ctx context.Context, request tfsdk.U
 		)
 		return
 	}
+
+	// Keep the requested version while the upgrade is in progress:
+	if isUpgradeInProgress(state.UpgradeState) && !plan.Version.Unknown && !plan.Version.Null {
+		state.Version = plan.Version
+	}
 	diags = response.State.Set(ctx, state)
 	response.Diagnostics.Append(diags...)
 }
@@ -1064,6 +1445,18 @@ func (r *ClusterRosaClassicResource) Delete(ctx context.Context, request tfsdk.D
 	if response.Diagnostics.HasError() {
 		return
 	}
+	ctx = withClusterLogField(ctx, state.ID.Value)
+
+	switch getDeletionPolicy(state.DeletionPolicy) {
+	case deletionPolicyProtect:
+		response.Diagnostics.AddError(protectedClusterError(state.ID.Value))
+		return
+	case deletionPolicyOrphan:
+		r.logger.Info(ctx, "Cluster has the '%s' deletion policy, removing it from the state without deleting it",
+			deletionPolicyOrphan)
+		response.State.RemoveResource(ctx)
+		return
+	}
 
 	// Send the request to delete the cluster:
 	resource := r.clusterCollection.Cluster(state.ID.Value)
@@ -1089,7 +1482,7 @@ func (r *ClusterRosaClassicResource) Delete(ctx context.Context, request tfsdk.D
 				timeout = state.DestroyTimeout.Value
 			}
 		}
-		isNotFound, err := r.retryClusterNotFoundWithTimeout(3, 1*time.Minute, ctx, timeout, resource)
+		isNotFound, err := retryClusterNotFoundWithTimeout(3, 1*time.Minute, ctx, timeout, resource, r.logger)
 		if err != nil {
 			response.Diagnostics.AddError(
 				"Can't poll cluster state",
@@ -1131,7 +1524,7 @@ func (r *ClusterRosaClassicResource) ImportState(ctx context.Context, request tf
 
 	// Save the state:
 	state := &ClusterRosaClassicState{}
-	err = populateRosaClassicClusterState(ctx, object, state, r.logger, DefaultHttpClient{})
+	err = populateRosaClassicClusterState(ctx, object, state, r.logger, DefaultHttpClient{r.httpClient})
 	if err != nil {
 		response.Diagnostics.AddError(
 			"Can't populate cluster state",
@@ -1173,6 +1566,15 @@ func populateRosaClassicClusterState(ctx context.Context, object *cmv1.Cluster,
 			Value: v,
 		}
 	}
+	state.Tags = types.Map{
+		ElemType: types.StringType,
+		Elems:    map[string]attr.Value{},
+	}
+	for k, v := range object.AWS().Tags() {
+		state.Tags.Elems[k] = types.String{
+			Value: v,
+		}
+	}
 	state.APIURL = types.String{
 		Value: object.API().URL(),
 	}
@@ -1190,7 +1592,7 @@ func populateRosaClassicClusterState(ctx context.Context, object *cmv1.Cluster,
 	}
 
 	labels, ok := object.Nodes().GetComputeLabels()
-	if ok {
+	if ok && len(labels) > 0 {
 		state.DefaultMPLabels = types.Map{
 			ElemType: types.StringType,
 			Elems:    map[string]attr.Value{},
@@ -1296,14 +1698,7 @@ func populateRosaClassicClusterState(ctx context.Context, object *cmv1.Cluster,
 		if state.Sts == nil {
 			state.Sts = &Sts{}
 		}
-		oidc_endpoint_url := sts.OIDCEndpointURL()
-		if strings.HasPrefix(oidc_endpoint_url, "https://") {
-			oidc_endpoint_url = strings.TrimPrefix(oidc_endpoint_url, "https://")
-		}
-
-		state.Sts.OIDCEndpointURL = types.String{
-			Value: oidc_endpoint_url,
-		}
+		state.Sts.OIDCEndpointURL, state.Sts.Thumbprint = getOIDCEndpointState(ctx, sts, logger, httpClient)
 		state.Sts.RoleARN = types.String{
 			Value: sts.RoleARN(),
 		}
@@ -1328,17 +1723,6 @@ func populateRosaClassicClusterState(ctx context.Context, object *cmv1.Cluster,
 				}
 			}
 		}
-		thumbprint, err := getThumbprint(sts.OIDCEndpointURL(), httpClient)
-		if err != nil {
-			logger.Error(ctx, "cannot get thumbprint", err)
-			state.Sts.Thumbprint = types.String{
-				Value: "",
-			}
-		} else {
-			state.Sts.Thumbprint = types.String{
-				Value: thumbprint,
-			}
-		}
 		oidcConfig, ok := sts.GetOidcConfig()
 		if ok && oidcConfig != nil {
 			state.Sts.OIDCConfigID = types.String{
@@ -1358,17 +1742,33 @@ func populateRosaClassicClusterState(ctx context.Context, object *cmv1.Cluster,
 	}
 
 	proxy, ok := object.GetProxy()
-	if ok {
+	if ok && (proxy.HTTPProxy() != "" || proxy.HTTPSProxy() != "") {
+		if state.Proxy == nil {
+			state.Proxy = &Proxy{
+				NoProxy: types.String{
+					Null: true,
+				},
+				AdditionalTrustBundle: types.String{
+					Null: true,
+				},
+			}
+		}
 		state.Proxy.HttpProxy = types.String{
 			Value: proxy.HTTPProxy(),
 		}
 		state.Proxy.HttpsProxy = types.String{
 			Value: proxy.HTTPSProxy(),
 		}
+		noProxy, ok := proxy.GetNoProxy()
+		if ok && noProxy != "" {
+			state.Proxy.NoProxy = types.String{
+				Value: noProxy,
+			}
+		}
 	}
 
 	trustBundle, ok := object.GetAdditionalTrustBundle()
-	if ok {
+	if ok && trustBundle != "" && state.Proxy != nil {
 		state.Proxy.AdditionalTrustBundle = types.String{
 			Value: trustBundle,
 		}
@@ -1419,27 +1819,106 @@ func populateRosaClassicClusterState(ctx context.Context, object *cmv1.Cluster,
 		state.Version = types.String{
 			Value: version,
 		}
+		state.CurrentVersion = types.String{
+			Value: version,
+		}
 	} else {
 		state.Version = types.String{
 			Null: true,
 		}
+		state.CurrentVersion = types.String{
+			Null: true,
+		}
+	}
+	if state.UpgradeState.Unknown {
+		state.UpgradeState = types.String{
+			Null: true,
+		}
 	}
 	state.State = types.String{
 		Value: string(object.State()),
 	}
+	state.ProvisionError = provisionErrorState(object)
+	state.Hibernating = hibernatingState(object)
 
 	return nil
 }
 
+// patchProxy adds to the given cluster patch the changes between the proxy configuration of the
+// state and the plan. Removing the proxy from the configuration clears the proxy and the
+// additional trust bundle of the cluster.
+func patchProxy(state, plan *Proxy, builder *cmv1.ClusterBuilder) {
+	current := &Proxy{}
+	if state != nil {
+		current = state
+	}
+	planned := &Proxy{}
+	if plan != nil {
+		planned = plan
+	}
+	if planned.HttpProxy.Value != current.HttpProxy.Value ||
+		planned.HttpsProxy.Value != current.HttpsProxy.Value ||
+		planned.NoProxy.Value != current.NoProxy.Value {
+		builder.Proxy(cmv1.NewProxy().
+			HTTPProxy(planned.HttpProxy.Value).
+			HTTPSProxy(planned.HttpsProxy.Value).
+			NoProxy(planned.NoProxy.Value))
+	}
+	if planned.AdditionalTrustBundle.Value != current.AdditionalTrustBundle.Value {
+		builder.AdditionalTrustBundle(planned.AdditionalTrustBundle.Value)
+	}
+}
+
+// newSTSBuilder returns a builder with the STS attributes that are common to all the ROSA
+// cluster resources.
+func newSTSBuilder(roleARN, supportRoleARN, oidcConfigID, operatorRolePrefix types.String) *cmv1.STSBuilder {
+	sts := cmv1.NewSTS()
+	sts.RoleARN(roleARN.Value)
+	sts.SupportRoleARN(supportRoleARN.Value)
+
+	// set OIDC config ID
+	if !oidcConfigID.Unknown && !oidcConfigID.Null && oidcConfigID.Value != "" {
+		sts.OidcConfig(cmv1.NewOidcConfig().ID(oidcConfigID.Value))
+	}
+
+	sts.OperatorRolePrefix(operatorRolePrefix.Value)
+	return sts
+}
+
+// getOIDCEndpointState returns the OIDC endpoint URL of the given STS object without the scheme,
+// and the thumbprint of its root CA.
+func getOIDCEndpointState(ctx context.Context, sts *cmv1.STS, logger logging.Logger,
+	httpClient HttpClient) (oidcEndpointURL types.String, thumbprint types.String) {
+	oidcEndpointURL = types.String{
+		Value: strings.TrimPrefix(sts.OIDCEndpointURL(), "https://"),
+	}
+
+	value, err := getThumbprint(sts.OIDCEndpointURL(), httpClient)
+	if err != nil {
+		logger.Error(withLogField(ctx, logFieldError, err.Error()), "Can't get thumbprint")
+		value = ""
+	}
+	thumbprint = types.String{
+		Value: value,
+	}
+	return
+}
+
 type HttpClient interface {
 	Get(url string) (resp *http.Response, err error)
 }
 
+// DefaultHttpClient implements the HttpClient interface with the given HTTP client, or with the
+// default one when it is nil.
 type DefaultHttpClient struct {
+	client *http.Client
 }
 
 func (c DefaultHttpClient) Get(url string) (resp *http.Response, err error) {
-	return http.Get(url)
+	if c.client == nil {
+		return http.Get(url)
+	}
+	return c.client.Get(url)
 }
 
 func getThumbprint(oidcEndpointURL string, httpClient HttpClient) (thumbprint string, err error) {
@@ -1493,6 +1972,141 @@ func sha1Hash(data []byte) (string, error) {
 	return hex.EncodeToString(hashed), nil
 }
 
+// scheduleUpgrade checks that the version requested in the plan is an available upgrade of the
+// current version of the cluster and that the account roles are compatible with it, and then
+// schedules a manual upgrade policy for it. If an upgrade to the same version is already
+// scheduled it returns the existing policy.
+func (r *ClusterRosaClassicResource) scheduleUpgrade(ctx context.Context, state *ClusterRosaClassicState,
+	plan *ClusterRosaClassicState) (*cmv1.UpgradePolicy, error) {
+	currentVersion := state.CurrentVersion.Value
+	if state.CurrentVersion.Unknown || state.CurrentVersion.Null {
+		currentVersion = state.Version.Value
+	}
+	r.logger.Debug(withLogField(ctx, logFieldVersion, currentVersion),
+		"Upgrading cluster to version '%s'", plan.Version.Value)
+
+	targetResponse, err := r.versionCollection.Version(plan.Version.Value).Get().SendContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("can't find version '%s': %v", plan.Version.Value, err)
+	}
+	target := targetResponse.Body()
+
+	currentResponse, err := r.versionCollection.Version(currentVersion).Get().SendContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("can't find version '%s': %v", currentVersion, err)
+	}
+	availableUpgrades := currentResponse.Body().AvailableUpgrades()
+	isAvailable := false
+	for _, availableUpgrade := range availableUpgrades {
+		if availableUpgrade == target.RawID() {
+			isAvailable = true
+			break
+		}
+	}
+	if !isAvailable {
+		return nil, fmt.Errorf("version '%s' is not an available upgrade of version '%s', available upgrades are: [%s]",
+			plan.Version.Value, currentVersion, strings.Join(availableUpgrades, ", "))
+	}
+
+	err = r.validateAccountRoles(ctx, plan)
+	if err != nil {
+		return nil, fmt.Errorf("failed while validating account roles: %v", err)
+	}
+
+	upgradePolicies := r.clusterCollection.Cluster(state.ID.Value).UpgradePolicies()
+	listResponse, err := upgradePolicies.List().SendContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("can't list upgrade policies: %v", err)
+	}
+	for _, policy := range listResponse.Items().Slice() {
+		if policy.UpgradeType() != osdUpgradeType {
+			continue
+		}
+		if policy.Version() == target.RawID() {
+			r.logger.Debug(withObjectLogField(ctx, policy.ID()),
+				"Upgrade to version '%s' is already scheduled", target.RawID())
+			return policy, nil
+		}
+		return nil, fmt.Errorf("there is already an upgrade policy '%s' scheduled to version '%s'",
+			policy.ID(), policy.Version())
+	}
+
+	policy, err := cmv1.NewUpgradePolicy().
+		UpgradeType(osdUpgradeType).
+		ScheduleType(manualScheduleType).
+		Version(target.RawID()).
+		NextRun(time.Now().UTC().Add(upgradeScheduleDelay)).
+		Build()
+	if err != nil {
+		return nil, fmt.Errorf("can't build upgrade policy: %v", err)
+	}
+	addResponse, err := upgradePolicies.Add().Body(policy).SendContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("can't create upgrade policy: %v", err)
+	}
+
+	return addResponse.Body(), nil
+}
+
+// getUpgradeState returns the state of the upgrade policy of the cluster, or 'cancelled' if
+// there is no such policy anymore.
+func (r *ClusterRosaClassicResource) getUpgradeState(ctx context.Context,
+	clusterID string) (cmv1.UpgradePolicyStateValue, error) {
+	upgradePolicies := r.clusterCollection.Cluster(clusterID).UpgradePolicies()
+	listResponse, err := upgradePolicies.List().SendContext(ctx)
+	if err != nil {
+		return "", err
+	}
+	for _, policy := range listResponse.Items().Slice() {
+		if policy.UpgradeType() != osdUpgradeType {
+			continue
+		}
+		stateResponse, err := upgradePolicies.UpgradePolicy(policy.ID()).State().Get().SendContext(ctx)
+		if err != nil {
+			return "", err
+		}
+		return stateResponse.Body().Value(), nil
+	}
+
+	return cmv1.UpgradePolicyStateValueCancelled, nil
+}
+
+func (r *ClusterRosaClassicResource) waitTillClusterIsUpgradedWithTimeout(ctx context.Context, timeout int64,
+	clusterID string, version string) (bool, error) {
+	pollCtx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Minute)
+	defer cancel()
+	isUpgraded := false
+	_, err := r.clusterCollection.Cluster(clusterID).Poll().
+		Interval(pollingIntervalInMinutes * time.Minute).
+		Predicate(func(getClusterResponse *cmv1.ClusterGetResponse) bool {
+			currentVersion := getClusterResponse.Body().Version().ID()
+			r.logger.Debug(withLogField(ctx, logFieldVersion, currentVersion),
+				"Polled cluster version")
+			isUpgraded = currentVersion == version
+			return isUpgraded
+		}).
+		StartContext(pollCtx)
+	if err != nil {
+		r.logger.Error(ctx, "Can't poll cluster upgrade")
+		return false, err
+	}
+
+	return isUpgraded, nil
+}
+
+func isUpgradeInProgress(upgradeState types.String) bool {
+	if upgradeState.Unknown || upgradeState.Null {
+		return false
+	}
+	switch cmv1.UpgradePolicyStateValue(upgradeState.Value) {
+	case cmv1.UpgradePolicyStateValueCompleted,
+		cmv1.UpgradePolicyStateValueFailed,
+		cmv1.UpgradePolicyStateValueCancelled:
+		return false
+	}
+	return true
+}
+
 func checkSupportedVersion(clusterVersion string) (bool, error) {
 	rawID := strings.Replace(clusterVersion, "openshift-v", "", 1)
 	v1, err := semver.NewVersion(rawID)
@@ -1507,13 +2121,13 @@ func checkSupportedVersion(clusterVersion string) (bool, error) {
 	return v1.GreaterThanOrEqual(v2), nil
 }
 
-func (r *ClusterRosaClassicResource) retryClusterNotFoundWithTimeout(attempts int, sleep time.Duration, ctx context.Context, timeout int64,
-	resource *cmv1.ClusterClient) (bool, error) {
-	isNotFound, err := r.waitTillClusterIsNotFoundWithTimeout(ctx, timeout, resource, r.logger)
+func retryClusterNotFoundWithTimeout(attempts int, sleep time.Duration, ctx context.Context, timeout int64,
+	resource *cmv1.ClusterClient, logger logging.Logger) (bool, error) {
+	isNotFound, err := waitTillClusterIsNotFoundWithTimeout(ctx, timeout, resource, logger)
 	if err != nil {
 		if attempts--; attempts > 0 {
 			time.Sleep(sleep)
-			return r.retryClusterNotFoundWithTimeout(attempts, 2*sleep, ctx, timeout, resource)
+			return retryClusterNotFoundWithTimeout(attempts, 2*sleep, ctx, timeout, resource, logger)
 		}
 		return isNotFound, err
 	}
@@ -1521,7 +2135,7 @@ func (r *ClusterRosaClassicResource) retryClusterNotFoundWithTimeout(attempts in
 	return isNotFound, nil
 }
 
-func (r *ClusterRosaClassicResource) waitTillClusterIsNotFoundWithTimeout(ctx context.Context, timeout int64,
+func waitTillClusterIsNotFoundWithTimeout(ctx context.Context, timeout int64,
 	resource *cmv1.ClusterClient, logger logging.Logger) (bool, error) {
 	timeoutInMinutes := time.Duration(timeout) * time.Minute
 	pollCtx, cancel := context.WithTimeout(ctx, timeoutInMinutes)
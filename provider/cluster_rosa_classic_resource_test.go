@@ -26,7 +26,9 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
 	. "github.com/onsi/gomega"             // nolint
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
@@ -233,6 +235,29 @@ var _ = Describe("Rosa Classic Sts cluster", func() {
 			Expect(clusterState.Sts.RoleARN.Value).To(Equal(roleArn))
 		})
 
+		It("Sets the current version and keeps the upgrade state", func() {
+			clusterState := &ClusterRosaClassicState{
+				UpgradeState: types.String{
+					Value: "scheduled",
+				},
+			}
+			clusterJson := generateBasicRosaClassicClusterJson()
+			clusterJson["version"] = map[string]interface{}{
+				"id": "openshift-v4.10.1",
+			}
+			clusterJsonString, err := json.Marshal(clusterJson)
+			Expect(err).To(BeNil())
+
+			clusterObject, err := cmv1.UnmarshalCluster(clusterJsonString)
+			Expect(err).To(BeNil())
+
+			err = populateRosaClassicClusterState(context.Background(), clusterObject, clusterState, &logging.StdLogger{}, mockHttpClient)
+			Expect(err).To(BeNil())
+			Expect(clusterState.Version.Value).To(Equal("openshift-v4.10.1"))
+			Expect(clusterState.CurrentVersion.Value).To(Equal("openshift-v4.10.1"))
+			Expect(clusterState.UpgradeState.Value).To(Equal("scheduled"))
+		})
+
 		It("Check trimming of oidc url with https perfix", func() {
 			clusterState := &ClusterRosaClassicState{}
 			clusterJson := generateBasicRosaClassicClusterJson()
@@ -267,4 +292,135 @@ var _ = Describe("Rosa Classic Sts cluster", func() {
 			Expect(clusterState.Sts.Thumbprint.Value).To(Equal(""))
 		})
 	})
+
+	Context("patchProxy", func() {
+		It("Clears the proxy and the trust bundle when the proxy is removed", func() {
+			state := &Proxy{
+				HttpProxy:             types.String{Value: "http://proxy.com"},
+				HttpsProxy:            types.String{Value: "http://proxy.com"},
+				NoProxy:               types.String{Null: true},
+				AdditionalTrustBundle: types.String{Value: "123"},
+			}
+			builder := cmv1.NewCluster()
+			patchProxy(state, nil, builder)
+			object, err := builder.Build()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(object.Proxy().HTTPProxy()).To(BeEmpty())
+			Expect(object.Proxy().HTTPSProxy()).To(BeEmpty())
+			trustBundle, ok := object.GetAdditionalTrustBundle()
+			Expect(ok).To(BeTrue())
+			Expect(trustBundle).To(BeEmpty())
+		})
+
+		It("Doesn't patch the proxy when it didn't change", func() {
+			state := &Proxy{
+				HttpProxy:  types.String{Value: "http://proxy.com"},
+				HttpsProxy: types.String{Value: "http://proxy.com"},
+			}
+			plan := &Proxy{
+				HttpProxy:  types.String{Value: "http://proxy.com"},
+				HttpsProxy: types.String{Value: "http://proxy.com"},
+			}
+			builder := cmv1.NewCluster()
+			patchProxy(state, plan, builder)
+			object, err := builder.Build()
+			Expect(err).ToNot(HaveOccurred())
+			_, ok := object.GetProxy()
+			Expect(ok).To(BeFalse())
+			_, ok = object.GetAdditionalTrustBundle()
+			Expect(ok).To(BeFalse())
+		})
+
+		It("Populates the proxy of a cluster without proxy in the state", func() {
+			clusterState := &ClusterRosaClassicState{}
+			clusterJson := generateBasicRosaClassicClusterJson()
+			clusterJson["proxy"] = map[string]interface{}{
+				"http_proxy":  "http://proxy.com",
+				"https_proxy": "http://proxy.com",
+				"no_proxy":    "example.com",
+			}
+			clusterJsonString, err := json.Marshal(clusterJson)
+			Expect(err).To(BeNil())
+
+			clusterObject, err := cmv1.UnmarshalCluster(clusterJsonString)
+			Expect(err).To(BeNil())
+
+			err = populateRosaClassicClusterState(context.Background(), clusterObject, clusterState, &logging.StdLogger{}, mockHttpClient)
+			Expect(err).To(BeNil())
+			Expect(clusterState.Proxy).ToNot(BeNil())
+			Expect(clusterState.Proxy.HttpProxy.Value).To(Equal("http://proxy.com"))
+			Expect(clusterState.Proxy.NoProxy.Value).To(Equal("example.com"))
+			Expect(clusterState.Proxy.AdditionalTrustBundle.Null).To(BeTrue())
+		})
+	})
+})
+
+var _ = Describe("Cluster upgrade plan", func() {
+	schema := tfsdk.Schema{
+		Attributes: map[string]tfsdk.Attribute{
+			"version": {
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"current_version": {
+				Type:     types.StringType,
+				Computed: true,
+			},
+			"upgrade_state": {
+				Type:     types.StringType,
+				Computed: true,
+			},
+		},
+	}
+	objectType := tftypes.Object{
+		AttributeTypes: map[string]tftypes.Type{
+			"version":         tftypes.String,
+			"current_version": tftypes.String,
+			"upgrade_state":   tftypes.String,
+		},
+	}
+	value := func(version string) tftypes.Value {
+		return tftypes.NewValue(objectType, map[string]tftypes.Value{
+			"version":         tftypes.NewValue(tftypes.String, version),
+			"current_version": tftypes.NewValue(tftypes.String, "4.10.1"),
+			"upgrade_state":   tftypes.NewValue(tftypes.String, "completed"),
+		})
+	}
+	modify := func(stateVersion, planVersion string) *tfsdk.ModifyResourcePlanResponse {
+		request := tfsdk.ModifyResourcePlanRequest{
+			State: tfsdk.State{
+				Schema: schema,
+				Raw:    value(stateVersion),
+			},
+			Plan: tfsdk.Plan{
+				Schema: schema,
+				Raw:    value(planVersion),
+			},
+		}
+		response := &tfsdk.ModifyResourcePlanResponse{
+			Plan: request.Plan,
+		}
+		planClusterUpgrade(context.Background(), request, response)
+		Expect(response.Diagnostics.HasError()).To(BeFalse())
+		return response
+	}
+	get := func(plan tfsdk.Plan, name string) types.String {
+		var result types.String
+		diags := plan.GetAttribute(context.Background(),
+			tftypes.NewAttributePath().WithAttributeName(name), &result)
+		Expect(diags.HasError()).To(BeFalse())
+		return result
+	}
+
+	It("Keeps the current version and upgrade state when the version doesn't change", func() {
+		response := modify("4.10.1", "4.10.1")
+		Expect(get(response.Plan, "current_version").Value).To(Equal("4.10.1"))
+		Expect(get(response.Plan, "upgrade_state").Value).To(Equal("completed"))
+	})
+
+	It("Marks the current version and upgrade state unknown when the version changes", func() {
+		response := modify("4.10.1", "4.10.2")
+		Expect(get(response.Plan, "current_version").Unknown).To(BeTrue())
+		Expect(get(response.Plan, "upgrade_state").Unknown).To(BeTrue())
+	})
 })
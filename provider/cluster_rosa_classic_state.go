@@ -54,9 +54,19 @@ type ClusterRosaClassicState struct {
 	ServiceCIDR               types.String `tfsdk:"service_cidr"`
 	Proxy                     *Proxy       `tfsdk:"proxy"`
 	State                     types.String `tfsdk:"state"`
+	ProvisionError            types.String `tfsdk:"provision_error"`
+	Hibernating               types.Bool   `tfsdk:"hibernating"`
 	Version                   types.String `tfsdk:"version"`
+	CurrentVersion            types.String `tfsdk:"current_version"`
+	UpgradeState              types.String `tfsdk:"upgrade_state"`
+	WaitForCreateComplete     types.Bool   `tfsdk:"wait_for_create_complete"`
+	CreateTimeout             types.Int64  `tfsdk:"create_timeout"`
+	WaitForUpgradeComplete    types.Bool   `tfsdk:"wait_for_upgrade_complete"`
+	UpgradeTimeout            types.Int64  `tfsdk:"upgrade_timeout"`
 	DisableWaitingInDestroy   types.Bool   `tfsdk:"disable_waiting_in_destroy"`
 	DestroyTimeout            types.Int64  `tfsdk:"destroy_timeout"`
+	DeletionPolicy            types.String `tfsdk:"deletion_policy"`
+	AdoptExisting             types.Bool   `tfsdk:"adopt_existing"`
 }
 
 type Sts struct {
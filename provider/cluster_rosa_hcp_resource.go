@@ -0,0 +1,949 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift-online/ocm-sdk-go/logging"
+	"github.com/terraform-redhat/terraform-provider-ocm/provider/common"
+)
+
+var awsAccountIDRE = regexp.MustCompile(`^\d{12}$`)
+
+type ClusterRosaHcpResourceType struct {
+	logger            logging.Logger
+	defaultTags       map[string]string
+	defaultProperties map[string]string
+}
+
+type ClusterRosaHcpResource struct {
+	logger            logging.Logger
+	clusterCollection *cmv1.ClustersClient
+	oidcConfigs       *cmv1.OidcConfigsClient
+	awsInquiries      *cmv1.AWSInquiriesClient
+	dryRun            bool
+	httpClient        *http.Client
+}
+
+func (t *ClusterRosaHcpResourceType) GetSchema(ctx context.Context) (result tfsdk.Schema,
+	diags diag.Diagnostics) {
+	result = tfsdk.Schema{
+		Description: "OpenShift managed cluster using rosa sts with a hosted control plane.",
+		Attributes: map[string]tfsdk.Attribute{
+			"id": {
+				Description: "Unique identifier of the cluster.",
+				Type:        types.StringType,
+				Computed:    true,
+			},
+			"external_id": {
+				Description: "Unique external identifier of the cluster.",
+				Type:        types.StringType,
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
+			"name": {
+				Description: "Name of the cluster. Must be a maximum of 15 characters in length.",
+				Type:        types.StringType,
+				Required:    true,
+				Validators: []tfsdk.AttributeValidator{
+					stringAttributeValidator("Validate cluster name length", func(value string) string {
+						if len(value) > maxClusterNameLength {
+							return fmt.Sprintf("Expected a valid value for 'name' maximum of %d characters in length. "+
+								"Provided Cluster name '%s' is of length '%d'", maxClusterNameLength, value, len(value))
+						}
+						return ""
+					}),
+				},
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
+			"cloud_region": {
+				Description: "Cloud region identifier, for example 'us-east-1'.",
+				Type:        types.StringType,
+				Required:    true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
+			"sts": {
+				Description: "STS Configuration",
+				Attributes:  hcpStsResource(t.logger),
+				Required:    true,
+			},
+			"properties": {
+				Description: "User defined properties. The default properties of the " +
+					"provider are added to these.",
+				Type: types.MapType{
+					ElemType: types.StringType,
+				},
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					MergeDefaultsModifier(t.defaultProperties),
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
+			"tags": {
+				Description: "Apply user defined tags to all resources created in AWS. " +
+					"The default tags of the provider are added to these.",
+				Type: types.MapType{
+					ElemType: types.StringType,
+				},
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					MergeCreateDefaultsModifier(t.defaultTags),
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
+			"etcd_encryption": {
+				Description: "Encrypt etcd data.",
+				Type:        types.BoolType,
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
+			"api_url": {
+				Description: "URL of the API server.",
+				Type:        types.StringType,
+				Computed:    true,
+			},
+			"console_url": {
+				Description: "URL of the console.",
+				Type:        types.StringType,
+				Computed:    true,
+			},
+			"domain": {
+				Description: "DNS Domain of Cluster",
+				Type:        types.StringType,
+				Computed:    true,
+			},
+			"aws_account_id": {
+				Description: "Identifier of the AWS account.",
+				Type:        types.StringType,
+				Required:    true,
+				Validators: []tfsdk.AttributeValidator{
+					awsAccountIDValidator("aws_account_id"),
+				},
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
+			"aws_billing_account_id": {
+				Description: "Identifier of the AWS account that is billed for the hosted control plane.",
+				Type:        types.StringType,
+				Required:    true,
+				Validators: []tfsdk.AttributeValidator{
+					awsAccountIDValidator("aws_billing_account_id"),
+				},
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
+			"aws_subnet_ids": {
+				Description: "Identifiers of the AWS subnets of the cluster. At least one private subnet is required.",
+				Type: types.ListType{
+					ElemType: types.StringType,
+				},
+				Required: true,
+				Validators: []tfsdk.AttributeValidator{
+					&common.AttributeValidator{
+						Desc:   "Validate that subnets are provided",
+						MDDesc: "Validate that subnets are provided",
+						Validator: func(ctx context.Context, req tfsdk.ValidateAttributeRequest, resp *tfsdk.ValidateAttributeResponse) {
+							subnetIDs := types.List{}
+							diag := req.Config.GetAttribute(ctx, req.AttributePath, &subnetIDs)
+							if diag.HasError() {
+								// No attribute to validate
+								return
+							}
+							if !subnetIDs.Unknown && !subnetIDs.Null && len(subnetIDs.Elems) == 0 {
+								resp.Diagnostics.AddAttributeError(req.AttributePath, "Invalid subnets",
+									"Expected at least one subnet identifier in 'aws_subnet_ids'")
+							}
+						},
+					},
+				},
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
+			"kms_key_arn": {
+				Description: "The key ARN is the Amazon Resource Name (ARN) of a AWS KMS (Key Management Service) Key. It is a unique, " +
+					"fully qualified identifier for the AWS KMS Key. A key ARN includes the AWS account, Region, and the key ID.",
+				Type:     types.StringType,
+				Optional: true,
+				Validators: []tfsdk.AttributeValidator{
+					stringAttributeValidator("Validate KMS key ARN", func(value string) string {
+						if value != "" && !kmsArnRE.MatchString(value) {
+							return fmt.Sprintf("Expected a valid value for kms-key-arn matching %s", kmsArnRE)
+						}
+						return ""
+					}),
+				},
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
+			"aws_private_link": {
+				Description: "Provides private connectivity between VPCs, AWS services, and your on-premises networks, without exposing your traffic to the public internet.",
+				Type:        types.BoolType,
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
+			"availability_zones": {
+				Description: "availability zones",
+				Type: types.ListType{
+					ElemType: types.StringType,
+				},
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
+			"machine_cidr": {
+				Description: "Block of IP addresses for nodes.",
+				Type:        types.StringType,
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
+			"service_cidr": {
+				Description: "Block of IP addresses for services.",
+				Type:        types.StringType,
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
+			"pod_cidr": {
+				Description: "Block of IP addresses for pods.",
+				Type:        types.StringType,
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
+			"host_prefix": {
+				Description: "Length of the prefix of the subnet assigned to each node.",
+				Type:        types.Int64Type,
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
+			"version": {
+				Description: "Identifier of the version of OpenShift, for example 'openshift-v4.12.10'.",
+				Type:        types.StringType,
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
+			"disable_waiting_in_destroy": {
+				Description: "Disable addressing cluster state in the destroy resource. Default value is false",
+				Type:        types.BoolType,
+				Optional:    true,
+			},
+			"destroy_timeout": {
+				Description: "Timeout in minutes for addressing cluster state in destroy resource. Default value is 60 minutes.",
+				Type:        types.Int64Type,
+				Optional:    true,
+			},
+			"deletion_policy": deletionPolicyAttribute(),
+			"state": {
+				Description: "State of the cluster.",
+				Type:        types.StringType,
+				Computed:    true,
+			},
+			"provision_error": provisionErrorAttribute(),
+		},
+	}
+	return
+}
+
+func (t *ClusterRosaHcpResourceType) NewResource(ctx context.Context,
+	p tfsdk.Provider) (result tfsdk.Resource, diags diag.Diagnostics) {
+	// Cast the provider interface to the specific implementation:
+	parent := p.(*Provider)
+
+	// Get the collections:
+	clusterCollection := parent.connection.ClustersMgmt().V1().Clusters()
+	oidcConfigs := parent.connection.ClustersMgmt().V1().OidcConfigs()
+	awsInquiries := parent.connection.ClustersMgmt().V1().AWSInquiries()
+
+	// Create the resource:
+	result = &ClusterRosaHcpResource{
+		logger:            parent.logger,
+		clusterCollection: clusterCollection,
+		oidcConfigs:       oidcConfigs,
+		awsInquiries:      awsInquiries,
+		dryRun:            parent.clusterDryRun,
+		httpClient:        parent.httpClient,
+	}
+
+	return
+}
+
+// stringAttributeValidator returns a validator that checks the value of a string attribute, when
+// it is known, with the given function. The function returns the error description, or an empty
+// string if the value is valid.
+func stringAttributeValidator(desc string, check func(value string) string) tfsdk.AttributeValidator {
+	return &common.AttributeValidator{
+		Desc:   desc,
+		MDDesc: desc,
+		Validator: func(ctx context.Context, req tfsdk.ValidateAttributeRequest, resp *tfsdk.ValidateAttributeResponse) {
+			value := types.String{}
+			diag := req.Config.GetAttribute(ctx, req.AttributePath, &value)
+			if diag.HasError() {
+				// No attribute to validate
+				return
+			}
+			if value.Unknown || value.Null {
+				return
+			}
+			errDescription := check(value.Value)
+			if errDescription != "" {
+				resp.Diagnostics.AddAttributeError(req.AttributePath, "Invalid value", errDescription)
+			}
+		},
+	}
+}
+
+func awsAccountIDValidator(attribute string) tfsdk.AttributeValidator {
+	return stringAttributeValidator("Validate AWS account identifier", func(value string) string {
+		if !awsAccountIDRE.MatchString(value) {
+			return fmt.Sprintf("Expected a valid value for '%s' matching %s, got '%s'",
+				attribute, awsAccountIDRE, value)
+		}
+		return ""
+	})
+}
+
+func createHcpClusterObject(ctx context.Context, state *ClusterRosaHcpState,
+	operatorIAMRoles []*cmv1.OperatorIAMRoleBuilder, logger logging.Logger) (*cmv1.Cluster, error) {
+	builder := cmv1.NewCluster()
+	builder.Name(state.Name.Value)
+	builder.CloudProvider(cmv1.NewCloudProvider().ID(awsCloudProvider))
+	builder.Product(cmv1.NewProduct().ID(rosaProduct))
+	builder.Region(cmv1.NewCloudRegion().ID(state.CloudRegion.Value))
+	builder.Hypershift(cmv1.NewHypershift().Enabled(true))
+	// hosted control planes are always highly available:
+	builder.MultiAZ(true)
+	builder.CCS(cmv1.NewCCS().Enabled(true))
+
+	if !state.Properties.Unknown && !state.Properties.Null {
+		properties := map[string]string{}
+		for k, v := range state.Properties.Elems {
+			properties[k] = v.(types.String).Value
+		}
+		builder.Properties(properties)
+	}
+
+	if !state.EtcdEncryption.Unknown && !state.EtcdEncryption.Null {
+		builder.EtcdEncryption(state.EtcdEncryption.Value)
+	}
+
+	if !state.ExternalID.Unknown && !state.ExternalID.Null {
+		builder.ExternalID(state.ExternalID.Value)
+	}
+
+	if !state.AvailabilityZones.Unknown && !state.AvailabilityZones.Null {
+		azs, err := common.StringListToArray(state.AvailabilityZones)
+		if err != nil {
+			return nil, err
+		}
+		builder.Nodes(cmv1.NewClusterNodes().AvailabilityZones(azs...))
+	}
+
+	aws := cmv1.NewAWS()
+	aws.AccountID(state.AWSAccountID.Value)
+	aws.BillingAccountID(state.AWSBillingAccountID.Value)
+
+	subnetIDs, err := common.StringListToArray(state.AWSSubnetIDs)
+	if err != nil {
+		return nil, err
+	}
+	aws.SubnetIDs(subnetIDs...)
+
+	if !state.Tags.Unknown && !state.Tags.Null {
+		tags := map[string]string{}
+		for k, v := range state.Tags.Elems {
+			tags[k] = v.(types.String).Value
+		}
+		aws.Tags(tags)
+	}
+
+	if !state.KMSKeyArn.Unknown && !state.KMSKeyArn.Null && state.KMSKeyArn.Value != "" {
+		aws.KMSKeyArn(state.KMSKeyArn.Value)
+	}
+
+	if !state.AWSPrivateLink.Unknown && !state.AWSPrivateLink.Null {
+		aws.PrivateLink(state.AWSPrivateLink.Value)
+		api := cmv1.NewClusterAPI()
+		if state.AWSPrivateLink.Value {
+			api.Listening(cmv1.ListeningMethodInternal)
+		}
+		builder.API(api)
+	}
+
+	sts := newSTSBuilder(state.Sts.RoleARN, state.Sts.SupportRoleArn, state.Sts.OIDCConfigID,
+		state.Sts.OperatorRolePrefix)
+	sts.InstanceIAMRoles(cmv1.NewInstanceIAMRoles().WorkerRoleARN(state.Sts.InstanceIAMRoles.WorkerRoleARN.Value))
+	sts.OperatorIAMRoles(operatorIAMRoles...)
+	aws.STS(sts)
+	builder.AWS(aws)
+
+	network := cmv1.NewNetwork()
+	if !state.MachineCIDR.Unknown && !state.MachineCIDR.Null {
+		network.MachineCIDR(state.MachineCIDR.Value)
+	}
+	if !state.ServiceCIDR.Unknown && !state.ServiceCIDR.Null {
+		network.ServiceCIDR(state.ServiceCIDR.Value)
+	}
+	if !state.PodCIDR.Unknown && !state.PodCIDR.Null {
+		network.PodCIDR(state.PodCIDR.Value)
+	}
+	if !state.HostPrefix.Unknown && !state.HostPrefix.Null {
+		network.HostPrefix(int(state.HostPrefix.Value))
+	}
+	if !network.Empty() {
+		builder.Network(network)
+	}
+
+	if !state.Version.Unknown && !state.Version.Null {
+		isSupported, err := checkSupportedVersion(state.Version.Value)
+		if err != nil {
+			logger.Error(withLogField(ctx, logFieldError, err.Error()),
+				"Error validating required cluster version")
+			return nil, fmt.Errorf("Can't check if cluster version is supported '%s': %v",
+				state.Version.Value, err)
+		}
+		if !isSupported {
+			logger.Error(withLogField(ctx, logFieldVersion, state.Version.Value),
+				"Cluster version is not supported")
+			return nil, fmt.Errorf("Cluster version '%s' is not supported, the minimal supported version is '%s'",
+				state.Version.Value, MinVersion)
+		}
+		builder.Version(cmv1.NewVersion().ID(state.Version.Value))
+	}
+
+	return builder.Build()
+}
+
+// validateOIDCConfig checks that the OIDC configuration of the plan exists and that it is
+// managed by Red Hat, as required by hosted control planes.
+func (r *ClusterRosaHcpResource) validateOIDCConfig(ctx context.Context, state *ClusterRosaHcpState) error {
+	get, err := r.oidcConfigs.OidcConfig(state.Sts.OIDCConfigID.Value).Get().SendContext(ctx)
+	if err != nil {
+		return fmt.Errorf("can't find OIDC configuration '%s': %v", state.Sts.OIDCConfigID.Value, err)
+	}
+	if !get.Body().Managed() {
+		return fmt.Errorf("OIDC configuration '%s' isn't managed, hosted control plane clusters "+
+			"require a managed OIDC configuration", state.Sts.OIDCConfigID.Value)
+	}
+	return nil
+}
+
+// getOperatorIAMRoles returns the operator roles of the hosted control plane, named after the
+// operator role prefix of the plan.
+func (r *ClusterRosaHcpResource) getOperatorIAMRoles(ctx context.Context,
+	state *ClusterRosaHcpState) ([]*cmv1.OperatorIAMRoleBuilder, error) {
+	list, err := r.awsInquiries.STSCredentialRequests().List().
+		Parameter("is_hypershift", true).
+		SendContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	partition := "aws"
+	installerRoleARN, err := arn.Parse(state.Sts.RoleARN.Value)
+	if err == nil {
+		partition = installerRoleARN.Partition
+	}
+
+	operatorIAMRoles := []*cmv1.OperatorIAMRoleBuilder{}
+	list.Items().Each(func(stsCredentialRequest *cmv1.STSCredentialRequest) bool {
+		operator := stsCredentialRequest.Operator()
+		roleARN := fmt.Sprintf("arn:%s:iam::%s:role/%s", partition, state.AWSAccountID.Value,
+			getRoleName(state.Sts.OperatorRolePrefix.Value, operator))
+		r.logger.Debug(ctx, "Operator '%s' in namespace '%s' uses role '%s'",
+			operator.Name(), operator.Namespace(), roleARN)
+		operatorIAMRoles = append(operatorIAMRoles, cmv1.NewOperatorIAMRole().
+			Name(operator.Name()).
+			Namespace(operator.Namespace()).
+			RoleARN(roleARN))
+		return true
+	})
+
+	return operatorIAMRoles, nil
+}
+
+// ModifyPlan sends the cluster that will be created to the server in dry run mode, so that
+// validation errors are reported while planning.
+func (r *ClusterRosaHcpResource) ModifyPlan(ctx context.Context,
+	request tfsdk.ModifyResourcePlanRequest, response *tfsdk.ModifyResourcePlanResponse) {
+	if !r.dryRun || !shouldDryRunCluster(ctx, r.logger, request) {
+		return
+	}
+
+	// Get the plan:
+	state := &ClusterRosaHcpState{}
+	diags := request.Plan.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	operatorIAMRoles, err := r.getOperatorIAMRoles(ctx, state)
+	if err != nil {
+		response.Diagnostics.AddWarning(
+			"Can't validate cluster",
+			fmt.Sprintf(
+				"Can't validate cluster with name '%s', failed while getting operator roles: %v",
+				state.Name.Value, err,
+			),
+		)
+		return
+	}
+
+	object, err := createHcpClusterObject(ctx, state, operatorIAMRoles, r.logger)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't build cluster",
+			fmt.Sprintf(
+				"Can't build cluster with name '%s': %v",
+				state.Name.Value, err,
+			),
+		)
+		return
+	}
+	dryRunCluster(ctx, r.clusterCollection, object, request.Plan.Schema, &response.Diagnostics)
+}
+
+func (r *ClusterRosaHcpResource) Create(ctx context.Context,
+	request tfsdk.CreateResourceRequest, response *tfsdk.CreateResourceResponse) {
+	// Get the plan:
+	state := &ClusterRosaHcpState{}
+	diags := request.Plan.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	err := r.validateOIDCConfig(ctx, state)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't build cluster",
+			fmt.Sprintf(
+				"Can't build cluster with name '%s', failed while validating OIDC configuration: %v",
+				state.Name.Value, err,
+			),
+		)
+		return
+	}
+
+	operatorIAMRoles, err := r.getOperatorIAMRoles(ctx, state)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't build cluster",
+			fmt.Sprintf(
+				"Can't build cluster with name '%s', failed while getting operator roles: %v",
+				state.Name.Value, err,
+			),
+		)
+		return
+	}
+
+	object, err := createHcpClusterObject(ctx, state, operatorIAMRoles, r.logger)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't build cluster",
+			fmt.Sprintf(
+				"Can't build cluster with name '%s': %v",
+				state.Name.Value, err,
+			),
+		)
+		return
+	}
+
+	add, err := r.clusterCollection.Add().Body(object).SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't create cluster",
+			fmt.Sprintf(
+				"Can't create cluster with name '%s': %v",
+				state.Name.Value, err,
+			),
+		)
+		return
+	}
+	object = add.Body()
+	ctx = withClusterLogField(ctx, object.ID())
+
+	// Save the state:
+	err = populateRosaHcpClusterState(ctx, object, state, r.logger, DefaultHttpClient{r.httpClient})
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't populate cluster state",
+			fmt.Sprintf(
+				"Received error %v", err,
+			),
+		)
+		return
+	}
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *ClusterRosaHcpResource) Read(ctx context.Context, request tfsdk.ReadResourceRequest,
+	response *tfsdk.ReadResourceResponse) {
+	// Get the current state:
+	state := &ClusterRosaHcpState{}
+	diags := request.State.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	ctx = withClusterLogField(ctx, state.ID.Value)
+
+	// Find the cluster:
+	get, err := r.clusterCollection.Cluster(state.ID.Value).Get().SendContext(ctx)
+	if err != nil {
+		if common.IsStatusNotFound(err) {
+			r.logger.Warn(ctx, "Cluster wasn't found, removing it from the state")
+			response.State.RemoveResource(ctx)
+			return
+		}
+		response.Diagnostics.AddError(
+			"Can't find cluster",
+			fmt.Sprintf(
+				"Can't find cluster with identifier '%s': %v",
+				state.ID.Value, err,
+			),
+		)
+		return
+	}
+	object := get.Body()
+
+	// Save the state:
+	err = populateRosaHcpClusterState(ctx, object, state, r.logger, DefaultHttpClient{r.httpClient})
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't populate cluster state",
+			fmt.Sprintf(
+				"Received error %v", err,
+			),
+		)
+		return
+	}
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *ClusterRosaHcpResource) Update(ctx context.Context, request tfsdk.UpdateResourceRequest,
+	response *tfsdk.UpdateResourceResponse) {
+	var diags diag.Diagnostics
+
+	// Get the state:
+	state := &ClusterRosaHcpState{}
+	diags = request.State.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	ctx = withClusterLogField(ctx, state.ID.Value)
+
+	// Get the plan:
+	plan := &ClusterRosaHcpState{}
+	diags = request.Plan.Get(ctx, plan)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// All the attributes of the cluster are blocked for updating, so only the attributes that
+	// are used by the provider itself can change:
+	state.DisableWaitingInDestroy = plan.DisableWaitingInDestroy
+	state.DestroyTimeout = plan.DestroyTimeout
+	state.DeletionPolicy = plan.DeletionPolicy
+
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *ClusterRosaHcpResource) Delete(ctx context.Context, request tfsdk.DeleteResourceRequest,
+	response *tfsdk.DeleteResourceResponse) {
+	// Get the state:
+	state := &ClusterRosaHcpState{}
+	diags := request.State.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	ctx = withClusterLogField(ctx, state.ID.Value)
+
+	switch getDeletionPolicy(state.DeletionPolicy) {
+	case deletionPolicyProtect:
+		response.Diagnostics.AddError(protectedClusterError(state.ID.Value))
+		return
+	case deletionPolicyOrphan:
+		r.logger.Info(ctx, "Cluster has the '%s' deletion policy, removing it from the state without deleting it",
+			deletionPolicyOrphan)
+		response.State.RemoveResource(ctx)
+		return
+	}
+
+	// Send the request to delete the cluster:
+	resource := r.clusterCollection.Cluster(state.ID.Value)
+	_, err := resource.Delete().SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't delete cluster",
+			fmt.Sprintf(
+				"Can't delete cluster with identifier '%s': %v",
+				state.ID.Value, err,
+			),
+		)
+		return
+	}
+	if !state.DisableWaitingInDestroy.Unknown && !state.DisableWaitingInDestroy.Null && state.DisableWaitingInDestroy.Value {
+		r.logger.Info(ctx, "Waiting for destroy to be completed, is disabled")
+	} else {
+		timeout := defaultTimeoutInMinutes
+		if !state.DestroyTimeout.Unknown && !state.DestroyTimeout.Null {
+			if state.DestroyTimeout.Value <= 0 {
+				response.Diagnostics.AddWarning(nonPositiveTimeoutSummary, fmt.Sprintf(nonPositiveTimeoutFormat, state.ID.Value))
+			} else {
+				timeout = state.DestroyTimeout.Value
+			}
+		}
+		isNotFound, err := retryClusterNotFoundWithTimeout(3, 1*time.Minute, ctx, timeout, resource, r.logger)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't poll cluster state",
+				fmt.Sprintf(
+					"Can't poll state of cluster with identifier '%s': %v",
+					state.ID.Value, err,
+				),
+			)
+			return
+		}
+
+		if !isNotFound {
+			response.Diagnostics.AddWarning(
+				"Cluster wasn't deleted yet",
+				fmt.Sprintf("The cluster with identifier '%s' is not deleted yet, but the polling finished due to a timeout", state.ID.Value),
+			)
+		}
+	}
+
+	// Remove the state:
+	response.State.RemoveResource(ctx)
+}
+
+func (r *ClusterRosaHcpResource) ImportState(ctx context.Context, request tfsdk.ImportResourceStateRequest,
+	response *tfsdk.ImportResourceStateResponse) {
+	// Try to retrieve the object:
+	get, err := r.clusterCollection.Cluster(request.ID).Get().SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't find cluster",
+			fmt.Sprintf(
+				"Can't find cluster with identifier '%s': %v",
+				request.ID, err,
+			),
+		)
+		return
+	}
+	object := get.Body()
+
+	// Save the state:
+	state := &ClusterRosaHcpState{}
+	err = populateRosaHcpClusterState(ctx, object, state, r.logger, DefaultHttpClient{r.httpClient})
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't populate cluster state",
+			fmt.Sprintf(
+				"Received error %v", err,
+			),
+		)
+		return
+	}
+
+	diags := response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+// populateRosaHcpClusterState copies the data from the API object to the Terraform state.
+func populateRosaHcpClusterState(ctx context.Context, object *cmv1.Cluster, state *ClusterRosaHcpState,
+	logger logging.Logger, httpClient HttpClient) error {
+	if !object.Hypershift().Enabled() {
+		return errors.New("the cluster doesn't have a hosted control plane, " +
+			"use the 'ocm_cluster_rosa_classic' resource to manage it")
+	}
+
+	state.ID = types.String{
+		Value: object.ID(),
+	}
+	state.ExternalID = types.String{
+		Value: object.ExternalID(),
+	}
+	state.Name = types.String{
+		Value: object.Name(),
+	}
+	state.CloudRegion = types.String{
+		Value: object.Region().ID(),
+	}
+	state.Properties = types.Map{
+		ElemType: types.StringType,
+		Elems:    map[string]attr.Value{},
+	}
+	for k, v := range object.Properties() {
+		state.Properties.Elems[k] = types.String{
+			Value: v,
+		}
+	}
+	state.Tags = types.Map{
+		ElemType: types.StringType,
+		Elems:    map[string]attr.Value{},
+	}
+	for k, v := range object.AWS().Tags() {
+		state.Tags.Elems[k] = types.String{
+			Value: v,
+		}
+	}
+	state.APIURL = types.String{
+		Value: object.API().URL(),
+	}
+	state.ConsoleURL = types.String{
+		Value: object.Console().URL(),
+	}
+	state.Domain = types.String{
+		Value: fmt.Sprintf("%s.%s", object.Name(), object.DNS().BaseDomain()),
+	}
+	state.EtcdEncryption = types.Bool{
+		Value: object.EtcdEncryption(),
+	}
+	state.AvailabilityZones = common.StringArrayToList(object.Nodes().AvailabilityZones())
+
+	awsAccountID, ok := object.AWS().GetAccountID()
+	if ok {
+		state.AWSAccountID = types.String{
+			Value: awsAccountID,
+		}
+	}
+	billingAccountID, ok := object.AWS().GetBillingAccountID()
+	if ok {
+		state.AWSBillingAccountID = types.String{
+			Value: billingAccountID,
+		}
+	}
+	state.AWSPrivateLink = types.Bool{
+		Value: object.AWS().PrivateLink(),
+	}
+	kmsKeyArn, ok := object.AWS().GetKMSKeyArn()
+	if ok {
+		state.KMSKeyArn = types.String{
+			Value: kmsKeyArn,
+		}
+	}
+	subnetIDs, ok := object.AWS().GetSubnetIDs()
+	if ok {
+		state.AWSSubnetIDs = common.StringArrayToList(subnetIDs)
+	}
+
+	sts, ok := object.AWS().GetSTS()
+	if ok {
+		if state.Sts == nil {
+			state.Sts = &HcpSts{}
+		}
+		state.Sts.OIDCEndpointURL, state.Sts.Thumbprint = getOIDCEndpointState(ctx, sts, logger, httpClient)
+		state.Sts.RoleARN = types.String{
+			Value: sts.RoleARN(),
+		}
+		state.Sts.SupportRoleArn = types.String{
+			Value: sts.SupportRoleARN(),
+		}
+		state.Sts.InstanceIAMRoles.WorkerRoleARN = types.String{
+			Value: sts.InstanceIAMRoles().WorkerRoleARN(),
+		}
+		operatorRolePrefix, ok := sts.GetOperatorRolePrefix()
+		if ok && operatorRolePrefix != "" {
+			state.Sts.OperatorRolePrefix = types.String{
+				Value: operatorRolePrefix,
+			}
+		}
+		state.Sts.OIDCConfigID = types.String{
+			Value: sts.OidcConfig().ID(),
+		}
+		operatorRoleARNs := []string{}
+		for _, operatorIAMRole := range sts.OperatorIAMRoles() {
+			operatorRoleARNs = append(operatorRoleARNs, operatorIAMRole.RoleARN())
+		}
+		state.Sts.OperatorIAMRoles = common.StringArrayToList(operatorRoleARNs)
+	}
+
+	state.MachineCIDR = types.String{
+		Value: object.Network().MachineCIDR(),
+	}
+	state.ServiceCIDR = types.String{
+		Value: object.Network().ServiceCIDR(),
+	}
+	state.PodCIDR = types.String{
+		Value: object.Network().PodCIDR(),
+	}
+	state.HostPrefix = types.Int64{
+		Value: int64(object.Network().HostPrefix()),
+	}
+	state.Version = types.String{
+		Value: object.Version().ID(),
+	}
+	state.State = types.String{
+		Value: string(object.State()),
+	}
+	state.ProvisionError = provisionErrorState(object)
+
+	return nil
+}
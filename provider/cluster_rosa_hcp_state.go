@@ -0,0 +1,66 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type ClusterRosaHcpState struct {
+	APIURL                  types.String `tfsdk:"api_url"`
+	AWSAccountID            types.String `tfsdk:"aws_account_id"`
+	AWSBillingAccountID     types.String `tfsdk:"aws_billing_account_id"`
+	AWSSubnetIDs            types.List   `tfsdk:"aws_subnet_ids"`
+	AWSPrivateLink          types.Bool   `tfsdk:"aws_private_link"`
+	AvailabilityZones       types.List   `tfsdk:"availability_zones"`
+	Sts                     *HcpSts      `tfsdk:"sts"`
+	EtcdEncryption          types.Bool   `tfsdk:"etcd_encryption"`
+	CloudRegion             types.String `tfsdk:"cloud_region"`
+	ConsoleURL              types.String `tfsdk:"console_url"`
+	Domain                  types.String `tfsdk:"domain"`
+	HostPrefix              types.Int64  `tfsdk:"host_prefix"`
+	ID                      types.String `tfsdk:"id"`
+	KMSKeyArn               types.String `tfsdk:"kms_key_arn"`
+	ExternalID              types.String `tfsdk:"external_id"`
+	MachineCIDR             types.String `tfsdk:"machine_cidr"`
+	Name                    types.String `tfsdk:"name"`
+	PodCIDR                 types.String `tfsdk:"pod_cidr"`
+	Properties              types.Map    `tfsdk:"properties"`
+	Tags                    types.Map    `tfsdk:"tags"`
+	ServiceCIDR             types.String `tfsdk:"service_cidr"`
+	State                   types.String `tfsdk:"state"`
+	ProvisionError          types.String `tfsdk:"provision_error"`
+	Version                 types.String `tfsdk:"version"`
+	DisableWaitingInDestroy types.Bool   `tfsdk:"disable_waiting_in_destroy"`
+	DestroyTimeout          types.Int64  `tfsdk:"destroy_timeout"`
+	DeletionPolicy          types.String `tfsdk:"deletion_policy"`
+}
+
+type HcpSts struct {
+	OIDCEndpointURL    types.String       `tfsdk:"oidc_endpoint_url"`
+	OIDCConfigID       types.String       `tfsdk:"oidc_config_id"`
+	Thumbprint         types.String       `tfsdk:"thumbprint"`
+	RoleARN            types.String       `tfsdk:"role_arn"`
+	SupportRoleArn     types.String       `tfsdk:"support_role_arn"`
+	InstanceIAMRoles   HcpInstanceIAMRole `tfsdk:"instance_iam_roles"`
+	OperatorRolePrefix types.String       `tfsdk:"operator_role_prefix"`
+	OperatorIAMRoles   types.List         `tfsdk:"operator_iam_roles"`
+}
+
+type HcpInstanceIAMRole struct {
+	WorkerRoleARN types.String `tfsdk:"worker_role_arn"`
+}
@@ -45,8 +45,11 @@ type ClusterState struct {
 	ServiceCIDR        types.String `tfsdk:"service_cidr"`
 	Proxy              *Proxy       `tfsdk:"proxy"`
 	State              types.String `tfsdk:"state"`
+	ProvisionError     types.String `tfsdk:"provision_error"`
+	Hibernating        types.Bool   `tfsdk:"hibernating"`
 	Version            types.String `tfsdk:"version"`
 	Wait               types.Bool   `tfsdk:"wait"`
+	DeletionPolicy     types.String `tfsdk:"deletion_policy"`
 }
 
 type Proxy struct {
@@ -0,0 +1,668 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/terraform-redhat/terraform-provider-ocm/provider/common"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift-online/ocm-sdk-go/logging"
+)
+
+const (
+	automaticScheduleType       = "automatic"
+	nodeDrainGracePeriodUnit    = "minutes"
+	upgradePolicyImportIDFormat = "<cluster_id>,<policy_id>"
+)
+
+type ClusterUpgradePolicyResourceType struct {
+	logger logging.Logger
+}
+
+type ClusterUpgradePolicyResource struct {
+	logger     logging.Logger
+	collection *cmv1.ClustersClient
+}
+
+func (t *ClusterUpgradePolicyResourceType) GetSchema(ctx context.Context) (result tfsdk.Schema,
+	diags diag.Diagnostics) {
+	result = tfsdk.Schema{
+		Description: "Upgrade policy of a cluster.",
+		Attributes: map[string]tfsdk.Attribute{
+			"cluster": {
+				Description: "Identifier of the cluster.",
+				Type:        types.StringType,
+				Required:    true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
+			"id": {
+				Description: "Unique identifier of the upgrade policy.",
+				Type:        types.StringType,
+				Computed:    true,
+			},
+			"schedule_type": {
+				Description: "Schedule type of the upgrade policy, either 'automatic' or 'manual'.",
+				Type:        types.StringType,
+				Required:    true,
+				Validators:  scheduleTypeValidators(),
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					tfsdk.RequiresReplace(),
+				},
+			},
+			"schedule": {
+				Description: "Cron expression of the recurring upgrades, for example '0 2 * * 6'. " +
+					"Required when the schedule type is 'automatic'.",
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"version": {
+				Description: "Raw identifier of the version to upgrade to, for example '4.12.10'. " +
+					"Required when the schedule type is 'manual'.",
+				Type:     types.StringType,
+				Optional: true,
+				Computed: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					tfsdk.RequiresReplace(),
+				},
+			},
+			"next_run": {
+				Description: "Time of the next upgrade in RFC3339 format, for example '2023-04-20T12:00:00Z'. " +
+					"For manual upgrades the default value is 10 minutes after the creation of the policy.",
+				Type:     types.StringType,
+				Optional: true,
+				Computed: true,
+			},
+			"enable_minor_version_upgrades": {
+				Description: "Enables automatic upgrades to new minor versions, and not only to patch versions.",
+				Type:        types.BoolType,
+				Optional:    true,
+			},
+			"node_drain_grace_period": {
+				Description: "Time in minutes that nodes are allowed to drain before being forcibly " +
+					"removed during the upgrade. It applies to the whole cluster, and it isn't " +
+					"restored when the upgrade policy is destroyed.",
+				Type:     types.Int64Type,
+				Optional: true,
+			},
+			"state": {
+				Description: "State of the upgrade policy, for example 'scheduled' or 'started'.",
+				Type:        types.StringType,
+				Computed:    true,
+			},
+		},
+	}
+	return
+}
+
+func (t *ClusterUpgradePolicyResourceType) NewResource(ctx context.Context,
+	p tfsdk.Provider) (result tfsdk.Resource, diags diag.Diagnostics) {
+	// Cast the provider interface to the specific implementation: use it directly when needed.
+	parent := p.(*Provider)
+
+	// Get the collection of clusters:
+	collection := parent.connection.ClustersMgmt().V1().Clusters()
+
+	// Create the resource:
+	result = &ClusterUpgradePolicyResource{
+		logger:     parent.logger,
+		collection: collection,
+	}
+
+	return
+}
+
+func scheduleTypeValidators() []tfsdk.AttributeValidator {
+	return []tfsdk.AttributeValidator{
+		&common.AttributeValidator{
+			Desc:   "Validate schedule type",
+			MDDesc: "Validate schedule type",
+			Validator: func(ctx context.Context, req tfsdk.ValidateAttributeRequest, resp *tfsdk.ValidateAttributeResponse) {
+				scheduleType := types.String{}
+				diag := req.Config.GetAttribute(ctx, req.AttributePath, &scheduleType)
+				if diag.HasError() {
+					// No attribute to validate
+					return
+				}
+				if scheduleType.Unknown || scheduleType.Null {
+					return
+				}
+				if scheduleType.Value != automaticScheduleType && scheduleType.Value != manualScheduleType {
+					resp.Diagnostics.AddAttributeError(req.AttributePath, "Invalid schedule type",
+						fmt.Sprintf("Expected schedule type to be '%s' or '%s', got '%s'",
+							automaticScheduleType, manualScheduleType, scheduleType.Value),
+					)
+				}
+			},
+		},
+	}
+}
+
+func (r *ClusterUpgradePolicyResource) Create(ctx context.Context,
+	request tfsdk.CreateResourceRequest, response *tfsdk.CreateResourceResponse) {
+	// Get the plan:
+	state := &ClusterUpgradePolicyState{}
+	diags := request.Plan.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+
+	builder, errMsg := getUpgradePolicyBuilder(state)
+	if errMsg != "" {
+		response.Diagnostics.AddError(
+			"Can't build upgrade policy",
+			fmt.Sprintf(
+				"Can't build upgrade policy for cluster '%s', %s", state.Cluster.Value, errMsg,
+			),
+		)
+		return
+	}
+	builder.UpgradeType(osdUpgradeType).ScheduleType(state.ScheduleType.Value)
+	if !state.Version.Unknown && !state.Version.Null {
+		builder.Version(state.Version.Value)
+	}
+	if state.ScheduleType.Value == manualScheduleType && (state.NextRun.Unknown || state.NextRun.Null) {
+		builder.NextRun(time.Now().UTC().Add(upgradeScheduleDelay))
+	}
+
+	// Wait till the cluster is ready:
+	resource := r.collection.Cluster(state.Cluster.Value)
+	pollCtx, cancel := context.WithTimeout(ctx, 1*time.Hour)
+	defer cancel()
+	_, err := resource.Poll().
+		Interval(30 * time.Second).
+		Predicate(func(get *cmv1.ClusterGetResponse) bool {
+			return get.Body().State() == cmv1.ClusterStateReady
+		}).
+		StartContext(pollCtx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't poll cluster state",
+			fmt.Sprintf(
+				"Can't poll state of cluster with identifier '%s': %v",
+				state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+
+	err = r.updateNodeDrainGracePeriod(ctx, state.Cluster.Value, nil, state)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't update node drain grace period",
+			fmt.Sprintf(
+				"Can't update node drain grace period of cluster '%s': %v",
+				state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+
+	object, err := builder.Build()
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't build upgrade policy",
+			fmt.Sprintf(
+				"Can't build upgrade policy for cluster '%s': %v",
+				state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+
+	add, err := resource.UpgradePolicies().Add().Body(object).SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't create upgrade policy",
+			fmt.Sprintf(
+				"Can't create upgrade policy for cluster '%s': %v",
+				state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+	object = add.Body()
+
+	// Save the state:
+	err = r.populateState(ctx, object, state)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't populate upgrade policy state",
+			fmt.Sprintf(
+				"Can't populate state of upgrade policy '%s' for cluster '%s': %v",
+				object.ID(), state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *ClusterUpgradePolicyResource) Read(ctx context.Context, request tfsdk.ReadResourceRequest,
+	response *tfsdk.ReadResourceResponse) {
+	// Get the current state:
+	state := &ClusterUpgradePolicyState{}
+	diags := request.State.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+	ctx = withObjectLogField(ctx, state.ID.Value)
+
+	// Find the upgrade policy:
+	get, err := r.collection.Cluster(state.Cluster.Value).
+		UpgradePolicies().
+		UpgradePolicy(state.ID.Value).
+		Get().
+		SendContext(ctx)
+	if err != nil {
+		if common.IsStatusNotFound(err) {
+			r.logger.Warn(ctx, "Upgrade policy wasn't found, removing it from the state")
+			response.State.RemoveResource(ctx)
+			return
+		}
+		response.Diagnostics.AddError(
+			"Can't find upgrade policy",
+			fmt.Sprintf(
+				"Can't find upgrade policy with identifier '%s' for "+
+					"cluster '%s': %v",
+				state.ID.Value, state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+	object := get.Body()
+
+	// Refresh the node drain grace period only when it is managed by this resource:
+	if !state.NodeDrainGracePeriod.Unknown && !state.NodeDrainGracePeriod.Null {
+		getCluster, err := r.collection.Cluster(state.Cluster.Value).Get().SendContext(ctx)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't find cluster",
+				fmt.Sprintf(
+					"Can't find cluster with identifier '%s': %v",
+					state.Cluster.Value, err,
+				),
+			)
+			return
+		}
+		state.NodeDrainGracePeriod = types.Int64{
+			Value: int64(getCluster.Body().NodeDrainGracePeriod().Value()),
+		}
+	}
+
+	// Save the state:
+	err = r.populateState(ctx, object, state)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't populate upgrade policy state",
+			fmt.Sprintf(
+				"Can't populate state of upgrade policy '%s' for cluster '%s': %v",
+				state.ID.Value, state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *ClusterUpgradePolicyResource) Update(ctx context.Context, request tfsdk.UpdateResourceRequest,
+	response *tfsdk.UpdateResourceResponse) {
+	var diags diag.Diagnostics
+
+	// Get the state:
+	state := &ClusterUpgradePolicyState{}
+	diags = request.State.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+	ctx = withObjectLogField(ctx, state.ID.Value)
+
+	// Get the plan:
+	plan := &ClusterUpgradePolicyState{}
+	diags = request.Plan.Get(ctx, plan)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	builder, errMsg := getUpgradePolicyBuilder(plan)
+	if errMsg != "" {
+		response.Diagnostics.AddError(
+			"Can't update upgrade policy",
+			fmt.Sprintf(
+				"Can't update upgrade policy for cluster '%s', %s", state.Cluster.Value, errMsg,
+			),
+		)
+		return
+	}
+
+	err := r.updateNodeDrainGracePeriod(ctx, state.Cluster.Value, state, plan)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't update node drain grace period",
+			fmt.Sprintf(
+				"Can't update node drain grace period of cluster '%s': %v",
+				state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+
+	resource := r.collection.Cluster(state.Cluster.Value).
+		UpgradePolicies().
+		UpgradePolicy(state.ID.Value)
+	var object *cmv1.UpgradePolicy
+	if builder.Empty() {
+		// Nothing to patch in the policy itself:
+		get, err := resource.Get().SendContext(ctx)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't find upgrade policy",
+				fmt.Sprintf(
+					"Can't find upgrade policy with identifier '%s' for "+
+						"cluster '%s': %v",
+					state.ID.Value, state.Cluster.Value, err,
+				),
+			)
+			return
+		}
+		object = get.Body()
+	} else {
+		policy, err := builder.Build()
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't update upgrade policy",
+				fmt.Sprintf(
+					"Can't update upgrade policy for cluster '%s': %v", state.Cluster.Value, err,
+				),
+			)
+			return
+		}
+		update, err := resource.Update().Body(policy).SendContext(ctx)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Failed to update upgrade policy",
+				fmt.Sprintf(
+					"Failed to update upgrade policy '%s' on cluster '%s': %v",
+					state.ID.Value, state.Cluster.Value, err,
+				),
+			)
+			return
+		}
+		object = update.Body()
+	}
+
+	// Update the state with the plan values that the API doesn't return when they are not set:
+	state.Schedule = plan.Schedule
+	state.EnableMinorVersionUpgrades = plan.EnableMinorVersionUpgrades
+	state.NodeDrainGracePeriod = plan.NodeDrainGracePeriod
+	if !plan.NextRun.Unknown && !plan.NextRun.Null {
+		state.NextRun = plan.NextRun
+	}
+
+	// Save the state:
+	err = r.populateState(ctx, object, state)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't populate upgrade policy state",
+			fmt.Sprintf(
+				"Can't populate state of upgrade policy '%s' for cluster '%s': %v",
+				state.ID.Value, state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *ClusterUpgradePolicyResource) Delete(ctx context.Context, request tfsdk.DeleteResourceRequest,
+	response *tfsdk.DeleteResourceResponse) {
+	// Get the state:
+	state := &ClusterUpgradePolicyState{}
+	diags := request.State.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+	ctx = withObjectLogField(ctx, state.ID.Value)
+
+	// Send the request to delete the upgrade policy:
+	resource := r.collection.Cluster(state.Cluster.Value).
+		UpgradePolicies().
+		UpgradePolicy(state.ID.Value)
+	_, err := resource.Delete().SendContext(ctx)
+	if err != nil && common.IsStatusNotFound(err) {
+		// The server removes manual upgrade policies once they are completed, so this isn't
+		// an error:
+		r.logger.Info(ctx, "Upgrade policy was already removed")
+		err = nil
+	}
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't delete upgrade policy",
+			fmt.Sprintf(
+				"Can't delete upgrade policy with identifier '%s' for "+
+					"cluster '%s': %v",
+				state.ID.Value, state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+
+	// Remove the state:
+	response.State.RemoveResource(ctx)
+}
+
+func (r *ClusterUpgradePolicyResource) ImportState(ctx context.Context, request tfsdk.ImportResourceStateRequest,
+	response *tfsdk.ImportResourceStateResponse) {
+	fields, err := common.SplitImportID(request.ID, upgradePolicyImportIDFormat)
+	if err != nil {
+		response.Diagnostics.AddError("Invalid import identifier", err.Error())
+		return
+	}
+	clusterID := fields[0]
+	policyID := fields[1]
+
+	// Try to retrieve the object:
+	get, err := r.collection.Cluster(clusterID).
+		UpgradePolicies().
+		UpgradePolicy(policyID).
+		Get().
+		SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't find upgrade policy",
+			fmt.Sprintf(
+				"Can't find upgrade policy with identifier '%s' for "+
+					"cluster '%s': %v",
+				policyID, clusterID, err,
+			),
+		)
+		return
+	}
+	object := get.Body()
+
+	// Save the state:
+	state := &ClusterUpgradePolicyState{
+		Cluster: types.String{
+			Value: clusterID,
+		},
+	}
+	err = r.populateState(ctx, object, state)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't populate upgrade policy state",
+			fmt.Sprintf(
+				"Can't populate state of upgrade policy '%s' for cluster '%s': %v",
+				policyID, clusterID, err,
+			),
+		)
+		return
+	}
+	diags := response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+// getUpgradePolicyBuilder validates the schedule of the given state and returns a builder that
+// contains the attributes that can be changed after the creation of the policy.
+func getUpgradePolicyBuilder(state *ClusterUpgradePolicyState) (builder *cmv1.UpgradePolicyBuilder, errMsg string) {
+	builder = cmv1.NewUpgradePolicy()
+	isScheduleSet := !state.Schedule.Unknown && !state.Schedule.Null
+	isVersionSet := !state.Version.Unknown && !state.Version.Null
+	switch state.ScheduleType.Value {
+	case automaticScheduleType:
+		if !isScheduleSet {
+			return nil, "when using an automatic schedule type, should set value for schedule"
+		}
+		builder.Schedule(state.Schedule.Value)
+		if !state.EnableMinorVersionUpgrades.Unknown && !state.EnableMinorVersionUpgrades.Null {
+			builder.EnableMinorVersionUpgrades(state.EnableMinorVersionUpgrades.Value)
+		}
+	case manualScheduleType:
+		if isScheduleSet {
+			return nil, "when using a manual schedule type, can't set schedule"
+		}
+		if !isVersionSet {
+			return nil, "when using a manual schedule type, should set value for version"
+		}
+		if !state.EnableMinorVersionUpgrades.Unknown && !state.EnableMinorVersionUpgrades.Null {
+			return nil, "when using a manual schedule type, can't set enable_minor_version_upgrades"
+		}
+	default:
+		return nil, fmt.Sprintf("unsupported schedule type '%s'", state.ScheduleType.Value)
+	}
+
+	if !state.NextRun.Unknown && !state.NextRun.Null {
+		nextRun, err := time.Parse(time.RFC3339, state.NextRun.Value)
+		if err != nil {
+			return nil, fmt.Sprintf("expected a valid RFC3339 value for next_run: %v", err)
+		}
+		builder.NextRun(nextRun)
+	}
+
+	return builder, ""
+}
+
+// updateNodeDrainGracePeriod patches the node drain grace period of the cluster if it was
+// changed between the given state and plan.
+func (r *ClusterUpgradePolicyResource) updateNodeDrainGracePeriod(ctx context.Context, clusterID string,
+	state, plan *ClusterUpgradePolicyState) error {
+	current := types.Int64{Null: true}
+	if state != nil {
+		current = state.NodeDrainGracePeriod
+	}
+	gracePeriod, ok := common.ShouldPatchInt(current, plan.NodeDrainGracePeriod)
+	if !ok {
+		return nil
+	}
+	r.logger.Debug(withClusterLogField(ctx, clusterID),
+		"Setting node drain grace period of cluster to %d minutes", gracePeriod)
+	patch, err := cmv1.NewCluster().
+		NodeDrainGracePeriod(
+			cmv1.NewValue().Unit(nodeDrainGracePeriodUnit).Value(float64(gracePeriod)),
+		).
+		Build()
+	if err != nil {
+		return err
+	}
+	_, err = r.collection.Cluster(clusterID).Update().Body(patch).SendContext(ctx)
+	return err
+}
+
+// populateState copies the data from the API object to the Terraform state.
+func (r *ClusterUpgradePolicyResource) populateState(ctx context.Context, object *cmv1.UpgradePolicy,
+	state *ClusterUpgradePolicyState) error {
+	state.ID = types.String{
+		Value: object.ID(),
+	}
+	state.ScheduleType = types.String{
+		Value: object.ScheduleType(),
+	}
+
+	schedule, ok := object.GetSchedule()
+	if ok && schedule != "" {
+		state.Schedule = types.String{
+			Value: schedule,
+		}
+	}
+
+	version, ok := object.GetVersion()
+	if ok {
+		state.Version = types.String{
+			Value: version,
+		}
+	} else if state.Version.Unknown {
+		state.Version = types.String{
+			Null: true,
+		}
+	}
+
+	nextRun, ok := object.GetNextRun()
+	if ok {
+		// Keep the value of the configuration if it is the same time written differently:
+		current, err := time.Parse(time.RFC3339, state.NextRun.Value)
+		if state.NextRun.Unknown || state.NextRun.Null || err != nil || !current.Equal(nextRun) {
+			state.NextRun = types.String{
+				Value: nextRun.UTC().Format(time.RFC3339),
+			}
+		}
+	} else if state.NextRun.Unknown {
+		state.NextRun = types.String{
+			Null: true,
+		}
+	}
+
+	enableMinorVersionUpgrades, ok := object.GetEnableMinorVersionUpgrades()
+	if ok && enableMinorVersionUpgrades {
+		state.EnableMinorVersionUpgrades = types.Bool{
+			Value: true,
+		}
+	}
+
+	get, err := r.collection.Cluster(state.Cluster.Value).
+		UpgradePolicies().
+		UpgradePolicy(object.ID()).
+		State().
+		Get().
+		SendContext(ctx)
+	if err != nil {
+		return err
+	}
+	state.State = types.String{
+		Value: string(get.Body().Value()),
+	}
+
+	return nil
+}
@@ -0,0 +1,192 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/terraform-redhat/terraform-provider-ocm/provider/common"
+)
+
+// waitTargetStates are the states of the cluster that can be used as targets of the waiter.
+var waitTargetStates = []string{
+	string(cmv1.ClusterStateInstalling),
+	string(cmv1.ClusterStateReady),
+	string(cmv1.ClusterStateHibernating),
+}
+
+// laterClusterStates contains, for the target states that clusters leave as part of their normal
+// lifecycle, the states that come later and that therefore also satisfy the target. For example,
+// a cluster that is ready has already been installing, and waiting for it to be installing again
+// would never finish.
+var laterClusterStates = map[cmv1.ClusterState][]cmv1.ClusterState{
+	cmv1.ClusterStateInstalling: {
+		cmv1.ClusterStateReady,
+		cmv1.ClusterStatePoweringDown,
+		cmv1.ClusterStateHibernating,
+		cmv1.ClusterStateResuming,
+	},
+}
+
+// lookupHost is the function used to check if the API URL of a cluster can be resolved. It is a
+// variable so that it can be replaced in tests.
+var lookupHost = net.DefaultResolver.LookupHost
+
+// clusterWaitTarget describes what the waiter waits for: the cluster being in one of the target
+// states, and the optional conditions being satisfied.
+type clusterWaitTarget struct {
+	states                   []cmv1.ClusterState
+	apiURLResolvable         bool
+	consoleURLSet            bool
+	defaultMachinePoolScaled bool
+}
+
+// readyClusterWaitTarget waits till the cluster is ready, without additional conditions.
+var readyClusterWaitTarget = &clusterWaitTarget{
+	states: []cmv1.ClusterState{
+		cmv1.ClusterStateReady,
+	},
+}
+
+// newClusterWaitTarget creates the wait target from the state of the waiter resource. When no
+// target states are given the waiter waits till the cluster is ready.
+func newClusterWaitTarget(state *ClusterWaiterState) *clusterWaitTarget {
+	target := &clusterWaitTarget{}
+	if !state.TargetStates.Unknown && !state.TargetStates.Null {
+		for _, value := range state.TargetStates.Elems {
+			target.states = append(target.states, cmv1.ClusterState(value.(types.String).Value))
+		}
+	}
+	if len(target.states) == 0 {
+		target.states = readyClusterWaitTarget.states
+	}
+	if state.Conditions != nil {
+		target.apiURLResolvable = state.Conditions.APIURLResolvable.Value
+		target.consoleURLSet = state.Conditions.ConsoleURLSet.Value
+		target.defaultMachinePoolScaled = state.Conditions.DefaultMachinePoolScaled.Value
+	}
+	return target
+}
+
+// reached checks if the given cluster has reached the target. When it hasn't it also returns a
+// description of what is still pending.
+func (t *clusterWaitTarget) reached(ctx context.Context, object *cmv1.Cluster) (result bool,
+	pending string) {
+	if !t.inTargetState(object.State()) {
+		pending = fmt.Sprintf("cluster state is '%s'", object.State())
+		return
+	}
+	if t.consoleURLSet && object.Console().URL() == "" {
+		pending = "console URL isn't set"
+		return
+	}
+	if t.apiURLResolvable && !isHostResolvable(ctx, object.API().URL()) {
+		pending = fmt.Sprintf("API URL '%s' can't be resolved", object.API().URL())
+		return
+	}
+	if t.defaultMachinePoolScaled {
+		desired := object.Nodes().Compute()
+		if autoscaling, ok := object.Nodes().GetAutoscaleCompute(); ok {
+			desired = autoscaling.MinReplicas()
+		}
+		current := object.Status().CurrentCompute()
+		if current < desired {
+			pending = fmt.Sprintf(
+				"default machine pool has %d of %d compute nodes",
+				current, desired,
+			)
+			return
+		}
+	}
+	result = true
+	return
+}
+
+// inTargetState checks if the given state is one of the target states, or comes later in the
+// lifecycle of the cluster than one of them.
+func (t *clusterWaitTarget) inTargetState(current cmv1.ClusterState) bool {
+	for _, state := range t.states {
+		if current == state {
+			return true
+		}
+		for _, later := range laterClusterStates[state] {
+			if current == later {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isHostResolvable checks if the host of the given URL can be resolved.
+func isHostResolvable(ctx context.Context, text string) bool {
+	if text == "" {
+		return false
+	}
+	parsed, err := url.Parse(text)
+	if err != nil || parsed.Hostname() == "" {
+		return false
+	}
+	addresses, err := lookupHost(ctx, parsed.Hostname())
+	return err == nil && len(addresses) > 0
+}
+
+// targetStatesValidator checks that the values of the 'target_states' attribute are states that
+// the waiter supports.
+func targetStatesValidator() tfsdk.AttributeValidator {
+	return &common.AttributeValidator{
+		Desc:   "Validate target states",
+		MDDesc: "Validate target states",
+		Validator: func(ctx context.Context, req tfsdk.ValidateAttributeRequest, resp *tfsdk.ValidateAttributeResponse) {
+			states := types.List{}
+			diag := req.Config.GetAttribute(ctx, req.AttributePath, &states)
+			if diag.HasError() {
+				// No attribute to validate
+				return
+			}
+			if states.Unknown || states.Null {
+				return
+			}
+			for _, element := range states.Elems {
+				value, ok := element.(types.String)
+				if !ok || value.Unknown || value.Null {
+					continue
+				}
+				valid := false
+				for _, state := range waitTargetStates {
+					if value.Value == state {
+						valid = true
+						break
+					}
+				}
+				if !valid {
+					resp.Diagnostics.AddAttributeError(req.AttributePath, "Invalid value",
+						fmt.Sprintf("Expected one of '%s' for 'target_states', got '%s'",
+							strings.Join(waitTargetStates, "', '"), value.Value),
+					)
+				}
+			}
+		},
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+)
+
+var _ = Describe("Cluster wait target", func() {
+	var ctx context.Context
+	var originalLookupHost func(ctx context.Context, host string) ([]string, error)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		originalLookupHost = lookupHost
+		lookupHost = func(ctx context.Context, host string) ([]string, error) {
+			if host == "api.my-cluster.example.com" {
+				return []string{"192.168.0.1"}, nil
+			}
+			return nil, errors.New("no such host")
+		}
+	})
+
+	AfterEach(func() {
+		lookupHost = originalLookupHost
+	})
+
+	build := func(builder *cmv1.ClusterBuilder) *cmv1.Cluster {
+		object, err := builder.Build()
+		Expect(err).ToNot(HaveOccurred())
+		return object
+	}
+
+	targetStates := func(states ...string) types.List {
+		list := types.List{
+			ElemType: types.StringType,
+		}
+		for _, state := range states {
+			list.Elems = append(list.Elems, types.String{Value: state})
+		}
+		return list
+	}
+
+	It("Waits till the cluster is ready by default", func() {
+		target := newClusterWaitTarget(&ClusterWaiterState{
+			TargetStates: types.List{ElemType: types.StringType, Null: true},
+		})
+		reached, pending := target.reached(ctx, build(cmv1.NewCluster().
+			State(cmv1.ClusterStateInstalling)))
+		Expect(reached).To(BeFalse())
+		Expect(pending).To(Equal("cluster state is 'installing'"))
+		reached, _ = target.reached(ctx, build(cmv1.NewCluster().
+			State(cmv1.ClusterStateReady)))
+		Expect(reached).To(BeTrue())
+	})
+
+	It("Accepts any of the target states", func() {
+		target := newClusterWaitTarget(&ClusterWaiterState{
+			TargetStates: targetStates("ready", "hibernating"),
+		})
+		reached, _ := target.reached(ctx, build(cmv1.NewCluster().
+			State(cmv1.ClusterStateHibernating)))
+		Expect(reached).To(BeTrue())
+		reached, _ = target.reached(ctx, build(cmv1.NewCluster().
+			State(cmv1.ClusterStateInstalling)))
+		Expect(reached).To(BeFalse())
+	})
+
+	It("Accepts states that come later than the target states", func() {
+		target := newClusterWaitTarget(&ClusterWaiterState{
+			TargetStates: targetStates("installing"),
+		})
+		reached, pending := target.reached(ctx, build(cmv1.NewCluster().
+			State(cmv1.ClusterStateValidating)))
+		Expect(reached).To(BeFalse())
+		Expect(pending).To(Equal("cluster state is 'validating'"))
+		reached, _ = target.reached(ctx, build(cmv1.NewCluster().
+			State(cmv1.ClusterStateInstalling)))
+		Expect(reached).To(BeTrue())
+		reached, _ = target.reached(ctx, build(cmv1.NewCluster().
+			State(cmv1.ClusterStateReady)))
+		Expect(reached).To(BeTrue())
+	})
+
+	It("Doesn't accept earlier states than the target states", func() {
+		target := newClusterWaitTarget(&ClusterWaiterState{
+			TargetStates: targetStates("ready"),
+		})
+		reached, _ := target.reached(ctx, build(cmv1.NewCluster().
+			State(cmv1.ClusterStateInstalling)))
+		Expect(reached).To(BeFalse())
+	})
+
+	It("Checks the conditions", func() {
+		target := newClusterWaitTarget(&ClusterWaiterState{
+			TargetStates: types.List{ElemType: types.StringType, Elems: []attr.Value{}},
+			Conditions: &ClusterWaiterConditions{
+				APIURLResolvable:         types.Bool{Value: true},
+				ConsoleURLSet:            types.Bool{Value: true},
+				DefaultMachinePoolScaled: types.Bool{Value: true},
+			},
+		})
+		cluster := func() *cmv1.ClusterBuilder {
+			return cmv1.NewCluster().
+				State(cmv1.ClusterStateReady).
+				API(cmv1.NewClusterAPI().URL("https://api.my-cluster.example.com:6443")).
+				Console(cmv1.NewClusterConsole().URL("https://console.example.com")).
+				Nodes(cmv1.NewClusterNodes().Compute(3)).
+				Status(cmv1.NewClusterStatus().CurrentCompute(3))
+		}
+
+		reached, _ := target.reached(ctx, build(cluster()))
+		Expect(reached).To(BeTrue())
+
+		reached, pending := target.reached(ctx, build(cluster().
+			Console(cmv1.NewClusterConsole())))
+		Expect(reached).To(BeFalse())
+		Expect(pending).To(Equal("console URL isn't set"))
+
+		reached, pending = target.reached(ctx, build(cluster().
+			API(cmv1.NewClusterAPI().URL("https://api.other.example.com:6443"))))
+		Expect(reached).To(BeFalse())
+		Expect(pending).To(ContainSubstring("can't be resolved"))
+
+		reached, pending = target.reached(ctx, build(cluster().
+			Status(cmv1.NewClusterStatus().CurrentCompute(2))))
+		Expect(reached).To(BeFalse())
+		Expect(pending).To(Equal("default machine pool has 2 of 3 compute nodes"))
+	})
+})
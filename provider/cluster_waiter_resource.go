@@ -10,6 +10,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift-online/ocm-sdk-go/logging"
+	"github.com/terraform-redhat/terraform-provider-ocm/provider/common"
 )
 
 type Waiter interface {
@@ -47,11 +48,53 @@ func (t *ClusterWaiterResourceType) GetSchema(ctx context.Context) (result tfsdk
 				Type:     types.Int64Type,
 				Optional: true,
 			},
+			"target_states": {
+				Description: "States of the cluster that the waiter waits for, any of " +
+					"'installing', 'ready' and 'hibernating'. The default is to wait till " +
+					"the cluster is 'ready'. Clusters that are already ready or " +
+					"hibernating also satisfy the 'installing' target.",
+				Type: types.ListType{
+					ElemType: types.StringType,
+				},
+				Optional: true,
+				Validators: []tfsdk.AttributeValidator{
+					targetStatesValidator(),
+				},
+			},
+			"conditions": {
+				Description: "Additional conditions that need to be satisfied, once the cluster " +
+					"is in one of the target states, for the waiter to finish.",
+				Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+					"api_url_resolvable": {
+						Description: "Wait till the host of the API URL can be resolved.",
+						Type:        types.BoolType,
+						Optional:    true,
+					},
+					"console_url_set": {
+						Description: "Wait till the console URL is set.",
+						Type:        types.BoolType,
+						Optional:    true,
+					},
+					"default_machine_pool_scaled": {
+						Description: "Wait till the cluster has all the compute nodes of the " +
+							"default machine pool.",
+						Type:     types.BoolType,
+						Optional: true,
+					},
+				}),
+				Optional: true,
+			},
 			"ready": {
-				Description: "Whether the cluster is ready",
-				Type:        types.BoolType,
-				Computed:    true,
+				Description: "Whether the cluster is in one of the target states and satisfies " +
+					"the conditions. It is refreshed from the cluster, and when it is no " +
+					"longer true the next apply waits again.",
+				Type:     types.BoolType,
+				Computed: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					WaitIfNotReadyModifier(),
+				},
 			},
+			"provision_error": provisionErrorAttribute(),
 		},
 	}
 	return
@@ -82,25 +125,105 @@ func (r *ClusterWaiterResource) Create(ctx context.Context,
 	if response.Diagnostics.HasError() {
 		return
 	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+
+	// Wait till the cluster reaches the target:
+	r.wait(ctx, state, &response.Diagnostics)
+
+	// Save the state:
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
 
+func (r *ClusterWaiterResource) Read(ctx context.Context, request tfsdk.ReadResourceRequest,
+	response *tfsdk.ReadResourceResponse) {
+	// Get the current state:
+	state := &ClusterWaiterState{}
+	diags := request.State.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+
+	// Refresh the readiness from the current state of the cluster, without waiting:
+	get, err := r.collection.Cluster(state.Cluster.Value).Get().SendContext(ctx)
+	if err != nil {
+		if common.IsStatusNotFound(err) {
+			r.logger.Warn(ctx, "Cluster wasn't found, removing the waiter from the state")
+			response.State.RemoveResource(ctx)
+			return
+		}
+		response.Diagnostics.AddError(
+			"Can't find cluster",
+			fmt.Sprintf(
+				"Can't find cluster with identifier '%s': %v",
+				state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+	object := get.Body()
+	ready, pending := newClusterWaitTarget(state).reached(ctx, object)
+	if !ready {
+		r.logger.Info(ctx, "Cluster is no longer ready: %s", pending)
+	}
+	state.Ready = types.Bool{
+		Value: ready,
+	}
+	state.ProvisionError = provisionErrorState(object)
+
+	// Save the state:
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *ClusterWaiterResource) Update(ctx context.Context, request tfsdk.UpdateResourceRequest,
+	response *tfsdk.UpdateResourceResponse) {
+	// Get the plan:
+	state := &ClusterWaiterState{}
+	diags := request.Plan.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+
+	// Wait again, as the target may have changed or may no longer be reached:
+	r.wait(ctx, state, &response.Diagnostics)
+
+	// Save the state:
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+// wait waits till the cluster reaches the target of the waiter and updates the state with the
+// result. Errors, including the installation of the cluster failing, are added to the
+// diagnostics, but the state is still updated, so that it can be saved and Terraform marks the
+// resource as tainted.
+func (r *ClusterWaiterResource) wait(ctx context.Context, state *ClusterWaiterState,
+	diags *diag.Diagnostics) {
 	state.Ready = types.Bool{
 		Value: false,
 	}
+	state.ProvisionError = types.String{
+		Null: true,
+	}
 
 	timeout := defaultTimeoutInMinutes
 	if !state.Timeout.Unknown && !state.Timeout.Null {
 		if state.Timeout.Value <= 0 {
-			response.Diagnostics.AddWarning(nonPositiveTimeoutSummary, fmt.Sprintf(nonPositiveTimeoutFormat, state.Cluster.Value))
+			diags.AddWarning(nonPositiveTimeoutSummary, fmt.Sprintf(nonPositiveTimeoutFormat, state.Cluster.Value))
 		} else {
 			timeout = state.Timeout.Value
 		}
 	}
 
-	// Wait till the cluster is ready:
-	object, err := r.retryClusterReadiness(3, 30*time.Second, state.Cluster.Value, ctx, timeout)
+	target := newClusterWaitTarget(state)
+	object, err := retryClusterReadiness(3, 30*time.Second, state.Cluster.Value, ctx, timeout,
+		target, r.collection, r.logger)
 	if err != nil {
-
-		response.Diagnostics.AddError(
+		diags.AddError(
 			"Can't poll cluster state",
 			fmt.Sprintf(
 				"Can't poll state of cluster with identifier '%s': %v",
@@ -109,28 +232,19 @@ func (r *ClusterWaiterResource) Create(ctx context.Context,
 		)
 		return
 	}
-	isClusterReady := false
-	if object.State() == cmv1.ClusterStateReady {
-		isClusterReady = true
-	}
-
+	ready, _ := target.reached(ctx, object)
 	state.Ready = types.Bool{
-		Value: isClusterReady,
+		Value: ready,
 	}
 
-	// Save the state:
-	diags = response.State.Set(ctx, state)
-	response.Diagnostics.Append(diags...)
-}
-
-func (r *ClusterWaiterResource) Read(ctx context.Context, request tfsdk.ReadResourceRequest,
-	response *tfsdk.ReadResourceResponse) {
-	// Do Nothing
-}
-
-func (r *ClusterWaiterResource) Update(ctx context.Context, request tfsdk.UpdateResourceRequest,
-	response *tfsdk.UpdateResourceResponse) {
-	// Do Nothing
+	// Report the details of the failure if the cluster is in error state:
+	if object.State() == cmv1.ClusterStateError {
+		details, status := clusterErrorDetails(ctx, r.collection, object, r.logger)
+		state.ProvisionError = types.String{
+			Value: provisionError(status),
+		}
+		diags.AddError("Cluster installation failed", details)
+	}
 }
 
 func (r *ClusterWaiterResource) Delete(ctx context.Context, request tfsdk.DeleteResourceRequest,
@@ -143,8 +257,11 @@ func (r *ClusterWaiterResource) ImportState(ctx context.Context, request tfsdk.I
 	// Do Nothing
 }
 
-func (r *ClusterWaiterResource) isClusterReady(clusterId string, ctx context.Context, timeout int64) (*cmv1.Cluster, error) {
-	resource := r.collection.Cluster(clusterId)
+// isClusterReady polls the cluster till it reaches the given target or is in error state, and
+// returns the last version of the cluster retrieved from the server.
+func isClusterReady(clusterId string, ctx context.Context, timeout int64, target *clusterWaitTarget,
+	collection *cmv1.ClustersClient, logger logging.Logger) (*cmv1.Cluster, error) {
+	resource := collection.Cluster(clusterId)
 	var object *cmv1.Cluster
 	pollCtx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Minute)
 	defer cancel()
@@ -152,29 +269,31 @@ func (r *ClusterWaiterResource) isClusterReady(clusterId string, ctx context.Con
 		Interval(pollingIntervalInMinutes * time.Minute).
 		Predicate(func(getClusterResponse *cmv1.ClusterGetResponse) bool {
 			object = getClusterResponse.Body()
-			r.logger.Debug(ctx, "cluster state is %s", object.State())
-			switch object.State() {
-			case cmv1.ClusterStateReady,
-				cmv1.ClusterStateError:
+			if object.State() == cmv1.ClusterStateError {
 				return true
 			}
-			return false
+			reached, pending := target.reached(ctx, object)
+			if !reached {
+				logger.Debug(ctx, "waiting for cluster: %s", pending)
+			}
+			return reached
 		}).
 		StartContext(pollCtx)
 	if err != nil {
-		r.logger.Error(ctx, "Can't  poll cluster state")
+		logger.Error(ctx, "Can't  poll cluster state")
 		return nil, err
 	}
 
 	return object, err
 }
 
-func (r *ClusterWaiterResource) retryClusterReadiness(attempts int, sleep time.Duration, clusterId string, ctx context.Context, timeout int64) (*cmv1.Cluster, error) {
-	object, err := r.isClusterReady(clusterId, ctx, timeout)
+func retryClusterReadiness(attempts int, sleep time.Duration, clusterId string, ctx context.Context, timeout int64,
+	target *clusterWaitTarget, collection *cmv1.ClustersClient, logger logging.Logger) (*cmv1.Cluster, error) {
+	object, err := isClusterReady(clusterId, ctx, timeout, target, collection, logger)
 	if err != nil {
 		if attempts--; attempts > 0 {
 			time.Sleep(sleep)
-			return r.retryClusterReadiness(attempts, 2*sleep, clusterId, ctx, timeout)
+			return retryClusterReadiness(attempts, 2*sleep, clusterId, ctx, timeout, target, collection, logger)
 		}
 		return object, err
 	}
@@ -5,7 +5,16 @@ import (
 )
 
 type ClusterWaiterState struct {
-	Cluster types.String `tfsdk:"cluster"`
-	Ready   types.Bool   `tfsdk:"ready"`
-	Timeout types.Int64  `tfsdk:"timeout"`
+	Cluster        types.String             `tfsdk:"cluster"`
+	Ready          types.Bool               `tfsdk:"ready"`
+	Timeout        types.Int64              `tfsdk:"timeout"`
+	ProvisionError types.String             `tfsdk:"provision_error"`
+	TargetStates   types.List               `tfsdk:"target_states"`
+	Conditions     *ClusterWaiterConditions `tfsdk:"conditions"`
+}
+
+type ClusterWaiterConditions struct {
+	APIURLResolvable         types.Bool `tfsdk:"api_url_resolvable"`
+	ConsoleURLSet            types.Bool `tfsdk:"console_url_set"`
+	DefaultMachinePoolScaled types.Bool `tfsdk:"default_machine_pool_scaled"`
 }
@@ -17,10 +17,14 @@ limitations under the License.
 package common
 
 import (
+	"fmt"
+	"net/http"
 	"regexp"
+	"strings"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	ocm_errors "github.com/openshift-online/ocm-sdk-go/errors"
 	"github.com/pkg/errors"
 )
 
@@ -60,6 +64,30 @@ func ShouldPatchString(state, plan types.String) (value string, ok bool) {
 	return
 }
 
+// ShouldPatchMap checks if the change between the given state and plan requires sending a patch
+// request to the server. If it does it returns the value to add to the patch.
+func ShouldPatchMap(state, plan types.Map) (value map[string]string, ok bool) {
+	if plan.Unknown || plan.Null {
+		return
+	}
+	value = map[string]string{}
+	for k, v := range plan.Elems {
+		value[k] = v.(types.String).Value
+	}
+	if state.Unknown || state.Null || len(state.Elems) != len(plan.Elems) {
+		ok = true
+		return
+	}
+	for k, v := range state.Elems {
+		planned, found := value[k]
+		if !found || planned != v.(types.String).Value {
+			ok = true
+			return
+		}
+	}
+	return
+}
+
 // TF types converter functions
 func StringArrayToList(arr []string) types.List {
 	list := types.List{
@@ -90,3 +118,26 @@ func IsValidDomain(candidate string) bool {
 	var domainRegexp = regexp.MustCompile(`^(?i)[a-z0-9-]+(\.[a-z0-9-]+)+\.?$`)
 	return domainRegexp.MatchString(candidate)
 }
+
+// SplitImportID splits an import identifier made of comma separated fields, for example
+// '<cluster_id>,<object_id>'. It returns an error if the identifier doesn't contain the same
+// number of non empty fields as the given format.
+func SplitImportID(id string, format string) ([]string, error) {
+	fields := strings.Split(id, ",")
+	if len(fields) != strings.Count(format, ",")+1 {
+		return nil, fmt.Errorf("Expected an import identifier with format '%s', got '%s'", format, id)
+	}
+	for _, field := range fields {
+		if field == "" {
+			return nil, fmt.Errorf("Expected an import identifier with format '%s', got '%s'", format, id)
+		}
+	}
+	return fields, nil
+}
+
+// IsStatusNotFound checks if the given error was returned by the OCM API because the requested
+// object doesn't exist.
+func IsStatusNotFound(err error) bool {
+	sdkErr, ok := err.(*ocm_errors.Error)
+	return ok && sdkErr.Status() == http.StatusNotFound
+}
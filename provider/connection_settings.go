@@ -0,0 +1,152 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// connectionSettings contains the settings of the provider that are resolved from the
+// configuration, the environment variables and the configuration file of the ocm CLI.
+type connectionSettings struct {
+	url           string
+	tokenURL      string
+	user          string
+	password      string
+	token         string
+	clientID      string
+	clientSecret  string
+	trustedCAs    string
+	insecure      bool
+	clusterDryRun bool
+	logHTTPBodies bool
+}
+
+// newConnectionSettings copies the settings from the given provider configuration, using the
+// environment variables returned by the given function for the ones that aren't explicitly
+// specified.
+func newConnectionSettings(config *Config, getenv func(string) string) (
+	settings *connectionSettings, diags diag.Diagnostics) {
+	settings = &connectionSettings{
+		url:          stringSetting(config.URL, "OCM_URL", getenv),
+		tokenURL:     stringSetting(config.TokenURL, "OCM_TOKEN_URL", getenv),
+		user:         stringSetting(config.User, "OCM_USER", getenv),
+		password:     stringSetting(config.Password, "OCM_PASSWORD", getenv),
+		token:        stringSetting(config.Token, "OCM_TOKEN", getenv),
+		clientID:     stringSetting(config.ClientID, "OCM_CLIENT_ID", getenv),
+		clientSecret: stringSetting(config.ClientSecret, "OCM_CLIENT_SECRET", getenv),
+		trustedCAs:   stringSetting(config.TrustedCAs, "OCM_TRUSTED_CAS", getenv),
+	}
+	var ok bool
+	settings.insecure, ok = boolSetting(config.Insecure, "OCM_INSECURE", false, getenv)
+	if !ok {
+		addInvalidBoolEnvError(&diags, "OCM_INSECURE", getenv)
+	}
+	settings.clusterDryRun, ok = boolSetting(config.ClusterDryRun, "OCM_CLUSTER_DRY_RUN", true,
+		getenv)
+	if !ok {
+		addInvalidBoolEnvError(&diags, "OCM_CLUSTER_DRY_RUN", getenv)
+	}
+	settings.logHTTPBodies, ok = boolSetting(config.LogHTTPBodies, "OCM_LOG_HTTP_BODIES", false,
+		getenv)
+	if !ok {
+		addInvalidBoolEnvError(&diags, "OCM_LOG_HTTP_BODIES", getenv)
+	}
+	return
+}
+
+// hasCredentials checks if the settings contain a token, a user and password, or a client
+// identifier and secret.
+func (s *connectionSettings) hasCredentials() bool {
+	return s.token != "" ||
+		(s.user != "" && s.password != "") ||
+		(s.clientID != "" && s.clientSecret != "")
+}
+
+// mergeOCMConfig takes from the given configuration file of the ocm CLI the settings that aren't
+// already set.
+func (s *connectionSettings) mergeOCMConfig(ocmConfig *OCMConfig) {
+	if s.url == "" {
+		s.url = ocmConfig.URL
+	}
+	if s.tokenURL == "" {
+		s.tokenURL = ocmConfig.TokenURL
+	}
+	if s.token == "" {
+		s.token = ocmConfig.RefreshToken
+	}
+	if s.clientID == "" {
+		s.clientID = ocmConfig.ClientID
+		s.clientSecret = ocmConfig.ClientSecret
+	}
+}
+
+// resolveURLAlias replaces the URL alias with the URL of the environment, and uses the token URL
+// of that environment unless another one was explicitly specified.
+func (s *connectionSettings) resolveURLAlias() {
+	alias, ok := urlAliases[strings.ToLower(s.url)]
+	if !ok {
+		return
+	}
+	s.url = alias.url
+	if s.tokenURL == "" {
+		s.tokenURL = alias.tokenURL
+	}
+}
+
+// stringSetting returns the value of a setting of the provider, or the value of the given
+// environment variable if the setting isn't explicitly specified.
+func stringSetting(value types.String, env string, getenv func(string) string) string {
+	if !value.Null && !value.Unknown {
+		return value.Value
+	}
+	return getenv(env)
+}
+
+// boolSetting returns the value of a boolean provider setting, taking it from the given
+// environment variable when it isn't explicitly configured, or using the given default when the
+// environment variable isn't set either. The returned flag is false if the value of the
+// environment variable isn't a valid boolean.
+func boolSetting(value types.Bool, env string, defaultValue bool,
+	getenv func(string) string) (result bool, ok bool) {
+	if !value.Null && !value.Unknown {
+		return value.Value, true
+	}
+	text := getenv(env)
+	if text == "" {
+		return defaultValue, true
+	}
+	result, err := strconv.ParseBool(text)
+	if err != nil {
+		return false, false
+	}
+	return result, true
+}
+
+// addInvalidBoolEnvError adds the error reported when the given environment variable doesn't
+// contain a valid boolean.
+func addInvalidBoolEnvError(diags *diag.Diagnostics, env string, getenv func(string) string) {
+	diags.AddError(
+		fmt.Sprintf("Invalid value of environment variable '%s'", env),
+		fmt.Sprintf("Expected a boolean value, got '%s'", getenv(env)),
+	)
+}
@@ -0,0 +1,145 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+)
+
+var _ = Describe("Connection settings", func() {
+	// nullConfig returns a provider configuration where none of the settings are specified:
+	nullConfig := func() *Config {
+		return &Config{
+			URL:           types.String{Null: true},
+			TokenURL:      types.String{Null: true},
+			User:          types.String{Null: true},
+			Password:      types.String{Null: true},
+			Token:         types.String{Null: true},
+			ClientID:      types.String{Null: true},
+			ClientSecret:  types.String{Null: true},
+			TrustedCAs:    types.String{Null: true},
+			Insecure:      types.Bool{Null: true},
+			ConfigFile:    types.String{Null: true},
+			ClusterDryRun: types.Bool{Null: true},
+			LogHTTPBodies: types.Bool{Null: true},
+		}
+	}
+
+	// env returns a function that returns the values of the given environment variables:
+	env := func(values map[string]string) func(string) string {
+		return func(name string) string {
+			return values[name]
+		}
+	}
+
+	It("Uses the defaults when nothing is specified", func() {
+		settings, diags := newConnectionSettings(nullConfig(), env(nil))
+		Expect(diags.HasError()).To(BeFalse())
+		Expect(settings.url).To(BeEmpty())
+		Expect(settings.insecure).To(BeFalse())
+		Expect(settings.clusterDryRun).To(BeTrue())
+		Expect(settings.logHTTPBodies).To(BeFalse())
+		Expect(settings.hasCredentials()).To(BeFalse())
+	})
+
+	It("Takes the settings from the environment", func() {
+		settings, diags := newConnectionSettings(nullConfig(), env(map[string]string{
+			"OCM_URL":           "https://api.example.com",
+			"OCM_TOKEN_URL":     "https://sso.example.com/token",
+			"OCM_CLIENT_ID":     "my-client",
+			"OCM_CLIENT_SECRET": "my-secret",
+			"OCM_TRUSTED_CAS":   "my-cas",
+			"OCM_INSECURE":      "true",
+		}))
+		Expect(diags.HasError()).To(BeFalse())
+		Expect(settings.url).To(Equal("https://api.example.com"))
+		Expect(settings.tokenURL).To(Equal("https://sso.example.com/token"))
+		Expect(settings.clientID).To(Equal("my-client"))
+		Expect(settings.clientSecret).To(Equal("my-secret"))
+		Expect(settings.trustedCAs).To(Equal("my-cas"))
+		Expect(settings.insecure).To(BeTrue())
+		Expect(settings.hasCredentials()).To(BeTrue())
+	})
+
+	It("Gives precedence to the configuration over the environment", func() {
+		config := nullConfig()
+		config.URL = types.String{Value: "https://api.config.example.com"}
+		config.Insecure = types.Bool{Value: false}
+		settings, diags := newConnectionSettings(config, env(map[string]string{
+			"OCM_URL":      "https://api.env.example.com",
+			"OCM_INSECURE": "true",
+		}))
+		Expect(diags.HasError()).To(BeFalse())
+		Expect(settings.url).To(Equal("https://api.config.example.com"))
+		Expect(settings.insecure).To(BeFalse())
+	})
+
+	It("Fails if a boolean environment variable isn't valid", func() {
+		_, diags := newConnectionSettings(nullConfig(), env(map[string]string{
+			"OCM_INSECURE": "maybe",
+		}))
+		Expect(diags.HasError()).To(BeTrue())
+		Expect(diags[0].Summary()).To(ContainSubstring("OCM_INSECURE"))
+		Expect(diags[0].Detail()).To(ContainSubstring("maybe"))
+	})
+
+	It("Resolves the URL aliases", func() {
+		settings, _ := newConnectionSettings(nullConfig(), env(map[string]string{
+			"OCM_URL": "Staging",
+		}))
+		settings.resolveURLAlias()
+		Expect(settings.url).To(Equal("https://api.stage.openshift.com"))
+		Expect(settings.tokenURL).To(Equal(stagingTokenURL))
+	})
+
+	It("Keeps the explicit token URL when resolving an alias", func() {
+		settings, _ := newConnectionSettings(nullConfig(), env(map[string]string{
+			"OCM_URL":       "production",
+			"OCM_TOKEN_URL": "https://sso.example.com/token",
+		}))
+		settings.resolveURLAlias()
+		Expect(settings.url).To(Equal("https://api.openshift.com"))
+		Expect(settings.tokenURL).To(Equal("https://sso.example.com/token"))
+	})
+
+	It("Doesn't change URLs that aren't aliases", func() {
+		settings, _ := newConnectionSettings(nullConfig(), env(map[string]string{
+			"OCM_URL": "https://api.example.com",
+		}))
+		settings.resolveURLAlias()
+		Expect(settings.url).To(Equal("https://api.example.com"))
+		Expect(settings.tokenURL).To(BeEmpty())
+	})
+
+	It("Takes missing settings from the ocm CLI configuration", func() {
+		settings, _ := newConnectionSettings(nullConfig(), env(map[string]string{
+			"OCM_URL": "https://api.example.com",
+		}))
+		settings.mergeOCMConfig(&OCMConfig{
+			URL:          "https://api.other.example.com",
+			TokenURL:     "https://sso.example.com/token",
+			ClientID:     "cloud-services",
+			RefreshToken: "my-refresh-token",
+		})
+		Expect(settings.url).To(Equal("https://api.example.com"))
+		Expect(settings.tokenURL).To(Equal("https://sso.example.com/token"))
+		Expect(settings.clientID).To(Equal("cloud-services"))
+		Expect(settings.token).To(Equal("my-refresh-token"))
+	})
+})
@@ -24,9 +24,13 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift-online/ocm-sdk-go/logging"
+	"github.com/terraform-redhat/terraform-provider-ocm/provider/common"
+)
+
+const (
+	groupMembershipImportIDFormat = "<cluster_id>,<group_id>,<user_id>"
 )
 
 type GroupMembershipResourceType struct {
@@ -93,6 +97,7 @@ func (r *GroupMembershipResource) Create(ctx context.Context,
 	if response.Diagnostics.HasError() {
 		return
 	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
 
 	// Wait till the cluster is ready:
 	resource := r.collection.Cluster(state.Cluster.Value)
@@ -158,6 +163,8 @@ func (r *GroupMembershipResource) Read(ctx context.Context, request tfsdk.ReadRe
 	if response.Diagnostics.HasError() {
 		return
 	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+	ctx = withObjectLogField(ctx, state.ID.Value)
 
 	// Find the group membership:
 	resource := r.collection.Cluster(state.Cluster.Value).Groups().Group(state.Group.Value).
@@ -165,6 +172,11 @@ func (r *GroupMembershipResource) Read(ctx context.Context, request tfsdk.ReadRe
 		User(state.ID.Value)
 	get, err := resource.Get().SendContext(ctx)
 	if err != nil {
+		if common.IsStatusNotFound(err) {
+			r.logger.Warn(ctx, "Group membership wasn't found, removing it from the state")
+			response.State.RemoveResource(ctx)
+			return
+		}
 		response.Diagnostics.AddError(
 			"Can't find group membership",
 			fmt.Sprintf(
@@ -196,6 +208,8 @@ func (r *GroupMembershipResource) Delete(ctx context.Context, request tfsdk.Dele
 	if response.Diagnostics.HasError() {
 		return
 	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+	ctx = withObjectLogField(ctx, state.ID.Value)
 
 	// Send the request to delete group membership:
 	resource := r.collection.Cluster(state.Cluster.Value).Groups().Group(state.Group.Value).
@@ -220,12 +234,46 @@ func (r *GroupMembershipResource) Delete(ctx context.Context, request tfsdk.Dele
 
 func (r *GroupMembershipResource) ImportState(ctx context.Context, request tfsdk.ImportResourceStateRequest,
 	response *tfsdk.ImportResourceStateResponse) {
-	tfsdk.ResourceImportStatePassthroughID(
-		ctx,
-		tftypes.NewAttributePath().WithAttributeName("id"),
-		request,
-		response,
-	)
+	fields, err := common.SplitImportID(request.ID, groupMembershipImportIDFormat)
+	if err != nil {
+		response.Diagnostics.AddError("Invalid import identifier", err.Error())
+		return
+	}
+	clusterID := fields[0]
+	groupID := fields[1]
+	userID := fields[2]
+
+	// Try to retrieve the object:
+	get, err := r.collection.Cluster(clusterID).Groups().Group(groupID).
+		Users().
+		User(userID).
+		Get().
+		SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't find group membership",
+			fmt.Sprintf(
+				"Can't find user group membership identifier '%s' for "+
+					"cluster '%s' and group '%s': %v",
+				userID, clusterID, groupID, err,
+			),
+		)
+		return
+	}
+	object := get.Body()
+
+	// Save the state:
+	state := &GroupMembershipState{
+		Cluster: types.String{
+			Value: clusterID,
+		},
+		Group: types.String{
+			Value: groupID,
+		},
+	}
+	r.populateState(object, state)
+	diags := response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
 }
 
 // populateState copies the data from the API object to the Terraform state.
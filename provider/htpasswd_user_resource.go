@@ -0,0 +1,423 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift-online/ocm-sdk-go/logging"
+	"github.com/terraform-redhat/terraform-provider-ocm/provider/common"
+)
+
+const (
+	htpasswdUserImportIDFormat = "<cluster_id>,<identity_provider_id>,<user_id>"
+	generatedPasswordLength    = 20
+)
+
+// generatedPasswordCharacterSets are the sets of characters used to generate passwords. Generated
+// passwords contain at least one character of each set, as required by the password policy of
+// htpasswd identity providers.
+var generatedPasswordCharacterSets = []string{
+	"abcdefghijklmnopqrstuvwxyz",
+	"ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+	"0123456789",
+	"!#$%&*+-.:=?@^_~",
+}
+
+type HTPasswdUserResourceType struct {
+}
+
+type HTPasswdUserResource struct {
+	logger     logging.Logger
+	collection *cmv1.ClustersClient
+}
+
+func (t *HTPasswdUserResourceType) GetSchema(ctx context.Context) (result tfsdk.Schema,
+	diags diag.Diagnostics) {
+	result = tfsdk.Schema{
+		Description: "User of an htpasswd identity provider.",
+		Attributes: map[string]tfsdk.Attribute{
+			"cluster": {
+				Description: "Identifier of the cluster.",
+				Type:        types.StringType,
+				Required:    true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					tfsdk.RequiresReplace(),
+				},
+			},
+			"identity_provider": {
+				Description: "Identifier of the htpasswd identity provider.",
+				Type:        types.StringType,
+				Required:    true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					tfsdk.RequiresReplace(),
+				},
+			},
+			"id": {
+				Description: "Unique identifier of the user.",
+				Type:        types.StringType,
+				Computed:    true,
+			},
+			"username": {
+				Description: "User name.",
+				Type:        types.StringType,
+				Required:    true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					tfsdk.RequiresReplace(),
+				},
+			},
+			"password": {
+				Description: "User password. If it isn't set a random password is generated.",
+				Type:        types.StringType,
+				Optional:    true,
+				Computed:    true,
+				Sensitive:   true,
+			},
+		},
+	}
+	return
+}
+
+func (t *HTPasswdUserResourceType) NewResource(ctx context.Context,
+	p tfsdk.Provider) (result tfsdk.Resource, diags diag.Diagnostics) {
+	// Cast the provider interface to the specific implementation: use it directly when needed.
+	parent := p.(*Provider)
+
+	// Get the collection of clusters:
+	collection := parent.connection.ClustersMgmt().V1().Clusters()
+
+	// Create the resource:
+	result = &HTPasswdUserResource{
+		logger:     parent.logger,
+		collection: collection,
+	}
+
+	return
+}
+
+func (r *HTPasswdUserResource) Create(ctx context.Context,
+	request tfsdk.CreateResourceRequest, response *tfsdk.CreateResourceResponse) {
+	// Get the plan:
+	state := &HTPasswdUserState{}
+	diags := request.Plan.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+
+	// Generate the password if it wasn't provided:
+	if state.Password.Unknown || state.Password.Null {
+		password, err := generatePassword()
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't generate password",
+				fmt.Sprintf(
+					"Can't generate password for user '%s': %v",
+					state.Username.Value, err,
+				),
+			)
+			return
+		}
+		state.Password = types.String{
+			Value: password,
+		}
+	}
+
+	// Create the user:
+	object, err := cmv1.NewHTPasswdUser().
+		Username(state.Username.Value).
+		Password(state.Password.Value).
+		Build()
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't build htpasswd user",
+			fmt.Sprintf(
+				"Can't build htpasswd user '%s': %v",
+				state.Username.Value, err,
+			),
+		)
+		return
+	}
+	add, err := r.collection.Cluster(state.Cluster.Value).
+		IdentityProviders().
+		IdentityProvider(state.IdentityProvider.Value).
+		HtpasswdUsers().
+		Add().
+		Body(object).
+		SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't create htpasswd user",
+			fmt.Sprintf(
+				"Can't create htpasswd user '%s' for identity provider '%s' of "+
+					"cluster '%s': %v",
+				state.Username.Value, state.IdentityProvider.Value, state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+	object = add.Body()
+
+	// Save the state:
+	r.populateState(object, state)
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *HTPasswdUserResource) Read(ctx context.Context, request tfsdk.ReadResourceRequest,
+	response *tfsdk.ReadResourceResponse) {
+	// Get the current state:
+	state := &HTPasswdUserState{}
+	diags := request.State.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+	ctx = withObjectLogField(ctx, state.ID.Value)
+
+	// Find the user:
+	get, err := r.collection.Cluster(state.Cluster.Value).
+		IdentityProviders().
+		IdentityProvider(state.IdentityProvider.Value).
+		HtpasswdUsers().
+		HtpasswdUser(state.ID.Value).
+		Get().
+		SendContext(ctx)
+	if err != nil {
+		if common.IsStatusNotFound(err) {
+			r.logger.Warn(ctx, "Htpasswd user wasn't found, removing it from the state")
+			response.State.RemoveResource(ctx)
+			return
+		}
+		response.Diagnostics.AddError(
+			"Can't find htpasswd user",
+			fmt.Sprintf(
+				"Can't find htpasswd user with identifier '%s' for identity provider '%s' of "+
+					"cluster '%s': %v",
+				state.ID.Value, state.IdentityProvider.Value, state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+	object := get.Body()
+
+	// Save the state:
+	r.populateState(object, state)
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *HTPasswdUserResource) Update(ctx context.Context, request tfsdk.UpdateResourceRequest,
+	response *tfsdk.UpdateResourceResponse) {
+	var diags diag.Diagnostics
+
+	// Get the state:
+	state := &HTPasswdUserState{}
+	diags = request.State.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+	ctx = withObjectLogField(ctx, state.ID.Value)
+
+	// Get the plan:
+	plan := &HTPasswdUserState{}
+	diags = request.Plan.Get(ctx, plan)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// The password is the only attribute that can be changed without replacing the user:
+	password, ok := common.ShouldPatchString(state.Password, plan.Password)
+	if ok {
+		object, err := cmv1.NewHTPasswdUser().Password(password).Build()
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't build htpasswd user",
+				fmt.Sprintf(
+					"Can't build htpasswd user '%s': %v",
+					state.Username.Value, err,
+				),
+			)
+			return
+		}
+		_, err = r.collection.Cluster(state.Cluster.Value).
+			IdentityProviders().
+			IdentityProvider(state.IdentityProvider.Value).
+			HtpasswdUsers().
+			HtpasswdUser(state.ID.Value).
+			Update().
+			Body(object).
+			SendContext(ctx)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't update htpasswd user",
+				fmt.Sprintf(
+					"Can't update password of htpasswd user with identifier '%s' for "+
+						"identity provider '%s' of cluster '%s': %v",
+					state.ID.Value, state.IdentityProvider.Value, state.Cluster.Value, err,
+				),
+			)
+			return
+		}
+		state.Password = types.String{
+			Value: password,
+		}
+	}
+
+	// Save the state:
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *HTPasswdUserResource) Delete(ctx context.Context, request tfsdk.DeleteResourceRequest,
+	response *tfsdk.DeleteResourceResponse) {
+	// Get the state:
+	state := &HTPasswdUserState{}
+	diags := request.State.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+	ctx = withObjectLogField(ctx, state.ID.Value)
+
+	// Send the request to delete the user:
+	_, err := r.collection.Cluster(state.Cluster.Value).
+		IdentityProviders().
+		IdentityProvider(state.IdentityProvider.Value).
+		HtpasswdUsers().
+		HtpasswdUser(state.ID.Value).
+		Delete().
+		SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't delete htpasswd user",
+			fmt.Sprintf(
+				"Can't delete htpasswd user with identifier '%s' for identity provider '%s' of "+
+					"cluster '%s': %v",
+				state.ID.Value, state.IdentityProvider.Value, state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+
+	// Remove the state:
+	response.State.RemoveResource(ctx)
+}
+
+func (r *HTPasswdUserResource) ImportState(ctx context.Context, request tfsdk.ImportResourceStateRequest,
+	response *tfsdk.ImportResourceStateResponse) {
+	fields, err := common.SplitImportID(request.ID, htpasswdUserImportIDFormat)
+	if err != nil {
+		response.Diagnostics.AddError("Invalid import identifier", err.Error())
+		return
+	}
+	clusterID := fields[0]
+	identityProviderID := fields[1]
+	userID := fields[2]
+
+	// Try to retrieve the object:
+	get, err := r.collection.Cluster(clusterID).
+		IdentityProviders().
+		IdentityProvider(identityProviderID).
+		HtpasswdUsers().
+		HtpasswdUser(userID).
+		Get().
+		SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't find htpasswd user",
+			fmt.Sprintf(
+				"Can't find htpasswd user with identifier '%s' for identity provider '%s' of "+
+					"cluster '%s': %v",
+				userID, identityProviderID, clusterID, err,
+			),
+		)
+		return
+	}
+	object := get.Body()
+
+	// Save the state, the password can't be retrieved:
+	state := &HTPasswdUserState{
+		Cluster: types.String{
+			Value: clusterID,
+		},
+		IdentityProvider: types.String{
+			Value: identityProviderID,
+		},
+		Password: types.String{
+			Null: true,
+		},
+	}
+	r.populateState(object, state)
+	diags := response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+// populateState copies the data from the API object to the Terraform state. The API doesn't
+// return passwords, so the password of the state isn't changed.
+func (r *HTPasswdUserResource) populateState(object *cmv1.HTPasswdUser, state *HTPasswdUserState) {
+	state.ID = types.String{
+		Value: object.ID(),
+	}
+	state.Username = types.String{
+		Value: object.Username(),
+	}
+}
+
+// generatePassword generates a random password that contains at least one character of each
+// of the generated password character sets.
+func generatePassword() (string, error) {
+	allCharacters := ""
+	for _, characterSet := range generatedPasswordCharacterSets {
+		allCharacters += characterSet
+	}
+	password := make([]byte, generatedPasswordLength)
+	for i := range password {
+		characters := allCharacters
+		if i < len(generatedPasswordCharacterSets) {
+			characters = generatedPasswordCharacterSets[i]
+		}
+		index, err := rand.Int(rand.Reader, big.NewInt(int64(len(characters))))
+		if err != nil {
+			return "", err
+		}
+		password[i] = characters[index.Int64()]
+	}
+
+	// Shuffle the password so that the required characters aren't always at the beginning:
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(i+1)))
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j.Int64()] = password[j.Int64()], password[i]
+	}
+	return string(password), nil
+}
@@ -19,8 +19,10 @@ package provider
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -31,6 +33,10 @@ import (
 	"github.com/terraform-redhat/terraform-provider-ocm/provider/idps"
 )
 
+const (
+	identityProviderImportIDFormat = "<cluster_id>,<identity_provider_id>"
+)
+
 type IdentityProviderResourceType struct {
 }
 
@@ -48,6 +54,9 @@ func (t *IdentityProviderResourceType) GetSchema(ctx context.Context) (result tf
 				Description: "Identifier of the cluster.",
 				Type:        types.StringType,
 				Required:    true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					tfsdk.RequiresReplace(),
+				},
 			},
 			"id": {
 				Description: "Unique identifier of the identity provider.",
@@ -58,6 +67,9 @@ func (t *IdentityProviderResourceType) GetSchema(ctx context.Context) (result tf
 				Description: "Name of the identity provider.",
 				Type:        types.StringType,
 				Required:    true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					tfsdk.RequiresReplace(),
+				},
 			},
 			"mapping_method": {
 				Description: "Specifies how new identities are mapped to users when they log in. Options are [add claim generate lookup] (default 'claim')",
@@ -70,40 +82,83 @@ func (t *IdentityProviderResourceType) GetSchema(ctx context.Context) (result tf
 				Description: "Details of the 'htpasswd' identity provider.",
 				Attributes:  idps.HtpasswdSchema(),
 				Optional:    true,
+				Validators:  idps.HtpasswdValidators(),
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					identityProviderTypeChangeRequiresReplace(),
+				},
 			},
 			"gitlab": {
 				Description: "Details of the Gitlab identity provider.",
 				Attributes:  idps.GitlabSchema(),
 				Optional:    true,
 				Validators:  idps.GitlabValidators(),
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					identityProviderTypeChangeRequiresReplace(),
+				},
 			},
 			"github": {
 				Description: "Details of the Github identity provider.",
 				Attributes:  idps.GithubSchema(),
 				Optional:    true,
 				Validators:  idps.GithubValidators(),
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					identityProviderTypeChangeRequiresReplace(),
+				},
 			},
 			"google": {
 				Description: "Details of the Google identity provider.",
 				Attributes:  idps.GoogleSchema(),
 				Optional:    true,
 				Validators:  idps.GoogleValidators(),
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					identityProviderTypeChangeRequiresReplace(),
+				},
 			},
 			"ldap": {
 				Description: "Details of the LDAP identity provider.",
 				Attributes:  idps.LdapSchema(),
 				Optional:    true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					identityProviderTypeChangeRequiresReplace(),
+				},
 			},
 			"openid": {
 				Description: "Details of the OpenID identity provider.",
 				Attributes:  idps.OpenidSchema(),
 				Optional:    true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					identityProviderTypeChangeRequiresReplace(),
+				},
 			},
 		},
 	}
 	return
 }
 
+// identityProviderTypeChangeRequiresReplace returns a plan modifier that requires replacing the
+// identity provider when the details of its type are added or removed, as the type of an
+// existing identity provider can't be changed.
+func identityProviderTypeChangeRequiresReplace() tfsdk.AttributePlanModifier {
+	return tfsdk.RequiresReplaceIf(
+		func(ctx context.Context, state, config attr.Value, path *tftypes.AttributePath) (bool, diag.Diagnostics) {
+			var diags diag.Diagnostics
+			stateValue, err := state.ToTerraformValue(ctx)
+			if err != nil {
+				diags.AddAttributeError(path, "Can't get state value", err.Error())
+				return false, diags
+			}
+			configValue, err := config.ToTerraformValue(ctx)
+			if err != nil {
+				diags.AddAttributeError(path, "Can't get config value", err.Error())
+				return false, diags
+			}
+			return (stateValue == nil) != (configValue == nil), diags
+		},
+		"The type of the identity provider can't be changed, it will be replaced.",
+		"The type of the identity provider can't be changed, it will be replaced.",
+	)
+}
+
 func (t *IdentityProviderResourceType) NewResource(ctx context.Context,
 	p tfsdk.Provider) (result tfsdk.Resource, diags diag.Diagnostics) {
 	// Cast the provider interface to the specific implementation:
@@ -131,6 +186,7 @@ func (r *IdentityProviderResource) Create(ctx context.Context,
 	if response.Diagnostics.HasError() {
 		return
 	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
 
 	// Wait till the cluster is ready:
 	resource := r.collection.Cluster(state.Cluster.Value)
@@ -153,9 +209,69 @@ func (r *IdentityProviderResource) Create(ctx context.Context,
 		return
 	}
 
+	// Generate the passwords of the users that don't have one:
+	if state.HTPasswd != nil && state.HTPasswd.Users != nil {
+		err = generateHTPasswdPasswords(nil, state.HTPasswd.Users)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't generate password",
+				fmt.Sprintf(
+					"Can't generate password for htpasswd user of identity provider "+
+						"with name '%s': %v",
+					state.Name.Value, err,
+				),
+			)
+			return
+		}
+	}
+
 	// Create the identity provider:
-	builder := cmv1.NewIdentityProvider()
+	builder, err := createIdentityProviderBuilder(ctx, state)
+	if err != nil {
+		response.Diagnostics.AddError(err.Error(), err.Error())
+		return
+	}
 	builder.Name(state.Name.Value)
+	object, err := builder.Build()
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't build identity provider",
+			fmt.Sprintf(
+				"Can't build identity provider with name '%s': %v",
+				state.Name.Value, err,
+			),
+		)
+		return
+	}
+	collection := resource.IdentityProviders()
+	add, err := collection.Add().Body(object).SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't create identity provider",
+			fmt.Sprintf(
+				"Can't create identity provider with name '%s' for "+
+					"cluster '%s': %v",
+				state.Name.Value, state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+	object = add.Body()
+
+	// Set the computed attributes:
+	r.populateComputedAttributes(object, state)
+
+	// Save the state:
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+// createIdentityProviderBuilder returns a builder for the identity provider with the type and the
+// details of the given state. It is used both to create and to update identity providers, so it
+// doesn't set the name, which can't be changed.
+func createIdentityProviderBuilder(ctx context.Context, state *IdentityProviderState) (
+	*cmv1.IdentityProviderBuilder, error) {
+	builder := cmv1.NewIdentityProvider()
 	// handle mapping_method
 	mappingMethod := idps.DefaultMappingMethod
 	if !state.MappingMethod.Unknown && !state.MappingMethod.Null {
@@ -171,70 +287,45 @@ func (r *IdentityProviderResource) Create(ctx context.Context,
 		builder.Type(cmv1.IdentityProviderTypeGitlab)
 		gitlabBuilder, err := idps.CreateGitlabIDPBuilder(ctx, state.Gitlab)
 		if err != nil {
-			response.Diagnostics.AddError(err.Error(), err.Error())
-			return
+			return nil, err
 		}
 		builder.Gitlab(gitlabBuilder)
 	case state.Github != nil:
 		builder.Type(cmv1.IdentityProviderTypeGithub)
 		githubBuilder, err := idps.CreateGithubIDPBuilder(ctx, state.Github)
 		if err != nil {
-			response.Diagnostics.AddError(err.Error(), err.Error())
-			return
+			return nil, err
 		}
 		builder.Github(githubBuilder)
 	case state.Google != nil:
 		builder.Type(cmv1.IdentityProviderTypeGoogle)
 		googleBuilder, err := idps.CreateGoogleIDPBuilder(ctx, mappingMethod, state.Google)
 		if err != nil {
-			response.Diagnostics.AddError(err.Error(), err.Error())
-			return
+			return nil, err
 		}
 		builder.Google(googleBuilder)
 	case state.LDAP != nil:
 		builder.Type(cmv1.IdentityProviderTypeLDAP)
 		ldapBuilder, err := idps.CreateLdapIDPBuilder(ctx, state.LDAP)
 		if err != nil {
-			response.Diagnostics.AddError(err.Error(), err.Error())
-			return
+			return nil, err
 		}
 		builder.LDAP(ldapBuilder)
 	case state.OpenID != nil:
 		builder.Type(cmv1.IdentityProviderTypeOpenID)
 		openidBuilder, err := idps.CreateOpenIDIDPBuilder(ctx, state.OpenID)
 		if err != nil {
-			response.Diagnostics.AddError(err.Error(), err.Error())
-			return
+			return nil, err
 		}
 		builder.OpenID(openidBuilder)
 	}
-	object, err := builder.Build()
-	if err != nil {
-		response.Diagnostics.AddError(
-			"Can't build identity provider",
-			fmt.Sprintf(
-				"Can't build identity provider with name '%s': %v",
-				state.Name.Value, err,
-			),
-		)
-		return
-	}
-	collection := resource.IdentityProviders()
-	add, err := collection.Add().Body(object).SendContext(ctx)
-	if err != nil {
-		response.Diagnostics.AddError(
-			"Can't create identity provider",
-			fmt.Sprintf(
-				"Can't create identity provider with name '%s' for "+
-					"cluster '%s': %v",
-				state.Name.Value, state.Cluster.Value, err,
-			),
-		)
-		return
-	}
-	object = add.Body()
+	return builder, nil
+}
 
-	// Set the computed attributes:
+// populateComputedAttributes copies the computed attributes from the API object to the Terraform
+// state, after the identity provider has been created or updated.
+func (r *IdentityProviderResource) populateComputedAttributes(object *cmv1.IdentityProvider,
+	state *IdentityProviderState) {
 	state.ID = types.String{
 		Value: object.ID(),
 	}
@@ -262,10 +353,6 @@ func (r *IdentityProviderResource) Create(ctx context.Context,
 		}
 	case openidObject != nil:
 	}
-
-	// Save the state:
-	diags = response.State.Set(ctx, state)
-	response.Diagnostics.Append(diags...)
 }
 
 func (r *IdentityProviderResource) Read(ctx context.Context, request tfsdk.ReadResourceRequest,
@@ -277,6 +364,8 @@ func (r *IdentityProviderResource) Read(ctx context.Context, request tfsdk.ReadR
 	if response.Diagnostics.HasError() {
 		return
 	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+	ctx = withObjectLogField(ctx, state.ID.Value)
 
 	// Find the identity provider:
 	resource := r.collection.Cluster(state.Cluster.Value).
@@ -284,6 +373,11 @@ func (r *IdentityProviderResource) Read(ctx context.Context, request tfsdk.ReadR
 		IdentityProvider(state.ID.Value)
 	get, err := resource.Get().SendContext(ctx)
 	if err != nil {
+		if common.IsStatusNotFound(err) {
+			r.logger.Warn(ctx, "Identity provider wasn't found, removing it from the state")
+			response.State.RemoveResource(ctx)
+			return
+		}
 		response.Diagnostics.AddError(
 			"Can't find identity provider",
 			fmt.Sprintf(
@@ -296,7 +390,212 @@ func (r *IdentityProviderResource) Read(ctx context.Context, request tfsdk.ReadR
 	}
 	object := get.Body()
 
-	// Copy the identity provider data into the state:
+	// Save the state:
+	r.populateState(object, state)
+	if state.HTPasswd != nil && state.HTPasswd.Users != nil {
+		users, err := listHTPasswdUsers(ctx, resource)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't list htpasswd users",
+				fmt.Sprintf(
+					"Can't list users of identity provider with identifier '%s' for "+
+						"cluster '%s': %v",
+					state.ID.Value, state.Cluster.Value, err,
+				),
+			)
+			return
+		}
+		state.HTPasswd.Users = refreshHTPasswdUsers(state.HTPasswd.Users, users)
+	}
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *IdentityProviderResource) Update(ctx context.Context, request tfsdk.UpdateResourceRequest,
+	response *tfsdk.UpdateResourceResponse) {
+	var diags diag.Diagnostics
+
+	// Get the state:
+	state := &IdentityProviderState{}
+	diags = request.State.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+	ctx = withObjectLogField(ctx, state.ID.Value)
+
+	// Get the plan:
+	plan := &IdentityProviderState{}
+	diags = request.Plan.Get(ctx, plan)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	resource := r.collection.Cluster(state.Cluster.Value).
+		IdentityProviders().
+		IdentityProvider(state.ID.Value)
+
+	// The users of htpasswd identity providers are managed with their own collection, so they
+	// aren't sent with the rest of the details:
+	patch := plan
+	if plan.HTPasswd != nil && plan.HTPasswd.Users != nil {
+		var stateUsers []idps.HTPasswdUser
+		if state.HTPasswd != nil {
+			stateUsers = state.HTPasswd.Users
+		}
+		err := generateHTPasswdPasswords(stateUsers, plan.HTPasswd.Users)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't generate password",
+				fmt.Sprintf(
+					"Can't generate password for htpasswd user of identity provider "+
+						"with identifier '%s': %v",
+					state.ID.Value, err,
+				),
+			)
+			return
+		}
+		err = reconcileHTPasswdUsers(ctx, resource, stateUsers, plan.HTPasswd.Users)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't update htpasswd users",
+				fmt.Sprintf(
+					"Can't update users of identity provider with identifier '%s' for "+
+						"cluster '%s': %v",
+					state.ID.Value, state.Cluster.Value, err,
+				),
+			)
+			return
+		}
+		patch = &IdentityProviderState{}
+		*patch = *plan
+		patch.HTPasswd = &idps.HTPasswdIdentityProvider{}
+	}
+
+	// The type and the name of the identity provider can't change, as that requires
+	// replacing it, so send the rest of the details:
+	builder, err := createIdentityProviderBuilder(ctx, patch)
+	if err != nil {
+		response.Diagnostics.AddError(err.Error(), err.Error())
+		return
+	}
+	object, err := builder.Build()
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't build identity provider",
+			fmt.Sprintf(
+				"Can't build identity provider with name '%s': %v",
+				state.Name.Value, err,
+			),
+		)
+		return
+	}
+	update, err := resource.Update().Body(object).SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't update identity provider",
+			fmt.Sprintf(
+				"Can't update identity provider with identifier '%s' for "+
+					"cluster '%s': %v",
+				state.ID.Value, state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+	object = update.Body()
+
+	// Set the computed attributes:
+	r.populateComputedAttributes(object, plan)
+	plan.ID = state.ID
+
+	// Save the state:
+	diags = response.State.Set(ctx, plan)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *IdentityProviderResource) Delete(ctx context.Context, request tfsdk.DeleteResourceRequest,
+	response *tfsdk.DeleteResourceResponse) {
+	// Get the state:
+	state := &IdentityProviderState{}
+	diags := request.State.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+	ctx = withObjectLogField(ctx, state.ID.Value)
+
+	// Send the request to delete the identity provider:
+	resource := r.collection.Cluster(state.Cluster.Value).
+		IdentityProviders().
+		IdentityProvider(state.ID.Value)
+	_, err := resource.Delete().SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't delete identity provider",
+			fmt.Sprintf(
+				"Can't delete identity provider with identifier '%s' for "+
+					"cluster '%s': %v",
+				state.ID.Value, state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+
+	// Remove the state:
+	response.State.RemoveResource(ctx)
+}
+
+func (r *IdentityProviderResource) ImportState(ctx context.Context, request tfsdk.ImportResourceStateRequest,
+	response *tfsdk.ImportResourceStateResponse) {
+	fields, err := common.SplitImportID(request.ID, identityProviderImportIDFormat)
+	if err != nil {
+		response.Diagnostics.AddError("Invalid import identifier", err.Error())
+		return
+	}
+	clusterID := fields[0]
+	identityProviderID := fields[1]
+
+	// Try to retrieve the object:
+	get, err := r.collection.Cluster(clusterID).
+		IdentityProviders().
+		IdentityProvider(identityProviderID).
+		Get().
+		SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't find identity provider",
+			fmt.Sprintf(
+				"Can't find identity provider with identifier '%s' for "+
+					"cluster '%s': %v",
+				identityProviderID, clusterID, err,
+			),
+		)
+		return
+	}
+	object := get.Body()
+
+	// Save the state:
+	state := &IdentityProviderState{
+		Cluster: types.String{
+			Value: clusterID,
+		},
+		ID: types.String{
+			Value: object.ID(),
+		},
+		MappingMethod: types.String{
+			Value: string(object.MappingMethod()),
+		},
+	}
+	r.populateState(object, state)
+	diags := response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+// populateState copies the data from the API object to the Terraform state.
+func (r *IdentityProviderResource) populateState(object *cmv1.IdentityProvider, state *IdentityProviderState) {
 	state.Name = types.String{
 		Value: object.Name(),
 	}
@@ -514,57 +813,152 @@ func (r *IdentityProviderResource) Read(ctx context.Context, request tfsdk.ReadR
 			}
 		}
 	}
-	response.Diagnostics.Append(diags...)
-	if response.Diagnostics.HasError() {
-		return
-	}
+}
 
-	// Save the state:
-	diags = response.State.Set(ctx, state)
-	response.Diagnostics.Append(diags...)
+// listHTPasswdUsers returns the users of an htpasswd identity provider, indexed by user name.
+func listHTPasswdUsers(ctx context.Context, resource *cmv1.IdentityProviderClient) (
+	map[string]*cmv1.HTPasswdUser, error) {
+	users := map[string]*cmv1.HTPasswdUser{}
+	listSize := 100
+	listPage := 1
+	listRequest := resource.HtpasswdUsers().List().Size(listSize)
+	for {
+		listResponse, err := listRequest.SendContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		listResponse.Items().Each(func(user *cmv1.HTPasswdUser) bool {
+			users[user.Username()] = user
+			return true
+		})
+		if listResponse.Size() < listSize {
+			break
+		}
+		listPage++
+		listRequest.Page(listPage)
+	}
+	return users, nil
 }
 
-func (r *IdentityProviderResource) Update(ctx context.Context, request tfsdk.UpdateResourceRequest,
-	response *tfsdk.UpdateResourceResponse) {
+// refreshHTPasswdUsers returns the users of the state that still exist, followed by the users that
+// were added outside of Terraform. The API doesn't return passwords, so the passwords of the
+// state are kept, and the added users don't have one.
+func refreshHTPasswdUsers(stateUsers []idps.HTPasswdUser,
+	users map[string]*cmv1.HTPasswdUser) []idps.HTPasswdUser {
+	result := []idps.HTPasswdUser{}
+	known := map[string]bool{}
+	for _, user := range stateUsers {
+		known[user.Username.Value] = true
+		if _, ok := users[user.Username.Value]; ok {
+			result = append(result, user)
+		}
+	}
+	usernames := make([]string, 0, len(users))
+	for username := range users {
+		if !known[username] {
+			usernames = append(usernames, username)
+		}
+	}
+	sort.Strings(usernames)
+	for _, username := range usernames {
+		result = append(result, idps.HTPasswdUser{
+			Username: types.String{
+				Value: username,
+			},
+			Password: types.String{
+				Null: true,
+			},
+		})
+	}
+	return result
 }
 
-func (r *IdentityProviderResource) Delete(ctx context.Context, request tfsdk.DeleteResourceRequest,
-	response *tfsdk.DeleteResourceResponse) {
-	// Get the state:
-	state := &IdentityProviderState{}
-	diags := request.State.Get(ctx, state)
-	response.Diagnostics.Append(diags...)
-	if response.Diagnostics.HasError() {
-		return
+// generateHTPasswdPasswords sets the passwords of the planned users that don't have one. Users
+// that are already in the state keep the password that they have there, and new users get a
+// random password, generated like the 'ocm_htpasswd_user' resource does.
+func generateHTPasswdPasswords(stateUsers, planUsers []idps.HTPasswdUser) error {
+	passwords := map[string]types.String{}
+	for _, user := range stateUsers {
+		passwords[user.Username.Value] = user.Password
+	}
+	for i, user := range planUsers {
+		if !user.Password.Unknown && !user.Password.Null {
+			continue
+		}
+		if password, ok := passwords[user.Username.Value]; ok {
+			planUsers[i].Password = password
+			continue
+		}
+		password, err := generatePassword()
+		if err != nil {
+			return fmt.Errorf("can't generate password for user '%s': %v",
+				user.Username.Value, err)
+		}
+		planUsers[i].Password = types.String{
+			Value: password,
+		}
 	}
+	return nil
+}
 
-	// Send the request to delete the identity provider:
-	resource := r.collection.Cluster(state.Cluster.Value).
-		IdentityProviders().
-		IdentityProvider(state.ID.Value)
-	_, err := resource.Delete().SendContext(ctx)
+// reconcileHTPasswdUsers adds, updates and removes the users of an htpasswd identity provider
+// so that they match the plan.
+func reconcileHTPasswdUsers(ctx context.Context, resource *cmv1.IdentityProviderClient,
+	stateUsers, planUsers []idps.HTPasswdUser) error {
+	users, err := listHTPasswdUsers(ctx, resource)
 	if err != nil {
-		response.Diagnostics.AddError(
-			"Can't delete identity provider",
-			fmt.Sprintf(
-				"Can't delete identity provider with identifier '%s' for "+
-					"cluster '%s': %v",
-				state.ID.Value, state.Cluster.Value, err,
-			),
-		)
-		return
+		return err
+	}
+	passwords := map[string]types.String{}
+	for _, user := range stateUsers {
+		passwords[user.Username.Value] = user.Password
 	}
 
-	// Remove the state:
-	response.State.RemoveResource(ctx)
-}
-
-func (r *IdentityProviderResource) ImportState(ctx context.Context, request tfsdk.ImportResourceStateRequest,
-	response *tfsdk.ImportResourceStateResponse) {
-	tfsdk.ResourceImportStatePassthroughID(
-		ctx,
-		tftypes.NewAttributePath().WithAttributeName("id"),
-		request,
-		response,
-	)
+	collection := resource.HtpasswdUsers()
+	desired := map[string]bool{}
+	for _, user := range planUsers {
+		desired[user.Username.Value] = true
+		existing, ok := users[user.Username.Value]
+		if !ok {
+			object, err := cmv1.NewHTPasswdUser().
+				Username(user.Username.Value).
+				Password(user.Password.Value).
+				Build()
+			if err != nil {
+				return err
+			}
+			_, err = collection.Add().Body(object).SendContext(ctx)
+			if err != nil {
+				return fmt.Errorf("can't add user '%s': %v", user.Username.Value, err)
+			}
+			continue
+		}
+		if passwords[user.Username.Value].Equal(user.Password) {
+			continue
+		}
+		object, err := cmv1.NewHTPasswdUser().
+			Password(user.Password.Value).
+			Build()
+		if err != nil {
+			return err
+		}
+		_, err = collection.HtpasswdUser(existing.ID()).Update().Body(object).SendContext(ctx)
+		if err != nil {
+			return fmt.Errorf("can't update password of user '%s': %v", user.Username.Value, err)
+		}
+	}
+	removed := []string{}
+	for username := range users {
+		if !desired[username] {
+			removed = append(removed, username)
+		}
+	}
+	sort.Strings(removed)
+	for _, username := range removed {
+		_, err = collection.HtpasswdUser(users[username].ID()).Delete().SendContext(ctx)
+		if err != nil {
+			return fmt.Errorf("can't remove user '%s': %v", username, err)
+		}
+	}
+	return nil
 }
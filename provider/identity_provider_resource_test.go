@@ -0,0 +1,72 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+
+	"github.com/terraform-redhat/terraform-provider-ocm/provider/idps"
+)
+
+var _ = Describe("Htpasswd user passwords", func() {
+	It("Keeps the passwords that are set", func() {
+		users := []idps.HTPasswdUser{{
+			Username: types.String{Value: "my-user"},
+			Password: types.String{Value: "my-password"},
+		}}
+		err := generateHTPasswdPasswords(nil, users)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(users[0].Password.Value).To(Equal("my-password"))
+	})
+
+	It("Keeps the passwords of the users that are already in the state", func() {
+		state := []idps.HTPasswdUser{{
+			Username: types.String{Value: "my-user"},
+			Password: types.String{Value: "my-password"},
+		}}
+		users := []idps.HTPasswdUser{{
+			Username: types.String{Value: "my-user"},
+			Password: types.String{Unknown: true},
+		}}
+		err := generateHTPasswdPasswords(state, users)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(users[0].Password.Value).To(Equal("my-password"))
+	})
+
+	It("Generates the passwords of new users", func() {
+		users := []idps.HTPasswdUser{
+			{
+				Username: types.String{Value: "my-user"},
+				Password: types.String{Unknown: true},
+			},
+			{
+				Username: types.String{Value: "my-other-user"},
+				Password: types.String{Null: true},
+			},
+		}
+		err := generateHTPasswdPasswords(nil, users)
+		Expect(err).ToNot(HaveOccurred())
+		for _, user := range users {
+			Expect(user.Password.Unknown).To(BeFalse())
+			Expect(user.Password.Null).To(BeFalse())
+			Expect(user.Password.Value).To(HaveLen(generatedPasswordLength))
+		}
+		Expect(users[0].Password.Value).ToNot(Equal(users[1].Password.Value))
+	})
+})
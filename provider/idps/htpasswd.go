@@ -6,9 +6,17 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+
+	"github.com/terraform-redhat/terraform-provider-ocm/provider/common"
 )
 
 type HTPasswdIdentityProvider struct {
+	Username types.String   `tfsdk:"username"`
+	Password types.String   `tfsdk:"password"`
+	Users    []HTPasswdUser `tfsdk:"users"`
+}
+
+type HTPasswdUser struct {
 	Username types.String `tfsdk:"username"`
 	Password types.String `tfsdk:"password"`
 }
@@ -16,19 +24,83 @@ type HTPasswdIdentityProvider struct {
 func HtpasswdSchema() tfsdk.NestedAttributes {
 	return tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
 		"username": {
-			Description: "User name.",
+			Description: "User name. Use 'users' instead to manage more than one user.",
 			Type:        types.StringType,
-			Required:    true,
+			Optional:    true,
 		},
 		"password": {
-			Description: "User password.",
+			Description: "User password. Use 'users' instead to manage more than one user.",
 			Type:        types.StringType,
-			Required:    true,
+			Optional:    true,
 			Sensitive:   true,
 		},
+		"users": {
+			Description: "Users of the identity provider. This list is authoritative: users that are " +
+				"added outside of it are removed, so it shouldn't be combined with the " +
+				"'ocm_htpasswd_user' resource.",
+			Attributes: tfsdk.ListNestedAttributes(map[string]tfsdk.Attribute{
+				"username": {
+					Description: "User name.",
+					Type:        types.StringType,
+					Required:    true,
+				},
+				"password": {
+					Description: "User password. If it isn't set a random password is " +
+						"generated, like the 'ocm_htpasswd_user' resource does.",
+					Type:      types.StringType,
+					Optional:  true,
+					Computed:  true,
+					Sensitive: true,
+				},
+			}, tfsdk.ListNestedAttributesOptions{}),
+			Optional: true,
+		},
 	})
 }
 
+func HtpasswdValidators() []tfsdk.AttributeValidator {
+	errSumm := "Invalid htpasswd IDP resource configuration"
+	return []tfsdk.AttributeValidator{
+		&common.AttributeValidator{
+			Desc: "Validate htpasswd users",
+			Validator: func(ctx context.Context, req tfsdk.ValidateAttributeRequest, resp *tfsdk.ValidateAttributeResponse) {
+				state := &HTPasswdIdentityProvider{}
+				diag := req.Config.GetAttribute(ctx, req.AttributePath, state)
+				if diag.HasError() {
+					// No attribute to validate
+					return
+				}
+				isUserDefined := !state.Username.Null || !state.Password.Null
+				areUsersDefined := state.Users != nil
+				if isUserDefined && areUsersDefined {
+					resp.Diagnostics.AddError(errSumm, "Expected either 'username' and 'password' or 'users', not both.")
+					return
+				}
+				if areUsersDefined {
+					if len(state.Users) == 0 {
+						resp.Diagnostics.AddError(errSumm, "Expected at least one user in 'users'.")
+					}
+					usernames := map[string]bool{}
+					for _, user := range state.Users {
+						if user.Username.Unknown {
+							continue
+						}
+						if usernames[user.Username.Value] {
+							resp.Diagnostics.AddError(errSumm,
+								"Expected unique user names in 'users', got '"+user.Username.Value+"' more than once.")
+						}
+						usernames[user.Username.Value] = true
+					}
+					return
+				}
+				if state.Username.Null || state.Password.Null {
+					resp.Diagnostics.AddError(errSumm, "Expected both 'username' and 'password', or 'users'.")
+				}
+			},
+		},
+	}
+}
+
 func CreateHTPasswdIDPBuilder(ctx context.Context, state *HTPasswdIdentityProvider) *cmv1.HTPasswdIdentityProviderBuilder {
 	builder := cmv1.NewHTPasswdIdentityProvider()
 	if !state.Username.Null {
@@ -37,5 +109,14 @@ func CreateHTPasswdIDPBuilder(ctx context.Context, state *HTPasswdIdentityProvid
 	if !state.Password.Null {
 		builder.Password(state.Password.Value)
 	}
+	if len(state.Users) > 0 {
+		users := []*cmv1.HTPasswdUserBuilder{}
+		for _, user := range state.Users {
+			users = append(users, cmv1.NewHTPasswdUser().
+				Username(user.Username.Value).
+				Password(user.Password.Value))
+		}
+		builder.Users(cmv1.NewHTPasswdUserList().Items(users...))
+	}
 	return builder
 }
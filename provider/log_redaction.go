@@ -0,0 +1,76 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"regexp"
+)
+
+// redactedText replaces the secrets in the log messages.
+const redactedText = "REDACTED"
+
+// redaction is a regular expression that matches a secret, and the replacement for it.
+type redaction struct {
+	re          *regexp.Regexp
+	replacement string
+}
+
+// redactions are the secrets removed from the log messages: tokens, client secrets, passwords,
+// like the ones of the htpasswd users, and private keys, like the ones of the OIDC
+// configurations.
+var redactions = []redaction{
+	{
+		// PEM encoded private keys, including the ones escaped inside JSON strings:
+		re: regexp.MustCompile(
+			`-----BEGIN ([A-Z ]*)PRIVATE KEY-----[\s\S]*?-----END ([A-Z ]*)PRIVATE KEY-----`,
+		),
+		replacement: "-----BEGIN ${1}PRIVATE KEY-----" + redactedText + "-----END ${2}PRIVATE KEY-----",
+	},
+	{
+		// Fields of JSON documents:
+		re: regexp.MustCompile(
+			`("(?:access_token|refresh_token|id_token|token|client_secret|password|private_key)"\s*:\s*)"(?:[^"\\]|\\.)*"`,
+		),
+		replacement: `$1"` + redactedText + `"`,
+	},
+	{
+		// Parameters of URL encoded forms and queries, like the ones sent to the token
+		// endpoint:
+		re: regexp.MustCompile(
+			`\b((?:access_token|refresh_token|id_token|client_secret|password)=)[^&\s"]+`,
+		),
+		replacement: "${1}" + redactedText,
+	},
+	{
+		// Authorization headers:
+		re:          regexp.MustCompile(`\b(Bearer|Basic) [A-Za-z0-9._~+/=-]+`),
+		replacement: "$1 " + redactedText,
+	},
+	{
+		// JSON web tokens that appear anywhere else:
+		re:          regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+		replacement: redactedText,
+	},
+}
+
+// redact returns a copy of the given text where the secrets have been replaced.
+func redact(text string) string {
+	for _, redaction := range redactions {
+		text = redaction.re.ReplaceAllString(text, redaction.replacement)
+	}
+	return text
+}
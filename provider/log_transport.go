@@ -0,0 +1,111 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/openshift-online/ocm-sdk-go/logging"
+)
+
+// operationIDHeader is the header that the API uses to return the identifier of the operation,
+// which is what support needs to find the request in the logs of the server.
+const operationIDHeader = "X-Operation-Id"
+
+// LogTransportWrapper is a transport wrapper that logs the requests sent to the API and their
+// responses, including the operation identifier returned by the server. Bodies are only logged
+// when explicitly enabled, and secrets are redacted by the logger.
+type LogTransportWrapper struct {
+	logger logging.Logger
+	bodies bool
+}
+
+// NewLogTransportWrapper creates a transport wrapper that logs requests with the given logger.
+func NewLogTransportWrapper(logger logging.Logger, bodies bool) *LogTransportWrapper {
+	return &LogTransportWrapper{
+		logger: logger,
+		bodies: bodies,
+	}
+}
+
+// Wrap creates a round tripper that logs the requests sent with the given one.
+func (w *LogTransportWrapper) Wrap(transport http.RoundTripper) http.RoundTripper {
+	return &logTransport{
+		owner:     w,
+		transport: transport,
+	}
+}
+
+type logTransport struct {
+	owner     *LogTransportWrapper
+	transport http.RoundTripper
+}
+
+func (t *logTransport) RoundTrip(request *http.Request) (response *http.Response, err error) {
+	ctx := request.Context()
+	logger := t.owner.logger
+
+	// Read the request body so that it can be logged, and send a clone of the request with a
+	// copy of it, as the request of the caller can't be modified:
+	if t.owner.bodies && request.Body != nil && request.Body != http.NoBody {
+		var body []byte
+		body, err = io.ReadAll(request.Body)
+		request.Body.Close()
+		if err != nil {
+			return
+		}
+		request = request.Clone(ctx)
+		request.Body = io.NopCloser(bytes.NewReader(body))
+		if len(body) > 0 {
+			logger.Debug(ctx, "Request body of %s '%s': %s", request.Method, request.URL, body)
+		}
+	}
+
+	start := time.Now()
+	response, err = t.transport.RoundTrip(request)
+	elapsed := time.Since(start)
+	if err != nil {
+		logger.Debug(ctx, "Request %s '%s' failed after %s: %v", request.Method, request.URL,
+			elapsed, err)
+		return
+	}
+	operationID := response.Header.Get(operationIDHeader)
+	if operationID != "" {
+		recordOperationID(ctx, operationID)
+		ctx = withLogField(ctx, logFieldOperationID, operationID)
+	}
+	logger.Debug(ctx, "Request %s '%s' returned status code %d after %s", request.Method,
+		request.URL, response.StatusCode, elapsed)
+
+	// Read the response body so that it can be logged, and replace it with a copy:
+	if t.owner.bodies && response.Body != nil {
+		var body []byte
+		body, err = io.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			return
+		}
+		response.Body = io.NopCloser(bytes.NewReader(body))
+		if len(body) > 0 {
+			logger.Debug(ctx, "Response body of %s '%s': %s", request.Method, request.URL, body)
+		}
+	}
+	return
+}
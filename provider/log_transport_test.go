@@ -0,0 +1,126 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+	"github.com/openshift-online/ocm-sdk-go/logging"
+)
+
+var _ = Describe("Log transport wrapper", func() {
+	var server *httptest.Server
+	var buffer *bytes.Buffer
+	var logger logging.Logger
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(r.Body)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(string(body)).To(Equal(`{"name":"my-cluster"}`))
+			w.Header().Set(operationIDHeader, "my-operation")
+			w.WriteHeader(http.StatusCreated)
+			_, err = w.Write([]byte(`{"id":"123"}`))
+			Expect(err).ToNot(HaveOccurred())
+		}))
+		buffer = &bytes.Buffer{}
+		var err error
+		logger, err = logging.NewStdLoggerBuilder().
+			Streams(buffer, buffer).
+			Debug(true).
+			Build()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	send := func(bodies bool) string {
+		client := &http.Client{
+			Transport: NewLogTransportWrapper(logger, bodies).Wrap(http.DefaultTransport),
+		}
+		response, err := client.Post(
+			server.URL+"/api/clusters_mgmt/v1/clusters",
+			"application/json",
+			strings.NewReader(`{"name":"my-cluster"}`),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		defer response.Body.Close()
+		body, err := io.ReadAll(response.Body)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(body)).To(Equal(`{"id":"123"}`))
+		return buffer.String()
+	}
+
+	It("Logs the requests without the bodies by default", func() {
+		output := send(false)
+		Expect(output).To(ContainSubstring("/api/clusters_mgmt/v1/clusters"))
+		Expect(output).To(ContainSubstring("returned status code 201"))
+		Expect(output).ToNot(ContainSubstring("my-cluster"))
+		Expect(output).ToNot(ContainSubstring(`"123"`))
+	})
+
+	It("Logs the bodies when enabled", func() {
+		output := send(true)
+		Expect(output).To(ContainSubstring(`Request body of POST`))
+		Expect(output).To(ContainSubstring(`{"name":"my-cluster"}`))
+		Expect(output).To(ContainSubstring(`{"id":"123"}`))
+	})
+
+	It("Doesn't modify the request of the caller", func() {
+		client := &http.Client{
+			Transport: NewLogTransportWrapper(logger, true).Wrap(http.DefaultTransport),
+		}
+		request, err := http.NewRequest(
+			http.MethodPost,
+			server.URL+"/api/clusters_mgmt/v1/clusters",
+			strings.NewReader(`{"name":"my-cluster"}`),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		body := request.Body
+		response, err := client.Do(request)
+		Expect(err).ToNot(HaveOccurred())
+		response.Body.Close()
+		Expect(request.Body).To(BeIdenticalTo(body))
+	})
+
+	It("Records the operation identifier for the resources", func() {
+		client := &http.Client{
+			Transport: NewLogTransportWrapper(logger, false).Wrap(http.DefaultTransport),
+		}
+		ctx := withResourceLogField(context.Background(), "ocm_cluster")
+		request, err := http.NewRequestWithContext(
+			ctx,
+			http.MethodPost,
+			server.URL+"/api/clusters_mgmt/v1/clusters",
+			strings.NewReader(`{"name":"my-cluster"}`),
+		)
+		Expect(err).ToNot(HaveOccurred())
+		response, err := client.Do(request)
+		Expect(err).ToNot(HaveOccurred())
+		response.Body.Close()
+		Expect(lastOperationID(ctx)).To(Equal("my-operation"))
+	})
+})
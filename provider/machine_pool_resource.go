@@ -26,13 +26,16 @@ import (
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
-	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/terraform-redhat/terraform-provider-ocm/provider/common"
 
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
+const (
+	machinePoolImportIDFormat = "<cluster_id>,<machine_pool_id>"
+)
+
 type MachinePoolResourceType struct {
 	logger logging.Logger
 }
@@ -175,6 +178,7 @@ func (r *MachinePoolResource) Create(ctx context.Context,
 	if response.Diagnostics.HasError() {
 		return
 	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
 
 	machinepoolName := state.Name.Value
 	if !machinepoolNameRE.MatchString(machinepoolName) {
@@ -308,6 +312,8 @@ func (r *MachinePoolResource) Read(ctx context.Context, request tfsdk.ReadResour
 	if response.Diagnostics.HasError() {
 		return
 	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+	ctx = withObjectLogField(ctx, state.ID.Value)
 
 	// Find the machine pool:
 	resource := r.collection.Cluster(state.Cluster.Value).
@@ -315,6 +321,11 @@ func (r *MachinePoolResource) Read(ctx context.Context, request tfsdk.ReadResour
 		MachinePool(state.ID.Value)
 	get, err := resource.Get().SendContext(ctx)
 	if err != nil {
+		if common.IsStatusNotFound(err) {
+			r.logger.Warn(ctx, "Machine pool wasn't found, removing it from the state")
+			response.State.RemoveResource(ctx)
+			return
+		}
 		response.Diagnostics.AddError(
 			"Can't find machine pool",
 			fmt.Sprintf(
@@ -344,6 +355,8 @@ func (r *MachinePoolResource) Update(ctx context.Context, request tfsdk.UpdateRe
 	if response.Diagnostics.HasError() {
 		return
 	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+	ctx = withObjectLogField(ctx, state.ID.Value)
 
 	// Get the plan:
 	plan := &MachinePoolState{}
@@ -515,6 +528,8 @@ func (r *MachinePoolResource) Delete(ctx context.Context, request tfsdk.DeleteRe
 	if response.Diagnostics.HasError() {
 		return
 	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+	ctx = withObjectLogField(ctx, state.ID.Value)
 
 	// Send the request to delete the machine pool:
 	resource := r.collection.Cluster(state.Cluster.Value).
@@ -539,12 +554,52 @@ func (r *MachinePoolResource) Delete(ctx context.Context, request tfsdk.DeleteRe
 
 func (r *MachinePoolResource) ImportState(ctx context.Context, request tfsdk.ImportResourceStateRequest,
 	response *tfsdk.ImportResourceStateResponse) {
-	tfsdk.ResourceImportStatePassthroughID(
-		ctx,
-		tftypes.NewAttributePath().WithAttributeName("id"),
-		request,
-		response,
-	)
+	fields, err := common.SplitImportID(request.ID, machinePoolImportIDFormat)
+	if err != nil {
+		response.Diagnostics.AddError("Invalid import identifier", err.Error())
+		return
+	}
+	clusterID := fields[0]
+	machinePoolID := fields[1]
+
+	// Try to retrieve the object:
+	get, err := r.collection.Cluster(clusterID).
+		MachinePools().
+		MachinePool(machinePoolID).
+		Get().
+		SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't find machine pool",
+			fmt.Sprintf(
+				"Can't find machine pool with identifier '%s' for "+
+					"cluster '%s': %v",
+				machinePoolID, clusterID, err,
+			),
+		)
+		return
+	}
+	object := get.Body()
+
+	// Save the state:
+	state := &MachinePoolState{
+		Cluster: types.String{
+			Value: clusterID,
+		},
+		Replicas: types.Int64{
+			Null: true,
+		},
+		AutoScalingEnabled: types.Bool{
+			Null: true,
+		},
+		Labels: types.Map{
+			ElemType: types.StringType,
+			Null:     true,
+		},
+	}
+	r.populateState(object, state)
+	diags := response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
 }
 
 // populateState copies the data from the API object to the Terraform state.
@@ -0,0 +1,124 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type mergeDefaultsModifier struct {
+	defaults         map[string]string
+	preserveExisting bool
+}
+
+// MergeDefaultsModifier returns a plan modifier for string map attributes that adds to the plan
+// the given default entries, typically configured in the provider. Entries explicitly given in
+// the configuration of the resource take precedence over the defaults. The attribute needs to be
+// computed, as the planned value can differ from the configured one.
+//
+// When the attribute isn't configured the defaults are only added when the resource is created,
+// for existing resources the value saved in the state is preserved.
+func MergeDefaultsModifier(defaults map[string]string) tfsdk.AttributePlanModifier {
+	return mergeDefaultsModifier{
+		defaults: defaults,
+	}
+}
+
+// MergeCreateDefaultsModifier is like MergeDefaultsModifier, but for attributes that can't be
+// changed once the resource is created. For existing resources the value saved in the state is
+// preserved as long as it contains the configured entries, so that changing the defaults of the
+// provider doesn't change the plan, and doesn't trip the check that the value isn't changed.
+func MergeCreateDefaultsModifier(defaults map[string]string) tfsdk.AttributePlanModifier {
+	return mergeDefaultsModifier{
+		defaults:         defaults,
+		preserveExisting: true,
+	}
+}
+
+func (m mergeDefaultsModifier) Description(ctx context.Context) string {
+	return "Entries not explicitly configured are taken from the defaults of the provider."
+}
+
+func (m mergeDefaultsModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m mergeDefaultsModifier) Modify(ctx context.Context, req tfsdk.ModifyAttributePlanRequest,
+	resp *tfsdk.ModifyAttributePlanResponse) {
+	if req.AttributeConfig == nil || req.AttributeState == nil || req.AttributePlan == nil {
+		// shouldn't happen, but let's not panic if it does
+		return
+	}
+	if req.Plan.Raw.IsNull() {
+		// The resource is being deleted:
+		return
+	}
+	config, ok := req.AttributeConfig.(types.Map)
+	if !ok || config.Unknown {
+		return
+	}
+	if config.Null && !req.State.Raw.IsNull() {
+		resp.AttributePlan = req.AttributeState
+		return
+	}
+	if m.preserveExisting && !req.State.Raw.IsNull() {
+		state, ok := req.AttributeState.(types.Map)
+		if ok && !state.Unknown && !state.Null && containsEntries(state, config) {
+			resp.AttributePlan = state
+			return
+		}
+	}
+
+	// Without configuration and without defaults this results in an empty map instead of an
+	// unknown value, which is also what the resources save when the server returns no entries:
+	resp.AttributePlan = mergeDefaults(m.defaults, config)
+}
+
+// containsEntries checks if the given map contains all the entries of the given subset.
+func containsEntries(value, subset types.Map) bool {
+	for k, v := range subset.Elems {
+		current, ok := value.Elems[k]
+		if !ok || !current.Equal(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeDefaults returns a string map containing the given default entries and the entries of the
+// given value, which take precedence.
+func mergeDefaults(defaults map[string]string, value types.Map) types.Map {
+	result := types.Map{
+		ElemType: types.StringType,
+		Elems:    map[string]attr.Value{},
+	}
+	for k, v := range defaults {
+		result.Elems[k] = types.String{
+			Value: v,
+		}
+	}
+	if !value.Unknown && !value.Null {
+		for k, v := range value.Elems {
+			result.Elems[k] = v
+		}
+	}
+	return result
+}
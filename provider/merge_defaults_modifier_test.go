@@ -0,0 +1,176 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+)
+
+var _ = Describe("Merge defaults modifier", func() {
+	ctx := context.Background()
+	objectType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{}}
+	nullObject := tftypes.NewValue(objectType, nil)
+	object := tftypes.NewValue(objectType, map[string]tftypes.Value{})
+	defaults := map[string]string{
+		"cost-center": "123",
+		"owner":       "team-a",
+	}
+
+	stringMapValue := func(values map[string]string) types.Map {
+		result := types.Map{
+			ElemType: types.StringType,
+			Elems:    map[string]attr.Value{},
+		}
+		for k, v := range values {
+			result.Elems[k] = types.String{Value: v}
+		}
+		return result
+	}
+
+	modifyWith := func(modifier tfsdk.AttributePlanModifier, config, state types.Map,
+		creating bool) attr.Value {
+		request := tfsdk.ModifyAttributePlanRequest{
+			AttributeConfig: config,
+			AttributeState:  state,
+			AttributePlan:   config,
+			State:           tfsdk.State{Raw: object},
+			Plan:            tfsdk.Plan{Raw: object},
+		}
+		if creating {
+			request.State.Raw = nullObject
+		}
+		response := &tfsdk.ModifyAttributePlanResponse{
+			AttributePlan: request.AttributePlan,
+		}
+		modifier.Modify(ctx, request, response)
+		Expect(response.Diagnostics.HasError()).To(BeFalse())
+		return response.AttributePlan
+	}
+
+	modify := func(config, state types.Map, creating bool) attr.Value {
+		return modifyWith(MergeDefaultsModifier(defaults), config, state, creating)
+	}
+
+	It("Adds the defaults to the configured values", func() {
+		config := stringMapValue(map[string]string{
+			"owner": "team-b",
+			"env":   "prod",
+		})
+		plan := modify(config, types.Map{ElemType: types.StringType, Null: true}, true)
+		Expect(plan).To(Equal(stringMapValue(map[string]string{
+			"cost-center": "123",
+			"owner":       "team-b",
+			"env":         "prod",
+		})))
+	})
+
+	It("Uses the defaults when creating without configured values", func() {
+		config := types.Map{ElemType: types.StringType, Null: true}
+		plan := modify(config, config, true)
+		Expect(plan).To(Equal(stringMapValue(defaults)))
+	})
+
+	It("Uses an empty map when creating without configured values and without defaults", func() {
+		config := types.Map{ElemType: types.StringType, Null: true}
+		plan := modifyWith(MergeDefaultsModifier(nil), config, config, true)
+		Expect(plan).To(Equal(stringMapValue(map[string]string{})))
+	})
+
+	It("Keeps the state when updating without configured values", func() {
+		config := types.Map{ElemType: types.StringType, Null: true}
+		state := stringMapValue(map[string]string{
+			"owner": "team-c",
+		})
+		plan := modify(config, state, false)
+		Expect(plan).To(Equal(state))
+	})
+
+	It("Doesn't change unknown values", func() {
+		config := types.Map{ElemType: types.StringType, Unknown: true}
+		plan := modify(config, types.Map{ElemType: types.StringType, Null: true}, true)
+		Expect(plan).To(Equal(config))
+	})
+
+	It("Doesn't change the plan when the resource is deleted", func() {
+		config := types.Map{ElemType: types.StringType, Null: true}
+		request := tfsdk.ModifyAttributePlanRequest{
+			AttributeConfig: config,
+			AttributeState:  stringMapValue(defaults),
+			AttributePlan:   config,
+			State:           tfsdk.State{Raw: object},
+			Plan:            tfsdk.Plan{Raw: nullObject},
+		}
+		response := &tfsdk.ModifyAttributePlanResponse{
+			AttributePlan: request.AttributePlan,
+		}
+		MergeDefaultsModifier(defaults).Modify(ctx, request, response)
+		Expect(response.AttributePlan).To(Equal(config))
+	})
+
+	Context("For values that can't be changed", func() {
+		modify := func(config, state types.Map, creating bool) attr.Value {
+			return modifyWith(MergeCreateDefaultsModifier(defaults), config, state, creating)
+		}
+
+		It("Adds the defaults when creating", func() {
+			config := stringMapValue(map[string]string{
+				"env": "prod",
+			})
+			plan := modify(config, types.Map{ElemType: types.StringType, Null: true}, true)
+			Expect(plan).To(Equal(stringMapValue(map[string]string{
+				"cost-center": "123",
+				"owner":       "team-a",
+				"env":         "prod",
+			})))
+		})
+
+		It("Keeps the state when the defaults change", func() {
+			config := stringMapValue(map[string]string{
+				"env": "prod",
+			})
+			state := stringMapValue(map[string]string{
+				"cost-center": "456",
+				"env":         "prod",
+			})
+			plan := modify(config, state, false)
+			Expect(plan).To(Equal(state))
+		})
+
+		It("Merges the defaults when a configured value changes", func() {
+			config := stringMapValue(map[string]string{
+				"env": "dev",
+			})
+			state := stringMapValue(map[string]string{
+				"cost-center": "456",
+				"env":         "prod",
+			})
+			plan := modify(config, state, false)
+			Expect(plan).To(Equal(stringMapValue(map[string]string{
+				"cost-center": "123",
+				"owner":       "team-a",
+				"env":         "dev",
+			})))
+		})
+	})
+})
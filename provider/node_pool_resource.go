@@ -0,0 +1,702 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	semver "github.com/hashicorp/go-version"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/terraform-redhat/terraform-provider-ocm/provider/common"
+
+	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
+	"github.com/openshift-online/ocm-sdk-go/logging"
+)
+
+const (
+	nodePoolImportIDFormat = "<cluster_id>,<node_pool_id>"
+)
+
+type NodePoolResourceType struct {
+	logger logging.Logger
+}
+
+type NodePoolResource struct {
+	logger     logging.Logger
+	collection *cmv1.ClustersClient
+}
+
+func (t *NodePoolResourceType) GetSchema(ctx context.Context) (result tfsdk.Schema,
+	diags diag.Diagnostics) {
+	result = tfsdk.Schema{
+		Description: "Node pool of a cluster with a hosted control plane.",
+		Attributes: map[string]tfsdk.Attribute{
+			"cluster": {
+				Description: "Identifier of the cluster.",
+				Type:        types.StringType,
+				Required:    true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
+			"id": {
+				Description: "Unique identifier of the node pool.",
+				Type:        types.StringType,
+				Computed:    true,
+			},
+			"name": {
+				Description: "Name of the node pool. Must consist of lower-case alphanumeric characters or '-', start with an alphabetic character, and end with an alphanumeric character.",
+				Type:        types.StringType,
+				Required:    true,
+				Validators: []tfsdk.AttributeValidator{
+					stringAttributeValidator("Validate node pool name", func(value string) string {
+						if !machinepoolNameRE.MatchString(value) {
+							return fmt.Sprintf("Expected a valid value for 'name' matching %s", machinepoolNameRE)
+						}
+						return ""
+					}),
+				},
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
+			"subnet_id": {
+				Description: "Identifier of the AWS subnet of the nodes. It must be one of the subnets of the cluster.",
+				Type:        types.StringType,
+				Optional:    true,
+				Computed:    true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
+			"availability_zone": {
+				Description: "Availability zone of the nodes.",
+				Type:        types.StringType,
+				Computed:    true,
+			},
+			"instance_type": {
+				Description: "Identifier of the AWS instance type used by the nodes, " +
+					"for example `m5.xlarge`. Use the `ocm_machine_types` data " +
+					"source to find the possible values.",
+				Type:     types.StringType,
+				Required: true,
+				PlanModifiers: []tfsdk.AttributePlanModifier{
+					ValueCannotBeChangedModifier(t.logger),
+				},
+			},
+			"replicas": {
+				Description: "The number of nodes of the pool",
+				Type:        types.Int64Type,
+				Optional:    true,
+			},
+			"autoscaling_enabled": {
+				Description: "Enables autoscaling.",
+				Type:        types.BoolType,
+				Optional:    true,
+			},
+			"min_replicas": {
+				Description: "Min replicas.",
+				Type:        types.Int64Type,
+				Optional:    true,
+			},
+			"max_replicas": {
+				Description: "Max replicas.",
+				Type:        types.Int64Type,
+				Optional:    true,
+			},
+			"taints": {
+				Description: "Taints for the node pool. This list will overwrite any modifications " +
+					"made to node taints on an ongoing basis.",
+				Attributes: tfsdk.ListNestedAttributes(map[string]tfsdk.Attribute{
+					"key": {
+						Description: "Taints key",
+						Type:        types.StringType,
+						Required:    true,
+					},
+					"value": {
+						Description: "Taints value",
+						Type:        types.StringType,
+						Required:    true,
+					},
+					"schedule_type": {
+						Description: "Taints schedule type",
+						Type:        types.StringType,
+						Required:    true,
+					},
+				}, tfsdk.ListNestedAttributesOptions{},
+				),
+				Optional: true,
+			},
+			"labels": {
+				Description: "Labels for the node pool. This list will overwrite any modifications " +
+					"made to node labels on an ongoing basis.",
+				Type: types.MapType{
+					ElemType: types.StringType,
+				},
+				Optional: true,
+			},
+			"version": {
+				Description: "Identifier of the version of OpenShift of the nodes, for example 'openshift-v4.12.10'. " +
+					"It is upgraded separately from the control plane, and can't be newer than the version of the control plane.",
+				Type:     types.StringType,
+				Optional: true,
+				Computed: true,
+			},
+			"auto_repair": {
+				Description: "Replaces nodes that are unhealthy automatically. Default value is 'true'.",
+				Type:        types.BoolType,
+				Optional:    true,
+				Computed:    true,
+			},
+		},
+	}
+	return
+}
+
+func (t *NodePoolResourceType) NewResource(ctx context.Context,
+	p tfsdk.Provider) (result tfsdk.Resource, diags diag.Diagnostics) {
+	// Cast the provider interface to the specific implementation: use it directly when needed.
+	parent := p.(*Provider)
+
+	// Get the collection of clusters:
+	collection := parent.connection.ClustersMgmt().V1().Clusters()
+
+	// Create the resource:
+	result = &NodePoolResource{
+		logger:     parent.logger,
+		collection: collection,
+	}
+
+	return
+}
+
+func (r *NodePoolResource) Create(ctx context.Context,
+	request tfsdk.CreateResourceRequest, response *tfsdk.CreateResourceResponse) {
+	// Get the plan:
+	state := &NodePoolState{}
+	diags := request.Plan.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+
+	// Wait till the cluster is ready:
+	resource := r.collection.Cluster(state.Cluster.Value)
+	pollCtx, cancel := context.WithTimeout(ctx, 1*time.Hour)
+	defer cancel()
+	poll, err := resource.Poll().
+		Interval(30 * time.Second).
+		Predicate(func(get *cmv1.ClusterGetResponse) bool {
+			return get.Body().State() == cmv1.ClusterStateReady
+		}).
+		StartContext(pollCtx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't poll cluster state",
+			fmt.Sprintf(
+				"Can't poll state of cluster with identifier '%s': %v",
+				state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+	if !poll.Body().Hypershift().Enabled() {
+		response.Diagnostics.AddError(
+			"Can't create node pool",
+			fmt.Sprintf(
+				"Can't create node pool for cluster '%s', node pools are only supported by "+
+					"clusters with a hosted control plane, use the 'ocm_machine_pool' resource instead",
+				state.Cluster.Value,
+			),
+		)
+		return
+	}
+
+	// Create the node pool:
+	builder := cmv1.NewNodePool().ID(state.Name.Value)
+	builder.AWSNodePool(cmv1.NewAWSNodePool().InstanceType(state.InstanceType.Value))
+
+	if !state.SubnetID.Unknown && !state.SubnetID.Null {
+		builder.Subnet(state.SubnetID.Value)
+	}
+
+	errMsg := getNodePoolScaling(state, builder)
+	if errMsg != "" {
+		response.Diagnostics.AddError(
+			"Can't build node pool",
+			fmt.Sprintf(
+				"Can't build node pool for cluster '%s', %s", state.Cluster.Value, errMsg,
+			),
+		)
+		return
+	}
+
+	if len(state.Taints) > 0 {
+		builder.Taints(getNodePoolTaints(state)...)
+	}
+
+	if !state.Labels.Unknown && !state.Labels.Null {
+		builder.Labels(getNodePoolLabels(state))
+	}
+
+	if !state.Version.Unknown && !state.Version.Null {
+		errMsg = checkNodePoolVersion(state.Version.Value, poll.Body().Version().ID())
+		if errMsg != "" {
+			response.Diagnostics.AddError(
+				"Can't build node pool",
+				fmt.Sprintf(
+					"Can't build node pool for cluster '%s', %s", state.Cluster.Value, errMsg,
+				),
+			)
+			return
+		}
+		builder.Version(cmv1.NewVersion().ID(state.Version.Value))
+	}
+
+	if !state.AutoRepair.Unknown && !state.AutoRepair.Null {
+		builder.AutoRepair(state.AutoRepair.Value)
+	}
+
+	object, err := builder.Build()
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't build node pool",
+			fmt.Sprintf(
+				"Can't build node pool for cluster '%s': %v",
+				state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+
+	add, err := resource.NodePools().Add().Body(object).SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't create node pool",
+			fmt.Sprintf(
+				"Can't create node pool for cluster '%s': %v",
+				state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+	object = add.Body()
+
+	// Save the state:
+	r.populateState(object, state)
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *NodePoolResource) Read(ctx context.Context, request tfsdk.ReadResourceRequest,
+	response *tfsdk.ReadResourceResponse) {
+	// Get the current state:
+	state := &NodePoolState{}
+	diags := request.State.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+	ctx = withObjectLogField(ctx, state.ID.Value)
+
+	// Find the node pool:
+	get, err := r.collection.Cluster(state.Cluster.Value).
+		NodePools().
+		NodePool(state.ID.Value).
+		Get().
+		SendContext(ctx)
+	if err != nil {
+		if common.IsStatusNotFound(err) {
+			r.logger.Warn(ctx, "Node pool wasn't found, removing it from the state")
+			response.State.RemoveResource(ctx)
+			return
+		}
+		response.Diagnostics.AddError(
+			"Can't find node pool",
+			fmt.Sprintf(
+				"Can't find node pool with identifier '%s' for "+
+					"cluster '%s': %v",
+				state.ID.Value, state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+	object := get.Body()
+
+	// Save the state:
+	r.populateState(object, state)
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *NodePoolResource) Update(ctx context.Context, request tfsdk.UpdateResourceRequest,
+	response *tfsdk.UpdateResourceResponse) {
+	var diags diag.Diagnostics
+
+	// Get the state:
+	state := &NodePoolState{}
+	diags = request.State.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+	ctx = withObjectLogField(ctx, state.ID.Value)
+
+	// Get the plan:
+	plan := &NodePoolState{}
+	diags = request.Plan.Get(ctx, plan)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	builder := cmv1.NewNodePool().ID(state.ID.Value)
+
+	errMsg := getNodePoolScaling(plan, builder)
+	if errMsg != "" {
+		response.Diagnostics.AddError(
+			"Can't update node pool",
+			fmt.Sprintf(
+				"Can't update node pool for cluster '%s', %s", state.Cluster.Value, errMsg,
+			),
+		)
+		return
+	}
+
+	// Taints and labels are always sent, so that removing them from the configuration also
+	// removes them from the node pool:
+	builder.Taints(getNodePoolTaints(plan)...)
+	builder.Labels(getNodePoolLabels(plan))
+
+	version, ok := common.ShouldPatchString(state.Version, plan.Version)
+	if ok {
+		get, err := r.collection.Cluster(state.Cluster.Value).Get().SendContext(ctx)
+		if err != nil {
+			response.Diagnostics.AddError(
+				"Can't find cluster",
+				fmt.Sprintf(
+					"Can't find cluster with identifier '%s': %v",
+					state.Cluster.Value, err,
+				),
+			)
+			return
+		}
+		errMsg = checkNodePoolVersion(version, get.Body().Version().ID())
+		if errMsg != "" {
+			response.Diagnostics.AddError(
+				"Can't update node pool",
+				fmt.Sprintf(
+					"Can't update node pool for cluster '%s', %s", state.Cluster.Value, errMsg,
+				),
+			)
+			return
+		}
+		builder.Version(cmv1.NewVersion().ID(version))
+	}
+
+	if !plan.AutoRepair.Unknown && !plan.AutoRepair.Null {
+		builder.AutoRepair(plan.AutoRepair.Value)
+	}
+
+	nodePool, err := builder.Build()
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't update node pool",
+			fmt.Sprintf(
+				"Can't update node pool for cluster '%s': %v", state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+	update, err := r.collection.Cluster(state.Cluster.Value).
+		NodePools().
+		NodePool(state.ID.Value).
+		Update().
+		Body(nodePool).
+		SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Failed to update node pool",
+			fmt.Sprintf(
+				"Failed to update node pool '%s' on cluster '%s': %v",
+				state.ID.Value, state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+
+	object := update.Body()
+
+	// update the autoscaling enabled with the plan value (important for nil and false cases)
+	state.AutoScalingEnabled = plan.AutoScalingEnabled
+	// update the Replicas with the plan value (important for nil and zero value cases)
+	state.Replicas = plan.Replicas
+
+	// Save the state:
+	r.populateState(object, state)
+	diags = response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+func (r *NodePoolResource) Delete(ctx context.Context, request tfsdk.DeleteResourceRequest,
+	response *tfsdk.DeleteResourceResponse) {
+	// Get the state:
+	state := &NodePoolState{}
+	diags := request.State.Get(ctx, state)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+	ctx = withClusterLogField(ctx, state.Cluster.Value)
+	ctx = withObjectLogField(ctx, state.ID.Value)
+
+	// Send the request to delete the node pool:
+	_, err := r.collection.Cluster(state.Cluster.Value).
+		NodePools().
+		NodePool(state.ID.Value).
+		Delete().
+		SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't delete node pool",
+			fmt.Sprintf(
+				"Can't delete node pool with identifier '%s' for "+
+					"cluster '%s': %v",
+				state.ID.Value, state.Cluster.Value, err,
+			),
+		)
+		return
+	}
+
+	// Remove the state:
+	response.State.RemoveResource(ctx)
+}
+
+func (r *NodePoolResource) ImportState(ctx context.Context, request tfsdk.ImportResourceStateRequest,
+	response *tfsdk.ImportResourceStateResponse) {
+	fields, err := common.SplitImportID(request.ID, nodePoolImportIDFormat)
+	if err != nil {
+		response.Diagnostics.AddError("Invalid import identifier", err.Error())
+		return
+	}
+	clusterID := fields[0]
+	nodePoolID := fields[1]
+
+	// Try to retrieve the object:
+	get, err := r.collection.Cluster(clusterID).
+		NodePools().
+		NodePool(nodePoolID).
+		Get().
+		SendContext(ctx)
+	if err != nil {
+		response.Diagnostics.AddError(
+			"Can't find node pool",
+			fmt.Sprintf(
+				"Can't find node pool with identifier '%s' for "+
+					"cluster '%s': %v",
+				nodePoolID, clusterID, err,
+			),
+		)
+		return
+	}
+	object := get.Body()
+
+	// Save the state:
+	state := &NodePoolState{
+		Cluster: types.String{
+			Value: clusterID,
+		},
+		Replicas: types.Int64{
+			Null: true,
+		},
+		AutoScalingEnabled: types.Bool{
+			Null: true,
+		},
+	}
+	r.populateState(object, state)
+	diags := response.State.Set(ctx, state)
+	response.Diagnostics.Append(diags...)
+}
+
+// getNodePoolScaling sets either the replicas or the autoscaling of the node pool, and returns
+// an error message if the plan doesn't contain exactly one of them.
+func getNodePoolScaling(state *NodePoolState, builder *cmv1.NodePoolBuilder) (errMsg string) {
+	autoscalingEnabled := !state.AutoScalingEnabled.Unknown && !state.AutoScalingEnabled.Null &&
+		state.AutoScalingEnabled.Value
+	minReplicasSet := !state.MinReplicas.Unknown && !state.MinReplicas.Null
+	maxReplicasSet := !state.MaxReplicas.Unknown && !state.MaxReplicas.Null
+	replicasSet := !state.Replicas.Unknown && !state.Replicas.Null
+
+	if autoscalingEnabled {
+		if !maxReplicasSet {
+			return "when enabling autoscaling, should set value for maxReplicas"
+		}
+		if !minReplicasSet {
+			return "when enabling autoscaling, should set value for minReplicas"
+		}
+		if replicasSet {
+			return "should hold either autoscaling or replicas"
+		}
+		builder.Autoscaling(cmv1.NewNodePoolAutoscaling().
+			MinReplica(int(state.MinReplicas.Value)).
+			MaxReplica(int(state.MaxReplicas.Value)))
+		return ""
+	}
+
+	if minReplicasSet || maxReplicasSet {
+		return "when disabling autoscaling, can't set min_replicas and/or max_replicas"
+	}
+	if !replicasSet {
+		return "should hold either autoscaling or replicas"
+	}
+	builder.Replicas(int(state.Replicas.Value))
+	return ""
+}
+
+func getNodePoolTaints(state *NodePoolState) []*cmv1.TaintBuilder {
+	taintBuilders := []*cmv1.TaintBuilder{}
+	for _, taint := range state.Taints {
+		taintBuilders = append(taintBuilders, cmv1.NewTaint().
+			Key(taint.Key.Value).
+			Value(taint.Value.Value).
+			Effect(taint.ScheduleType.Value))
+	}
+	return taintBuilders
+}
+
+func getNodePoolLabels(state *NodePoolState) map[string]string {
+	labels := map[string]string{}
+	if !state.Labels.Unknown && !state.Labels.Null {
+		for k, v := range state.Labels.Elems {
+			labels[k] = v.(types.String).Value
+		}
+	}
+	return labels
+}
+
+// checkNodePoolVersion returns an error message if the version of the node pool is newer than
+// the version of the control plane.
+func checkNodePoolVersion(nodePoolVersion, controlPlaneVersion string) (errMsg string) {
+	if controlPlaneVersion == "" {
+		return ""
+	}
+	v1, err := semver.NewVersion(strings.Replace(nodePoolVersion, "openshift-v", "", 1))
+	if err != nil {
+		return fmt.Sprintf("invalid version '%s': %v", nodePoolVersion, err)
+	}
+	v2, err := semver.NewVersion(strings.Replace(controlPlaneVersion, "openshift-v", "", 1))
+	if err != nil {
+		return fmt.Sprintf("invalid control plane version '%s': %v", controlPlaneVersion, err)
+	}
+	if v1.GreaterThan(v2) {
+		return fmt.Sprintf("version '%s' is newer than the version of the control plane '%s'",
+			nodePoolVersion, controlPlaneVersion)
+	}
+	return ""
+}
+
+// populateState copies the data from the API object to the Terraform state.
+func (r *NodePoolResource) populateState(object *cmv1.NodePool, state *NodePoolState) {
+	state.ID = types.String{
+		Value: object.ID(),
+	}
+	state.Name = types.String{
+		Value: object.ID(),
+	}
+	state.SubnetID = types.String{
+		Value: object.Subnet(),
+	}
+	state.AvailabilityZone = types.String{
+		Value: object.AvailabilityZone(),
+	}
+
+	instanceType, ok := object.AWSNodePool().GetInstanceType()
+	if ok {
+		state.InstanceType = types.String{
+			Value: instanceType,
+		}
+	}
+
+	autoscaling, ok := object.GetAutoscaling()
+	if ok {
+		state.AutoScalingEnabled = types.Bool{Value: true}
+		state.MinReplicas = types.Int64{
+			Value: int64(autoscaling.MinReplica()),
+		}
+		state.MaxReplicas = types.Int64{
+			Value: int64(autoscaling.MaxReplica()),
+		}
+	} else {
+		state.MaxReplicas.Null = true
+		state.MinReplicas.Null = true
+	}
+
+	replicas, ok := object.GetReplicas()
+	if ok {
+		state.Replicas = types.Int64{
+			Value: int64(replicas),
+		}
+	}
+
+	taints := object.Taints()
+	if len(taints) > 0 {
+		state.Taints = make([]Taints, len(taints))
+		for i, taint := range taints {
+			state.Taints[i] = Taints{
+				Key:          types.String{Value: taint.Key()},
+				Value:        types.String{Value: taint.Value()},
+				ScheduleType: types.String{Value: taint.Effect()},
+			}
+		}
+	} else {
+		state.Taints = nil
+	}
+
+	labels := object.Labels()
+	if len(labels) > 0 {
+		state.Labels = types.Map{
+			ElemType: types.StringType,
+			Elems:    map[string]attr.Value{},
+		}
+		for k, v := range labels {
+			state.Labels.Elems[k] = types.String{
+				Value: v,
+			}
+		}
+	} else {
+		state.Labels = types.Map{
+			ElemType: types.StringType,
+			Null:     true,
+		}
+	}
+
+	state.Version = types.String{
+		Value: object.Version().ID(),
+	}
+	state.AutoRepair = types.Bool{
+		Value: object.AutoRepair(),
+	}
+}
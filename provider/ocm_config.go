@@ -0,0 +1,72 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// OCMConfig contains the settings of the configuration file of the ocm CLI that are used by the
+// provider. The file is created by the 'ocm login' command.
+type OCMConfig struct {
+	URL          string `json:"url,omitempty"`
+	TokenURL     string `json:"token_url,omitempty"`
+	ClientID     string `json:"client_id,omitempty"`
+	ClientSecret string `json:"client_secret,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+// ocmConfigLocation returns the location of the configuration file of the ocm CLI. It is the value
+// of the 'OCM_CONFIG' environment variable if it is set. Otherwise it is '~/.ocm.json' if that
+// file exists, and 'ocm/ocm.json' inside the user configuration directory if it doesn't.
+func ocmConfigLocation() (string, error) {
+	path, ok := os.LookupEnv("OCM_CONFIG")
+	if ok && path != "" {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	path = filepath.Join(home, ".ocm.json")
+	_, err = os.Stat(path)
+	if err == nil {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return path, nil
+	}
+	return filepath.Join(dir, "ocm", "ocm.json"), nil
+}
+
+// loadOCMConfig loads the configuration file of the ocm CLI from the given path.
+func loadOCMConfig(path string) (*OCMConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	config := &OCMConfig{}
+	err = json.Unmarshal(data, config)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse ocm CLI configuration file '%s': %v", path, err)
+	}
+	return config, nil
+}
@@ -0,0 +1,92 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+)
+
+var _ = Describe("ocm CLI configuration file", func() {
+	var tmpDir string
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = os.MkdirTemp("", "ocm-config-*.d")
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		err := os.RemoveAll(tmpDir)
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("Loads the settings written by 'ocm login'", func() {
+		path := filepath.Join(tmpDir, "ocm.json")
+		err := os.WriteFile(path, []byte(`{
+		  "access_token": "my-access-token",
+		  "client_id": "cloud-services",
+		  "refresh_token": "my-refresh-token",
+		  "scopes": ["openid"],
+		  "token_url": "https://sso.example.com/token",
+		  "url": "https://api.example.com"
+		}`), 0600)
+		Expect(err).ToNot(HaveOccurred())
+
+		config, err := loadOCMConfig(path)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(config.URL).To(Equal("https://api.example.com"))
+		Expect(config.TokenURL).To(Equal("https://sso.example.com/token"))
+		Expect(config.ClientID).To(Equal("cloud-services"))
+		Expect(config.ClientSecret).To(BeEmpty())
+		Expect(config.RefreshToken).To(Equal("my-refresh-token"))
+	})
+
+	It("Fails if the file doesn't exist", func() {
+		_, err := loadOCMConfig(filepath.Join(tmpDir, "missing.json"))
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	It("Fails if the file isn't valid JSON", func() {
+		path := filepath.Join(tmpDir, "ocm.json")
+		err := os.WriteFile(path, []byte("junk"), 0600)
+		Expect(err).ToNot(HaveOccurred())
+
+		_, err = loadOCMConfig(path)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Uses the location from the 'OCM_CONFIG' environment variable", func() {
+		path := filepath.Join(tmpDir, "ocm.json")
+		previous, ok := os.LookupEnv("OCM_CONFIG")
+		os.Setenv("OCM_CONFIG", path)
+		defer func() {
+			if ok {
+				os.Setenv("OCM_CONFIG", previous)
+			} else {
+				os.Unsetenv("OCM_CONFIG")
+			}
+		}()
+
+		location, err := ocmConfigLocation()
+		Expect(err).ToNot(HaveOccurred())
+		Expect(location).To(Equal(path))
+	})
+})
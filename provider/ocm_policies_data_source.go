@@ -155,7 +155,7 @@ func (t *OcmPoliciesDataSource) Read(ctx context.Context, request tfsdk.ReadData
 	operatorRolePolicies := OperatorRolePolicies{}
 	accountRolePolicies := AccountRolePolicies{}
 	policiesResponse.Items().Each(func(awsPolicy *cmv1.AWSSTSPolicy) bool {
-		t.logger.Debug(ctx, "policy id: %s ", awsPolicy.ID())
+		t.logger.Debug(withObjectLogField(ctx, awsPolicy.ID()), "Found policy")
 		switch awsPolicy.ID() {
 		// operator roles
 		case CloudCred:
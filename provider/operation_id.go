@@ -0,0 +1,98 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+)
+
+// operationIDRecorderKey is the key used to store in the context the recorder of operation
+// identifiers.
+type operationIDRecorderKey struct{}
+
+// operationIDRecorder saves the operation identifier returned by the server for the last request
+// sent with a context, so that the resources can add it to their log messages and diagnostics.
+type operationIDRecorder struct {
+	lock  sync.Mutex
+	value string
+}
+
+// withOperationIDRecorder returns a copy of the context that records the operation identifiers
+// of the requests sent with it.
+func withOperationIDRecorder(ctx context.Context) context.Context {
+	if _, ok := ctx.Value(operationIDRecorderKey{}).(*operationIDRecorder); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, operationIDRecorderKey{}, &operationIDRecorder{})
+}
+
+// recordOperationID saves the given operation identifier in the recorder of the context, if it
+// has one.
+func recordOperationID(ctx context.Context, operationID string) {
+	recorder, ok := ctx.Value(operationIDRecorderKey{}).(*operationIDRecorder)
+	if !ok {
+		return
+	}
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+	recorder.value = operationID
+}
+
+// lastOperationID returns the operation identifier of the last request sent with the context, or
+// an empty string if no request returned one.
+func lastOperationID(ctx context.Context) string {
+	recorder, ok := ctx.Value(operationIDRecorderKey{}).(*operationIDRecorder)
+	if !ok {
+		return ""
+	}
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+	return recorder.value
+}
+
+// addOperationID adds the operation identifier of the last request sent with the context to the
+// details of the errors in the given diagnostics, as that is what support needs to find the
+// request in the logs of the server. It is intended to be deferred by the methods of the resources
+// and data sources.
+func addOperationID(ctx context.Context, diags *diag.Diagnostics) {
+	operationID := lastOperationID(ctx)
+	if operationID == "" {
+		return
+	}
+	for i, current := range *diags {
+		if current.Severity() != diag.SeverityError ||
+			strings.Contains(current.Detail(), operationID) {
+			continue
+		}
+		var updated diag.Diagnostic = diag.NewErrorDiagnostic(
+			current.Summary(),
+			fmt.Sprintf(
+				"%s\n\nOperation identifier of the last request: %s",
+				current.Detail(), operationID,
+			),
+		)
+		if withPath, ok := current.(diag.DiagnosticWithPath); ok {
+			updated = diag.WithPath(withPath.Path(), updated)
+		}
+		(*diags)[i] = updated
+	}
+}
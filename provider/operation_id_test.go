@@ -0,0 +1,66 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+)
+
+var _ = Describe("Operation identifiers", func() {
+	It("Adds the last operation identifier to the errors", func() {
+		ctx := withResourceLogField(context.Background(), "ocm_cluster")
+		recordOperationID(ctx, "my-operation")
+		path := tftypes.NewAttributePath().WithAttributeName("name")
+		diags := diag.Diagnostics{}
+		diags.AddWarning("Can't check cluster", "Something is odd")
+		diags.AddError("Can't create cluster", "Something went wrong")
+		diags.AddAttributeError(path, "Invalid name", "The name is too long")
+		addOperationID(ctx, &diags)
+		Expect(diags).To(HaveLen(3))
+		Expect(diags[0].Detail()).To(Equal("Something is odd"))
+		Expect(diags[1].Summary()).To(Equal("Can't create cluster"))
+		Expect(diags[1].Detail()).To(Equal(
+			"Something went wrong\n\nOperation identifier of the last request: my-operation",
+		))
+		withPath, ok := diags[2].(diag.DiagnosticWithPath)
+		Expect(ok).To(BeTrue())
+		Expect(withPath.Path()).To(Equal(path))
+		Expect(withPath.Detail()).To(ContainSubstring("my-operation"))
+	})
+
+	It("Doesn't repeat the operation identifier", func() {
+		ctx := withResourceLogField(context.Background(), "ocm_cluster")
+		recordOperationID(ctx, "my-operation")
+		diags := diag.Diagnostics{}
+		diags.AddError("Can't create cluster", "Operation identifier is 'my-operation'")
+		addOperationID(ctx, &diags)
+		Expect(diags[0].Detail()).To(Equal("Operation identifier is 'my-operation'"))
+	})
+
+	It("Doesn't change the errors without requests", func() {
+		ctx := withResourceLogField(context.Background(), "ocm_cluster")
+		diags := diag.Diagnostics{}
+		diags.AddError("Can't create cluster", "Something went wrong")
+		addOperationID(ctx, &diags)
+		Expect(diags[0].Detail()).To(Equal("Something went wrong"))
+	})
+})
@@ -20,8 +20,9 @@ import (
 	"context"
 	"crypto/x509"
 	"fmt"
+	"net/http"
 	"os"
-	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-framework/diag"
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
@@ -33,21 +34,65 @@ import (
 
 // Provider is the implementation of the Provider.
 type Provider struct {
-	logger     logging.Logger
-	connection *sdk.Connection
+	logger            logging.Logger
+	connection        *sdk.Connection
+	defaultTags       map[string]string
+	defaultProperties map[string]string
+	clusterDryRun     bool
+	httpClient        *http.Client
+	awsOptions        *awsSessionOptions
 }
 
 // Config contains the configuration of the provider.
 type Config struct {
-	URL          types.String `tfsdk:"url"`
-	TokenURL     types.String `tfsdk:"token_url"`
-	User         types.String `tfsdk:"user"`
-	Password     types.String `tfsdk:"password"`
-	Token        types.String `tfsdk:"token"`
-	ClientID     types.String `tfsdk:"client_id"`
-	ClientSecret types.String `tfsdk:"client_secret"`
-	TrustedCAs   types.String `tfsdk:"trusted_cas"`
-	Insecure     types.Bool   `tfsdk:"insecure"`
+	URL               types.String `tfsdk:"url"`
+	TokenURL          types.String `tfsdk:"token_url"`
+	User              types.String `tfsdk:"user"`
+	Password          types.String `tfsdk:"password"`
+	Token             types.String `tfsdk:"token"`
+	ClientID          types.String `tfsdk:"client_id"`
+	ClientSecret      types.String `tfsdk:"client_secret"`
+	TrustedCAs        types.String `tfsdk:"trusted_cas"`
+	Insecure          types.Bool   `tfsdk:"insecure"`
+	ConfigFile        types.String `tfsdk:"config_file"`
+	Retry             *RetryConfig `tfsdk:"retry"`
+	DefaultTags       types.Map    `tfsdk:"default_tags"`
+	DefaultProperties types.Map    `tfsdk:"default_properties"`
+	ClusterDryRun     types.Bool   `tfsdk:"cluster_dry_run"`
+	Proxy             *ProxyConfig `tfsdk:"proxy"`
+	AWS               *AWSConfig   `tfsdk:"aws"`
+	LogHTTPBodies     types.Bool   `tfsdk:"log_http_bodies"`
+}
+
+// RetryConfig contains the retry policy applied to the requests sent to the API.
+type RetryConfig struct {
+	MaxAttempts types.Int64  `tfsdk:"max_attempts"`
+	BaseBackoff types.String `tfsdk:"base_backoff"`
+	MaxBackoff  types.String `tfsdk:"max_backoff"`
+	StatusCodes types.List   `tfsdk:"status_codes"`
+}
+
+// Token URLs of the SSO servers used by the OCM environments:
+const (
+	productionTokenURL = "https://sso.redhat.com/auth/realms/redhat-external/protocol/openid-connect/token"
+	stagingTokenURL    = "https://sso.stage.redhat.com/auth/realms/redhat-external/protocol/openid-connect/token"
+)
+
+// urlAlias contains the URLs of an OCM environment.
+type urlAlias struct {
+	url      string
+	tokenURL string
+}
+
+// urlAliases are the names that can be used in the 'url' attribute instead of the URL of the
+// API server of an environment. The short names are the ones accepted by the ocm CLI.
+var urlAliases = map[string]urlAlias{
+	"production":  {url: "https://api.openshift.com", tokenURL: productionTokenURL},
+	"prod":        {url: "https://api.openshift.com", tokenURL: productionTokenURL},
+	"staging":     {url: "https://api.stage.openshift.com", tokenURL: stagingTokenURL},
+	"stage":       {url: "https://api.stage.openshift.com", tokenURL: stagingTokenURL},
+	"integration": {url: "https://api.integration.openshift.com", tokenURL: stagingTokenURL},
+	"int":         {url: "https://api.integration.openshift.com", tokenURL: stagingTokenURL},
 }
 
 // New creates the provider.
@@ -60,48 +105,56 @@ func (p *Provider) GetSchema(ctx context.Context) (schema tfsdk.Schema, diags di
 	schema = tfsdk.Schema{
 		Attributes: map[string]tfsdk.Attribute{
 			"url": {
-				Description: "URL of the API server.",
-				Type:        types.StringType,
-				Optional:    true,
+				Description: "URL of the API server, or one of the aliases 'production', " +
+					"'staging' or 'integration', which also select the token URL of the " +
+					"environment. Can also be set with the 'OCM_URL' environment variable.",
+				Type:     types.StringType,
+				Optional: true,
 			},
 			"token_url": {
-				Description: "OpenID token URL.",
-				Type:        types.StringType,
-				Optional:    true,
+				Description: "OpenID token URL. Can also be set with the 'OCM_TOKEN_URL' " +
+					"environment variable.",
+				Type:     types.StringType,
+				Optional: true,
 			},
 			"user": {
-				Description: "User name.",
+				Description: "User name. Can also be set with the 'OCM_USER' environment variable.",
 				Type:        types.StringType,
 				Optional:    true,
 			},
 			"password": {
-				Description: "User password.",
-				Type:        types.StringType,
-				Optional:    true,
-				Sensitive:   true,
+				Description: "User password. Can also be set with the 'OCM_PASSWORD' " +
+					"environment variable.",
+				Type:      types.StringType,
+				Optional:  true,
+				Sensitive: true,
 			},
 			"token": {
-				Description: "Access or refresh token.",
-				Type:        types.StringType,
-				Optional:    true,
-				Sensitive:   true,
+				Description: "Access or refresh token. Can also be set with the 'OCM_TOKEN' " +
+					"environment variable.",
+				Type:      types.StringType,
+				Optional:  true,
+				Sensitive: true,
 			},
 			"client_id": {
-				Description: "OpenID client identifier.",
-				Type:        types.StringType,
-				Optional:    true,
+				Description: "OpenID client identifier. Can also be set with the " +
+					"'OCM_CLIENT_ID' environment variable.",
+				Type:     types.StringType,
+				Optional: true,
 			},
 			"client_secret": {
-				Description: "OpenID client secret.",
-				Type:        types.StringType,
-				Optional:    true,
-				Sensitive:   true,
+				Description: "OpenID client secret. Can also be set with the " +
+					"'OCM_CLIENT_SECRET' environment variable.",
+				Type:      types.StringType,
+				Optional:  true,
+				Sensitive: true,
 			},
 			"trusted_cas": {
 				Description: "PEM encoded certificates of authorities that will " +
 					"be trusted. If this isn't explicitly specified then " +
 					"the provider will trust the certificate authorities " +
-					"trusted by default by the system.",
+					"trusted by default by the system. Can also be set " +
+					"with the 'OCM_TRUSTED_CAS' environment variable.",
 				Type:     types.StringType,
 				Optional: true,
 			},
@@ -109,7 +162,172 @@ func (p *Provider) GetSchema(ctx context.Context) (schema tfsdk.Schema, diags di
 				Description: "When set to 'true' enables insecure communication " +
 					"with the server. This disables verification of TLS " +
 					"certificates and host names and it isn't recommended " +
-					"for production environments.",
+					"for production environments. Can also be set with " +
+					"the 'OCM_INSECURE' environment variable.",
+				Type:     types.BoolType,
+				Optional: true,
+			},
+			"config_file": {
+				Description: "Path of the configuration file of the ocm CLI, created by " +
+					"the 'ocm login' command. The URL, token URL, client identifier and " +
+					"refresh token that aren't explicitly specified are taken from this " +
+					"file. If this isn't explicitly specified then the file is only used " +
+					"when no credentials are given, and it is located with the 'OCM_CONFIG' " +
+					"environment variable or in the default location of the ocm CLI.",
+				Type:     types.StringType,
+				Optional: true,
+			},
+			"retry": {
+				Description: "Retry policy applied to all the requests sent to the API. " +
+					"Requests that fail with a connection error or with one of the " +
+					"retryable status codes are sent again, waiting the time requested " +
+					"by the 'Retry-After' header of the response or an exponentially " +
+					"growing backoff. The default settings are used when the block " +
+					"isn't specified.",
+				Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+					"max_attempts": {
+						Description: "Maximum number of attempts for each request, " +
+							"including the first one. Default value is 5.",
+						Type:     types.Int64Type,
+						Optional: true,
+					},
+					"base_backoff": {
+						Description: "Time to wait before the first retry, for example " +
+							"'500ms'. It is doubled for each retry. Default value is '1s'.",
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"max_backoff": {
+						Description: "Maximum time to wait between retries, for example " +
+							"'1m'. Default value is '30s'.",
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"status_codes": {
+						Description: "HTTP status codes that are retried. Default value " +
+							"is [429, 502, 503, 504].",
+						Type: types.ListType{
+							ElemType: types.Int64Type,
+						},
+						Optional: true,
+					},
+				}),
+				Optional: true,
+			},
+			"default_tags": {
+				Description: "Tags applied to all the resources created in AWS for the " +
+					"clusters managed by the provider. Tags with the same key in the " +
+					"'tags' attribute of a cluster take precedence.",
+				Type: types.MapType{
+					ElemType: types.StringType,
+				},
+				Optional: true,
+			},
+			"default_properties": {
+				Description: "Properties added to all the clusters managed by the " +
+					"provider. Properties with the same key in the 'properties' " +
+					"attribute of a cluster take precedence.",
+				Type: types.MapType{
+					ElemType: types.StringType,
+				},
+				Optional: true,
+			},
+			"cluster_dry_run": {
+				Description: "When 'true', the default, the clusters that will be created " +
+					"are sent to the API in dry run mode while planning, so that errors " +
+					"like missing quota, overlapping CIDRs or invalid subnets are reported " +
+					"by the plan instead of during the apply. Can also be set with the " +
+					"'OCM_CLUSTER_DRY_RUN' environment variable.",
+				Type:     types.BoolType,
+				Optional: true,
+			},
+			"proxy": {
+				Description: "Proxy used for all the connections opened by the provider: " +
+					"to the API, to the token endpoint, to the OIDC issuers and to AWS. If " +
+					"this isn't explicitly specified then the proxy given by the 'HTTPS_PROXY', " +
+					"'HTTP_PROXY' and 'NO_PROXY' environment variables is used.",
+				Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+					"url": {
+						Description: "URL of the proxy, for example " +
+							"'http://proxy.example.com:3128'.",
+						Type:     types.StringType,
+						Required: true,
+					},
+					"no_proxy": {
+						Description: "Comma separated list of host names, domains, IP " +
+							"addresses and CIDRs that are reached directly instead of " +
+							"through the proxy, with the same format as the 'NO_PROXY' " +
+							"environment variable.",
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"ca_bundle": {
+						Description: "PEM encoded certificates of the authorities that " +
+							"sign the certificates presented by the proxy when it " +
+							"intercepts TLS connections. They are trusted in addition " +
+							"to the certificate authorities trusted by default.",
+						Type:     types.StringType,
+						Optional: true,
+					},
+				}),
+				Optional: true,
+			},
+			"aws": {
+				Description: "AWS credentials used for the checks that the provider runs " +
+					"before creating clusters, like the validation of the account roles. " +
+					"If this isn't explicitly specified then the credentials are taken from " +
+					"the environment, like the AWS CLI does.",
+				Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+					"profile": {
+						Description: "Name of the profile of the shared configuration " +
+							"and credentials files.",
+						Type:     types.StringType,
+						Optional: true,
+					},
+					"shared_config_files": {
+						Description: "Paths of the shared configuration files. If this " +
+							"isn't explicitly specified then the default location is used.",
+						Type: types.ListType{
+							ElemType: types.StringType,
+						},
+						Optional: true,
+					},
+					"assume_role": {
+						Description: "Role that is assumed with the credentials.",
+						Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+							"role_arn": {
+								Description: "ARN of the role.",
+								Type:        types.StringType,
+								Required:    true,
+							},
+							"external_id": {
+								Description: "External identifier required by the " +
+									"trust policy of the role.",
+								Type:     types.StringType,
+								Optional: true,
+							},
+							"session_name": {
+								Description: "Name of the session.",
+								Type:        types.StringType,
+								Optional:    true,
+							},
+						}),
+						Optional: true,
+					},
+					"endpoint": {
+						Description: "URL used instead of the endpoints of the AWS " +
+							"services, for example the one of a local AWS emulator.",
+						Type:     types.StringType,
+						Optional: true,
+					},
+				}),
+				Optional: true,
+			},
+			"log_http_bodies": {
+				Description: "When 'true' the bodies of the requests sent to the API and of " +
+					"their responses are logged in the 'http' log subsystem, with the " +
+					"secrets redacted. The default is 'false'. Can also be set with the " +
+					"'OCM_LOG_HTTP_BODIES' environment variable.",
 				Type:     types.BoolType,
 				Optional: true,
 			},
@@ -130,67 +348,138 @@ func (p *Provider) Configure(ctx context.Context, request tfsdk.ConfigureProvide
 		return
 	}
 
-	// Determine the log level used by the SDK from the environment variables used by Terraform:
-	level := os.Getenv("TF_LOG")
-
-	// The plugin infrastructure redirects the log package output so that it is sent to the main
-	// Terraform process, so if we want to have the logs of the SDK redirected we need to use
-	// the log package as well.
-	logger, err := logging.NewGoLoggerBuilder().
-		Error(true).
-		Warn(true).
-		Info(true).
-		Debug(strings.EqualFold(level, "DEBUG")).
-		Build()
-	if err != nil {
-		response.Diagnostics.AddError(err.Error(), "")
-		return
-	}
+	// Send the logs to the tflog subsystems, so that they are collected by Terraform and can be
+	// filtered with the 'TF_LOG' and 'TF_LOG_PROVIDER_OCM_*' environment variables:
+	logger := NewTFLogger(logSubsystemResources)
+	httpLogger := NewTFLogger(logSubsystemHTTP)
 
 	// Create the builder:
 	builder := sdk.NewConnectionBuilder()
-	builder.Logger(logger)
+	builder.Logger(newSDKLogger())
 	builder.Agent(fmt.Sprintf("OCM-TF/%s-%s", build.Version, build.Commit))
 
-	// Copy the settings:
-	if !config.URL.Null {
-		builder.URL(config.URL.Value)
-	} else {
-		url, ok := os.LookupEnv("OCM_URL")
-		if ok {
-			builder.URL(url)
+	// Copy the settings, using the environment variables for the ones that aren't explicitly
+	// specified:
+	settings, diags := newConnectionSettings(&config, os.Getenv)
+	response.Diagnostics.Append(diags...)
+	if response.Diagnostics.HasError() {
+		return
+	}
+
+	// Use the configuration file of the ocm CLI when it is explicitly given, or when there are
+	// no credentials in the provider configuration or in the environment:
+	if !config.ConfigFile.Null || !settings.hasCredentials() {
+		path := config.ConfigFile.Value
+		if config.ConfigFile.Null {
+			var err error
+			path, err = ocmConfigLocation()
+			if err != nil {
+				response.Diagnostics.AddError(
+					"Can't find ocm CLI configuration file",
+					err.Error(),
+				)
+				return
+			}
+		}
+		ocmConfig, err := loadOCMConfig(path)
+		switch {
+		case err == nil:
+			settings.mergeOCMConfig(ocmConfig)
+			response.Diagnostics.AddWarning(
+				"Using ocm CLI configuration file",
+				fmt.Sprintf(
+					"Settings that aren't explicitly specified in the provider "+
+						"configuration or in the environment are taken from the ocm CLI "+
+						"configuration file '%s'",
+					path,
+				),
+			)
+		case os.IsNotExist(err) && config.ConfigFile.Null:
+			// Nothing, the ocm CLI configuration file is optional unless it is explicitly given.
+		default:
+			response.Diagnostics.AddError(
+				"Can't load ocm CLI configuration file",
+				err.Error(),
+			)
+			return
 		}
 	}
-	if !config.TokenURL.Null {
-		builder.TokenURL(config.TokenURL.Value)
+	settings.resolveURLAlias()
+
+	if settings.url != "" {
+		builder.URL(settings.url)
 	}
-	if !config.User.Null && !config.Password.Null {
-		builder.User(config.User.Value, config.Password.Value)
+	if settings.tokenURL != "" {
+		builder.TokenURL(settings.tokenURL)
 	}
-	if !config.Token.Null {
-		builder.Tokens(config.Token.Value)
-	} else {
-		token, ok := os.LookupEnv("OCM_TOKEN")
-		if ok {
-			builder.Tokens(token)
-		}
+	if settings.user != "" && settings.password != "" {
+		builder.User(settings.user, settings.password)
 	}
-	if !config.ClientID.Null && !config.ClientSecret.Null {
-		builder.Client(config.ClientID.Value, config.ClientSecret.Value)
+	if settings.token != "" {
+		builder.Tokens(settings.token)
+	}
+	if settings.clientID != "" {
+		builder.Client(settings.clientID, settings.clientSecret)
+	}
+	builder.Insecure(settings.insecure)
+
+	// The retry policy of the provider replaces the one built into the SDK, and is applied with
+	// the default settings when the 'retry' block isn't specified:
+	retryWrapper, errMsg := newRetryTransportWrapper(httpLogger, config.Retry)
+	if errMsg != "" {
+		response.Diagnostics.AddError("Invalid retry policy", errMsg)
+		return
 	}
-	if !config.Insecure.Null {
-		builder.Insecure(config.Insecure.Value)
+	builder.RetryLimit(0)
+	builder.TransportWrapper(retryWrapper.Wrap)
+
+	// Log each attempt of each request, with the bodies only when explicitly requested, as they
+	// may be large:
+	builder.TransportWrapper(NewLogTransportWrapper(httpLogger, settings.logHTTPBodies).Wrap)
+	var proxy *OutboundProxy
+	if config.Proxy != nil {
+		var errMsg string
+		proxy, errMsg = newOutboundProxy(config.Proxy)
+		if errMsg != "" {
+			response.Diagnostics.AddError("Invalid proxy configuration", errMsg)
+			return
+		}
+
+		// The proxy wrapper needs to be the last one, as it replaces the transport created
+		// by the SDK:
+		builder.TransportWrapper(proxy.Wrap)
 	}
-	if !config.TrustedCAs.Null {
+	if settings.trustedCAs != "" {
 		pool := x509.NewCertPool()
-		if !pool.AppendCertsFromPEM([]byte(config.TrustedCAs.Value)) {
+		if !pool.AppendCertsFromPEM([]byte(settings.trustedCAs)) {
 			response.Diagnostics.AddError(
 				"the value of 'trusted_cas' doesn't contain any certificate",
 				"",
 			)
 			return
 		}
+		if proxy != nil {
+			proxy.AppendCAs(pool)
+		}
 		builder.TrustedCAs(pool)
+	} else if proxy != nil && !config.Proxy.CABundle.Null {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		proxy.AppendCAs(pool)
+		builder.TrustedCAs(pool)
+	}
+
+	// Prepare the options of the AWS sessions, which use the proxy as well:
+	var httpClient *http.Client
+	if proxy != nil {
+		httpClient = proxy.Client()
+	}
+	awsOptions, errMsg := newAWSSessionOptions(config.AWS, httpClient)
+	if errMsg != "" {
+		response.Diagnostics.AddError("Invalid AWS configuration", errMsg)
+		return
 	}
 
 	// Create the connection:
@@ -200,37 +489,131 @@ func (p *Provider) Configure(ctx context.Context, request tfsdk.ConfigureProvide
 		return
 	}
 
-	// Save the connection:
+	// Save the connection and the settings used by the resources:
 	p.logger = logger
 	p.connection = connection
+	p.defaultTags = stringMap(config.DefaultTags)
+	p.defaultProperties = stringMap(config.DefaultProperties)
+	p.clusterDryRun = settings.clusterDryRun
+	p.httpClient = httpClient
+	p.awsOptions = awsOptions
+}
+
+// stringMap converts the given string map value to a Go map. Unknown and null values result in
+// an empty map.
+func stringMap(value types.Map) map[string]string {
+	result := map[string]string{}
+	if value.Unknown || value.Null {
+		return result
+	}
+	for k, v := range value.Elems {
+		result[k] = v.(types.String).Value
+	}
+	return result
+}
+
+// newRetryTransportWrapper creates the retry transport wrapper from the 'retry' block of the
+// provider, using the default values for the settings that aren't explicitly specified, or for
+// all of them if the block isn't specified.
+func newRetryTransportWrapper(logger logging.Logger, config *RetryConfig) (
+	wrapper *RetryTransportWrapper, errMsg string) {
+	if config == nil {
+		wrapper = NewRetryTransportWrapper(logger, defaultRetryMaxAttempts,
+			defaultRetryBaseBackoff, defaultRetryMaxBackoff, defaultRetryStatusCodes)
+		return
+	}
+	maxAttempts := defaultRetryMaxAttempts
+	if !config.MaxAttempts.Null && !config.MaxAttempts.Unknown {
+		if config.MaxAttempts.Value < 1 {
+			return nil, fmt.Sprintf(
+				"Expected a positive value for 'max_attempts', got %d",
+				config.MaxAttempts.Value,
+			)
+		}
+		maxAttempts = int(config.MaxAttempts.Value)
+	}
+	baseBackoff := defaultRetryBaseBackoff
+	if !config.BaseBackoff.Null && !config.BaseBackoff.Unknown {
+		value, err := time.ParseDuration(config.BaseBackoff.Value)
+		if err != nil || value < 0 {
+			return nil, fmt.Sprintf(
+				"Expected a valid duration for 'base_backoff', got '%s'",
+				config.BaseBackoff.Value,
+			)
+		}
+		baseBackoff = value
+	}
+	maxBackoff := defaultRetryMaxBackoff
+	if !config.MaxBackoff.Null && !config.MaxBackoff.Unknown {
+		value, err := time.ParseDuration(config.MaxBackoff.Value)
+		if err != nil || value < 0 {
+			return nil, fmt.Sprintf(
+				"Expected a valid duration for 'max_backoff', got '%s'",
+				config.MaxBackoff.Value,
+			)
+		}
+		maxBackoff = value
+	}
+	if maxBackoff < baseBackoff {
+		return nil, fmt.Sprintf(
+			"Expected 'max_backoff' to be greater than or equal to 'base_backoff', got '%s' and '%s'",
+			maxBackoff, baseBackoff,
+		)
+	}
+	statusCodes := defaultRetryStatusCodes
+	if !config.StatusCodes.Null && !config.StatusCodes.Unknown {
+		statusCodes = []int{}
+		for _, elem := range config.StatusCodes.Elems {
+			statusCodes = append(statusCodes, int(elem.(types.Int64).Value))
+		}
+	}
+	wrapper = NewRetryTransportWrapper(logger, maxAttempts, baseBackoff, maxBackoff, statusCodes)
+	return
 }
 
 // GetResources returns the resources supported by the provider.
 func (p *Provider) GetResources(ctx context.Context) (result map[string]tfsdk.ResourceType,
 	diags diag.Diagnostics) {
-	result = map[string]tfsdk.ResourceType{
-		"ocm_cluster":                &ClusterResourceType{},
-		"ocm_cluster_rosa_classic":   &ClusterRosaClassicResourceType{p.logger},
+	result = withResourceLogging(map[string]tfsdk.ResourceType{
+		"ocm_cluster": &ClusterResourceType{
+			defaultProperties: p.defaultProperties,
+		},
+		"ocm_cluster_addon":   &ClusterAddOnResourceType{},
+		"ocm_cluster_ingress": &ClusterIngressResourceType{p.logger},
+		"ocm_cluster_rosa_classic": &ClusterRosaClassicResourceType{
+			logger:            p.logger,
+			defaultTags:       p.defaultTags,
+			defaultProperties: p.defaultProperties,
+		},
+		"ocm_cluster_rosa_hcp": &ClusterRosaHcpResourceType{
+			logger:            p.logger,
+			defaultTags:       p.defaultTags,
+			defaultProperties: p.defaultProperties,
+		},
+		"ocm_cluster_upgrade_policy": &ClusterUpgradePolicyResourceType{p.logger},
 		"ocm_group_membership":       &GroupMembershipResourceType{},
+		"ocm_htpasswd_user":          &HTPasswdUserResourceType{},
 		"ocm_identity_provider":      &IdentityProviderResourceType{},
 		"ocm_machine_pool":           &MachinePoolResourceType{p.logger},
+		"ocm_node_pool":              &NodePoolResourceType{p.logger},
 		"ocm_cluster_wait":           &ClusterWaiterResourceType{},
 		"ocm_rosa_oidc_config_input": &RosaOidcConfigInputResourceType{},
 		"ocm_rosa_oidc_config":       &RosaOidcConfigResourceType{},
-	}
+	})
 	return
 }
 
 // GetDataSources returns the data sources supported by the provider.
 func (p *Provider) GetDataSources(ctx context.Context) (result map[string]tfsdk.DataSourceType,
 	diags diag.Diagnostics) {
-	result = map[string]tfsdk.DataSourceType{
+	result = withDataSourceLogging(map[string]tfsdk.DataSourceType{
+		"ocm_addons":              &AddOnsDataSourceType{},
 		"ocm_cloud_providers":     &CloudProvidersDataSourceType{},
 		"ocm_rosa_operator_roles": &RosaOperatorRolesDataSourceType{},
 		"ocm_policies":            &OcmPoliciesDataSourceType{},
 		"ocm_groups":              &GroupsDataSourceType{},
 		"ocm_machine_types":       &MachineTypesDataSourceType{},
 		"ocm_versions":            &VersionsDataSourceType{},
-	}
+	})
 	return
 }
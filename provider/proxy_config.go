@@ -0,0 +1,136 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"golang.org/x/net/http/httpproxy"
+)
+
+// ProxyConfig contains the proxy used for the connections opened by the provider.
+type ProxyConfig struct {
+	URL      types.String `tfsdk:"url"`
+	NoProxy  types.String `tfsdk:"no_proxy"`
+	CABundle types.String `tfsdk:"ca_bundle"`
+}
+
+// OutboundProxy sends the connections opened by the provider through a proxy. It is used for the
+// connection to the API and the token endpoint, as a transport wrapper of the SDK, and for the
+// rest of connections, like the ones to AWS and to the OIDC issuers, with the HTTP client that it
+// creates.
+type OutboundProxy struct {
+	proxyFunc func(*url.URL) (*url.URL, error)
+	caBundle  string
+}
+
+// newOutboundProxy creates the outbound proxy from the 'proxy' block of the provider
+// configuration. It returns an error message if the configuration isn't valid.
+func newOutboundProxy(config *ProxyConfig) (result *OutboundProxy, errMsg string) {
+	if config.URL.Unknown || config.URL.Null || config.URL.Value == "" {
+		errMsg = "Attribute 'url' of the proxy is required"
+		return
+	}
+	proxyURL, err := url.Parse(config.URL.Value)
+	if err != nil || proxyURL.Host == "" {
+		errMsg = fmt.Sprintf("Expected a valid URL for the proxy, got '%s'", config.URL.Value)
+		return
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5":
+	default:
+		errMsg = fmt.Sprintf(
+			"Expected scheme 'http', 'https' or 'socks5' in the URL of the proxy, got '%s'",
+			proxyURL.Scheme,
+		)
+		return
+	}
+	caBundle := ""
+	if !config.CABundle.Unknown && !config.CABundle.Null {
+		caBundle = config.CABundle.Value
+		if !x509.NewCertPool().AppendCertsFromPEM([]byte(caBundle)) {
+			errMsg = "The value of the 'ca_bundle' attribute of the proxy doesn't contain any certificate"
+			return
+		}
+	}
+	proxyConfig := &httpproxy.Config{
+		HTTPProxy:  config.URL.Value,
+		HTTPSProxy: config.URL.Value,
+	}
+	if !config.NoProxy.Unknown && !config.NoProxy.Null {
+		proxyConfig.NoProxy = config.NoProxy.Value
+	}
+	result = &OutboundProxy{
+		proxyFunc: proxyConfig.ProxyFunc(),
+		caBundle:  caBundle,
+	}
+	return
+}
+
+// Proxy returns the URL of the proxy that should be used for the given request, or nil if the
+// request should be sent directly. It has the signature of the 'Proxy' field of the HTTP
+// transport.
+func (p *OutboundProxy) Proxy(request *http.Request) (*url.URL, error) {
+	return p.proxyFunc(request.URL)
+}
+
+// AppendCAs adds the certificates of the CA bundle of the proxy, if any, to the given pool.
+func (p *OutboundProxy) AppendCAs(pool *x509.CertPool) {
+	if p.caBundle != "" {
+		pool.AppendCertsFromPEM([]byte(p.caBundle))
+	}
+}
+
+// Wrap is a transport wrapper for the SDK that sends the requests through the proxy. The SDK
+// calls it with the transport that it creates for each server, so it needs to be the last
+// transport wrapper of the connection.
+func (p *OutboundProxy) Wrap(transport http.RoundTripper) http.RoundTripper {
+	httpTransport, ok := transport.(*http.Transport)
+	if !ok {
+		// Other transports, like the ones used for h2c, don't support proxies:
+		return transport
+	}
+	result := httpTransport.Clone()
+	result.Proxy = p.Proxy
+	return result
+}
+
+// Client creates the HTTP client used for the connections that aren't opened by the SDK. It
+// trusts the certificate authorities of the system and the ones of the CA bundle of the proxy.
+func (p *OutboundProxy) Client() *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = p.Proxy
+	if p.caBundle != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		p.AppendCAs(pool)
+		// #nosec G402
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs: pool,
+		}
+	}
+	return &http.Client{
+		Transport: transport,
+	}
+}
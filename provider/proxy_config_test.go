@@ -0,0 +1,107 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+)
+
+var _ = Describe("Outbound proxy", func() {
+	var proxyServer *httptest.Server
+	var proxied []string
+
+	BeforeEach(func() {
+		proxied = nil
+		proxyServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Requests sent to a proxy contain the complete URL of the destination:
+			proxied = append(proxied, r.URL.String())
+			w.WriteHeader(http.StatusOK)
+		}))
+	})
+
+	AfterEach(func() {
+		proxyServer.Close()
+	})
+
+	newProxy := func(noProxy string) *OutboundProxy {
+		config := &ProxyConfig{
+			URL:      types.String{Value: proxyServer.URL},
+			NoProxy:  types.String{Null: true},
+			CABundle: types.String{Null: true},
+		}
+		if noProxy != "" {
+			config.NoProxy = types.String{Value: noProxy}
+		}
+		proxy, errMsg := newOutboundProxy(config)
+		Expect(errMsg).To(BeEmpty())
+		return proxy
+	}
+
+	It("Sends the requests of the client through the proxy", func() {
+		response, err := newProxy("").Client().Get("http://api.example.com/api")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+		Expect(proxied).To(Equal([]string{"http://api.example.com/api"}))
+	})
+
+	It("Sends the requests of the wrapped transport through the proxy", func() {
+		client := &http.Client{
+			Transport: newProxy("").Wrap(http.DefaultTransport.(*http.Transport).Clone()),
+		}
+		response, err := client.Get("http://sso.example.com/token")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+		Expect(proxied).To(Equal([]string{"http://sso.example.com/token"}))
+	})
+
+	It("Doesn't use the proxy for the excluded hosts", func() {
+		proxy := newProxy("internal.example.com,.corp.example.com")
+		request, err := http.NewRequest(http.MethodGet, "https://api.internal.example.com", nil)
+		Expect(err).ToNot(HaveOccurred())
+		proxyURL, err := proxy.Proxy(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(proxyURL).To(BeNil())
+
+		request, err = http.NewRequest(http.MethodGet, "https://sts.amazonaws.com", nil)
+		Expect(err).ToNot(HaveOccurred())
+		proxyURL, err = proxy.Proxy(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(proxyURL).ToNot(BeNil())
+		Expect(proxyURL.String()).To(Equal(proxyServer.URL))
+	})
+
+	It("Rejects invalid configurations", func() {
+		_, errMsg := newOutboundProxy(&ProxyConfig{
+			URL:      types.String{Value: "ftp://proxy.example.com"},
+			NoProxy:  types.String{Null: true},
+			CABundle: types.String{Null: true},
+		})
+		Expect(errMsg).To(ContainSubstring("scheme"))
+
+		_, errMsg = newOutboundProxy(&ProxyConfig{
+			URL:      types.String{Value: "http://proxy.example.com:3128"},
+			NoProxy:  types.String{Null: true},
+			CABundle: types.String{Value: "not a certificate"},
+		})
+		Expect(errMsg).To(ContainSubstring("ca_bundle"))
+	})
+})
@@ -0,0 +1,172 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+)
+
+// loggingResourceType wraps a resource type so that all the methods of its resources add the
+// name of the type to the log messages, and the last operation identifier to the errors. That
+// way the resources themselves don't need to do it.
+type loggingResourceType struct {
+	tfsdk.ResourceType
+	name string
+}
+
+// loggingResource is the resource created by the loggingResourceType.
+type loggingResource struct {
+	resource tfsdk.Resource
+	name     string
+}
+
+// loggingResourceWithModifyPlan is the resource created by the loggingResourceType when the
+// wrapped resource also modifies plans.
+type loggingResourceWithModifyPlan struct {
+	loggingResource
+}
+
+// Make sure that we implement the interfaces:
+var _ tfsdk.ResourceType = &loggingResourceType{}
+var _ tfsdk.Resource = &loggingResource{}
+var _ tfsdk.ResourceWithModifyPlan = &loggingResourceWithModifyPlan{}
+
+// withResourceLogging wraps the given resource types, using as name of each type the key of the
+// map.
+func withResourceLogging(types map[string]tfsdk.ResourceType) map[string]tfsdk.ResourceType {
+	result := make(map[string]tfsdk.ResourceType, len(types))
+	for name, resourceType := range types {
+		result[name] = &loggingResourceType{
+			ResourceType: resourceType,
+			name:         name,
+		}
+	}
+	return result
+}
+
+func (t *loggingResourceType) NewResource(ctx context.Context,
+	p tfsdk.Provider) (result tfsdk.Resource, diags diag.Diagnostics) {
+	resource, diags := t.ResourceType.NewResource(ctx, p)
+	if diags.HasError() {
+		return
+	}
+	wrapper := loggingResource{
+		resource: resource,
+		name:     t.name,
+	}
+	if _, ok := resource.(tfsdk.ResourceWithModifyPlan); ok {
+		result = &loggingResourceWithModifyPlan{wrapper}
+	} else {
+		result = &wrapper
+	}
+	return
+}
+
+func (r *loggingResource) Create(ctx context.Context, request tfsdk.CreateResourceRequest,
+	response *tfsdk.CreateResourceResponse) {
+	ctx = withResourceLogField(ctx, r.name)
+	defer addOperationID(ctx, &response.Diagnostics)
+	r.resource.Create(ctx, request, response)
+}
+
+func (r *loggingResource) Read(ctx context.Context, request tfsdk.ReadResourceRequest,
+	response *tfsdk.ReadResourceResponse) {
+	ctx = withResourceLogField(ctx, r.name)
+	defer addOperationID(ctx, &response.Diagnostics)
+	r.resource.Read(ctx, request, response)
+}
+
+func (r *loggingResource) Update(ctx context.Context, request tfsdk.UpdateResourceRequest,
+	response *tfsdk.UpdateResourceResponse) {
+	ctx = withResourceLogField(ctx, r.name)
+	defer addOperationID(ctx, &response.Diagnostics)
+	r.resource.Update(ctx, request, response)
+}
+
+func (r *loggingResource) Delete(ctx context.Context, request tfsdk.DeleteResourceRequest,
+	response *tfsdk.DeleteResourceResponse) {
+	ctx = withResourceLogField(ctx, r.name)
+	defer addOperationID(ctx, &response.Diagnostics)
+	r.resource.Delete(ctx, request, response)
+}
+
+func (r *loggingResource) ImportState(ctx context.Context, request tfsdk.ImportResourceStateRequest,
+	response *tfsdk.ImportResourceStateResponse) {
+	ctx = withResourceLogField(ctx, r.name)
+	defer addOperationID(ctx, &response.Diagnostics)
+	r.resource.ImportState(ctx, request, response)
+}
+
+func (r *loggingResourceWithModifyPlan) ModifyPlan(ctx context.Context,
+	request tfsdk.ModifyResourcePlanRequest, response *tfsdk.ModifyResourcePlanResponse) {
+	ctx = withResourceLogField(ctx, r.name)
+	defer addOperationID(ctx, &response.Diagnostics)
+	r.resource.(tfsdk.ResourceWithModifyPlan).ModifyPlan(ctx, request, response)
+}
+
+// loggingDataSourceType wraps a data source type so that reading its data sources adds the name
+// of the type to the log messages, and the last operation identifier to the errors.
+type loggingDataSourceType struct {
+	tfsdk.DataSourceType
+	name string
+}
+
+// loggingDataSource is the data source created by the loggingDataSourceType.
+type loggingDataSource struct {
+	dataSource tfsdk.DataSource
+	name       string
+}
+
+// Make sure that we implement the interfaces:
+var _ tfsdk.DataSourceType = &loggingDataSourceType{}
+var _ tfsdk.DataSource = &loggingDataSource{}
+
+// withDataSourceLogging wraps the given data source types, using as name of each type the key of
+// the map.
+func withDataSourceLogging(types map[string]tfsdk.DataSourceType) map[string]tfsdk.DataSourceType {
+	result := make(map[string]tfsdk.DataSourceType, len(types))
+	for name, dataSourceType := range types {
+		result[name] = &loggingDataSourceType{
+			DataSourceType: dataSourceType,
+			name:           name,
+		}
+	}
+	return result
+}
+
+func (t *loggingDataSourceType) NewDataSource(ctx context.Context,
+	p tfsdk.Provider) (result tfsdk.DataSource, diags diag.Diagnostics) {
+	dataSource, diags := t.DataSourceType.NewDataSource(ctx, p)
+	if diags.HasError() {
+		return
+	}
+	result = &loggingDataSource{
+		dataSource: dataSource,
+		name:       t.name,
+	}
+	return
+}
+
+func (s *loggingDataSource) Read(ctx context.Context, request tfsdk.ReadDataSourceRequest,
+	response *tfsdk.ReadDataSourceResponse) {
+	ctx = withResourceLogField(ctx, s.name)
+	defer addOperationID(ctx, &response.Diagnostics)
+	s.dataSource.Read(ctx, request, response)
+}
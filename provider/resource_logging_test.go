@@ -0,0 +1,121 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+)
+
+// fakeResourceType creates fake resources that record the operation identifier 'my-operation'
+// and fail, so that the tests can check what the logging wrapper adds.
+type fakeResourceType struct {
+	tfsdk.ResourceType
+	modifyPlan bool
+	contexts   []context.Context
+}
+
+type fakeResource struct {
+	tfsdk.Resource
+	resourceType *fakeResourceType
+}
+
+type fakeResourceWithModifyPlan struct {
+	fakeResource
+}
+
+func (t *fakeResourceType) NewResource(ctx context.Context,
+	p tfsdk.Provider) (tfsdk.Resource, diag.Diagnostics) {
+	resource := fakeResource{
+		resourceType: t,
+	}
+	if t.modifyPlan {
+		return &fakeResourceWithModifyPlan{resource}, nil
+	}
+	return &resource, nil
+}
+
+func (r *fakeResource) Read(ctx context.Context, request tfsdk.ReadResourceRequest,
+	response *tfsdk.ReadResourceResponse) {
+	r.resourceType.contexts = append(r.resourceType.contexts, ctx)
+	recordOperationID(ctx, "my-operation")
+	response.Diagnostics.AddError("Can't read object", "Something went wrong")
+}
+
+func (r *fakeResourceWithModifyPlan) ModifyPlan(ctx context.Context,
+	request tfsdk.ModifyResourcePlanRequest, response *tfsdk.ModifyResourcePlanResponse) {
+	r.resourceType.contexts = append(r.resourceType.contexts, ctx)
+}
+
+var _ = Describe("Resource logging", func() {
+	It("Adds the resource type and the operation identifier", func() {
+		fake := &fakeResourceType{}
+		types := withResourceLogging(map[string]tfsdk.ResourceType{
+			"ocm_fake": fake,
+		})
+		resource, diags := types["ocm_fake"].NewResource(context.Background(), nil)
+		Expect(diags.HasError()).To(BeFalse())
+		_, ok := resource.(tfsdk.ResourceWithModifyPlan)
+		Expect(ok).To(BeFalse())
+
+		response := &tfsdk.ReadResourceResponse{}
+		resource.Read(context.Background(), tfsdk.ReadResourceRequest{}, response)
+		Expect(fake.contexts).To(HaveLen(1))
+		Expect(logFields(fake.contexts[0])).To(Equal([]interface{}{
+			logFieldResourceType, "ocm_fake",
+		}))
+		Expect(response.Diagnostics).To(HaveLen(1))
+		Expect(response.Diagnostics[0].Detail()).To(ContainSubstring("my-operation"))
+	})
+
+	It("Keeps the plan modification of the resource", func() {
+		fake := &fakeResourceType{
+			modifyPlan: true,
+		}
+		types := withResourceLogging(map[string]tfsdk.ResourceType{
+			"ocm_fake": fake,
+		})
+		resource, diags := types["ocm_fake"].NewResource(context.Background(), nil)
+		Expect(diags.HasError()).To(BeFalse())
+		modifier, ok := resource.(tfsdk.ResourceWithModifyPlan)
+		Expect(ok).To(BeTrue())
+
+		modifier.ModifyPlan(
+			context.Background(),
+			tfsdk.ModifyResourcePlanRequest{},
+			&tfsdk.ModifyResourcePlanResponse{},
+		)
+		Expect(fake.contexts).To(HaveLen(1))
+		Expect(logFields(fake.contexts[0])).To(Equal([]interface{}{
+			logFieldResourceType, "ocm_fake",
+		}))
+	})
+
+	It("Adds the identifier of the object", func() {
+		ctx := withClusterLogField(context.Background(), "123")
+		ctx = withObjectLogField(ctx, "456")
+		ctx = withObjectLogField(ctx, "")
+		Expect(logFields(ctx)).To(Equal([]interface{}{
+			logFieldClusterID, "123",
+			logFieldObjectID, "456",
+		}))
+	})
+})
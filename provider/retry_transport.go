@@ -0,0 +1,240 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/openshift-online/ocm-sdk-go/logging"
+)
+
+// Default values of the settings of the 'retry' block of the provider:
+const (
+	defaultRetryMaxAttempts = 5
+	defaultRetryBaseBackoff = 1 * time.Second
+	defaultRetryMaxBackoff  = 30 * time.Second
+)
+
+var defaultRetryStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryTransportWrapper contains the retry policy applied to the requests sent to the OCM API.
+type RetryTransportWrapper struct {
+	logger      logging.Logger
+	maxAttempts int
+	baseBackoff time.Duration
+	maxBackoff  time.Duration
+	statusCodes map[int]bool
+}
+
+// retryTransport is the round tripper that implements the retry policy.
+type retryTransport struct {
+	wrapper   *RetryTransportWrapper
+	transport http.RoundTripper
+}
+
+// NewRetryTransportWrapper creates a wrapper that retries requests that fail with a connection
+// error or with one of the given status codes, waiting an exponentially growing time between
+// attempts. Requests that aren't idempotent are only retried when it is safe to do so, see the
+// isIdempotentMethod function for details.
+func NewRetryTransportWrapper(logger logging.Logger, maxAttempts int, baseBackoff,
+	maxBackoff time.Duration, statusCodes []int) *RetryTransportWrapper {
+	wrapper := &RetryTransportWrapper{
+		logger:      logger,
+		maxAttempts: maxAttempts,
+		baseBackoff: baseBackoff,
+		maxBackoff:  maxBackoff,
+		statusCodes: map[int]bool{},
+	}
+	for _, code := range statusCodes {
+		wrapper.statusCodes[code] = true
+	}
+	return wrapper
+}
+
+// Wrap creates a new round tripper that wraps the given one and implements the retry policy.
+func (w *RetryTransportWrapper) Wrap(transport http.RoundTripper) http.RoundTripper {
+	return &retryTransport{
+		wrapper:   w,
+		transport: transport,
+	}
+}
+
+// RoundTrip is the implementation of the round tripper interface.
+func (t *retryTransport) RoundTrip(request *http.Request) (response *http.Response, err error) {
+	ctx := request.Context()
+
+	// The request of the caller can't be modified, so each attempt sends a clone with its own
+	// copy of the body. When the request doesn't know how to create copies of the body, it is
+	// read to memory. The original body is never sent, but it still needs to be closed:
+	getBody := request.GetBody
+	if request.Body != nil && request.Body != http.NoBody {
+		if getBody == nil {
+			var body []byte
+			body, err = io.ReadAll(request.Body)
+			if err != nil {
+				request.Body.Close()
+				return
+			}
+			getBody = func() (io.ReadCloser, error) {
+				return io.NopCloser(bytes.NewReader(body)), nil
+			}
+		}
+		request.Body.Close()
+	}
+
+	for attempt := 1; ; attempt++ {
+		attemptRequest := request.Clone(ctx)
+		if getBody != nil {
+			attemptRequest.Body, err = getBody()
+			if err != nil {
+				return
+			}
+		}
+		response, err = t.transport.RoundTrip(attemptRequest)
+		if attempt >= t.wrapper.maxAttempts || ctx.Err() != nil {
+			return
+		}
+
+		// Decide if the request should be retried and how long to wait before that:
+		var delay time.Duration
+		idempotent := isIdempotentMethod(request.Method)
+		switch {
+		case err != nil:
+			if !isRetryableError(err, idempotent) {
+				return
+			}
+			delay = t.wrapper.backoff(attempt)
+			t.wrapper.logger.Debug(
+				ctx,
+				"Request for method %s and URL '%s' failed with error '%v', "+
+					"will retry in %s (attempt %d of %d)",
+				request.Method, request.URL, err, delay, attempt, t.wrapper.maxAttempts,
+			)
+		case t.wrapper.statusCodes[response.StatusCode] &&
+			(idempotent || response.StatusCode == http.StatusTooManyRequests):
+			delay = retryAfter(response)
+			if delay <= 0 {
+				delay = t.wrapper.backoff(attempt)
+			}
+			if delay > t.wrapper.maxBackoff {
+				delay = t.wrapper.maxBackoff
+			}
+			t.wrapper.logger.Debug(
+				ctx,
+				"Request for method %s and URL '%s' failed with status code %d, "+
+					"will retry in %s (attempt %d of %d)",
+				request.Method, request.URL, response.StatusCode, delay, attempt,
+				t.wrapper.maxAttempts,
+			)
+			_, _ = io.Copy(io.Discard, response.Body)
+			response.Body.Close()
+		default:
+			return
+		}
+
+		// Wait before the next attempt, unless the request is cancelled:
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// backoff returns the time to wait after the given failed attempt. It doubles with each attempt,
+// starting with the base backoff and never exceeding the max backoff.
+func (w *RetryTransportWrapper) backoff(attempt int) time.Duration {
+	delay := w.baseBackoff
+	for i := 1; i < attempt && delay < w.maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > w.maxBackoff {
+		delay = w.maxBackoff
+	}
+	return delay
+}
+
+// retryAfter returns the time to wait requested by the server with the 'Retry-After' header,
+// or zero if the header isn't present, isn't valid or contains a date that already passed. The
+// caller is responsible for limiting it to the max backoff.
+func retryAfter(response *http.Response) time.Duration {
+	value := response.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	seconds, err := strconv.Atoi(value)
+	if err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	date, err := http.ParseTime(value)
+	if err == nil && time.Until(date) > 0 {
+		return time.Until(date)
+	}
+	return 0
+}
+
+// isIdempotentMethod checks if sending a request with the given method more than once has the
+// same effect as sending it once. Requests with other methods, like the POST used to create
+// clusters, are only retried when the server explicitly rejected them with a 429 status code, or
+// when they weren't sent at all. PATCH isn't considered idempotent, as the server may apply side
+// effects, like scheduling an upgrade, each time it receives the request.
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// isRetryableError checks if the error returned by the transport is a connection error that
+// is worth retrying. Errors that happen after the connection was established are only retried
+// for idempotent requests, as the server may have received and processed the request.
+func isRetryableError(err error, idempotent bool) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		// The connection wasn't established, so the request wasn't sent:
+		return true
+	}
+	if !idempotent {
+		return false
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	message := err.Error()
+	return strings.Contains(message, "connection reset by peer") ||
+		strings.Contains(message, "EOF")
+}
@@ -0,0 +1,226 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"syscall"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2/dsl/core" // nolint
+	. "github.com/onsi/gomega"             // nolint
+	"github.com/openshift-online/ocm-sdk-go/logging"
+)
+
+var _ = Describe("Retry transport", func() {
+	var logger logging.Logger
+
+	BeforeEach(func() {
+		var err error
+		logger, err = logging.NewGoLoggerBuilder().Build()
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	// newClientWithMaxBackoff creates an HTTP client that uses the retry transport with a short
+	// base backoff and the given max backoff:
+	newClientWithMaxBackoff := func(maxAttempts int, maxBackoff time.Duration) *http.Client {
+		wrapper := NewRetryTransportWrapper(logger, maxAttempts, time.Millisecond,
+			maxBackoff, defaultRetryStatusCodes)
+		return &http.Client{
+			Transport: wrapper.Wrap(http.DefaultTransport),
+		}
+	}
+
+	// newClient creates an HTTP client that uses the retry transport with a short backoff:
+	newClient := func(maxAttempts int) *http.Client {
+		return newClientWithMaxBackoff(maxAttempts, 10*time.Millisecond)
+	}
+
+	It("Retries retryable status codes and sends the body again", func() {
+		attempts := 0
+		bodies := []string{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			body, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			if attempts < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		request, err := http.NewRequest(http.MethodPut, server.URL, strings.NewReader("{}"))
+		Expect(err).ToNot(HaveOccurred())
+		response, err := newClient(5).Do(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+		Expect(attempts).To(Equal(3))
+		Expect(bodies).To(Equal([]string{"{}", "{}", "{}"}))
+	})
+
+	It("Doesn't retry server errors of requests that aren't idempotent", func() {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		response, err := newClient(5).Post(server.URL, "application/json", strings.NewReader("{}"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.StatusCode).To(Equal(http.StatusServiceUnavailable))
+		Expect(attempts).To(Equal(1))
+	})
+
+	It("Retries requests that aren't idempotent when rate limited", func() {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts < 2 {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+		}))
+		defer server.Close()
+
+		response, err := newClient(5).Post(server.URL, "application/json", strings.NewReader("{}"))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.StatusCode).To(Equal(http.StatusCreated))
+		Expect(attempts).To(Equal(2))
+	})
+
+	It("Stops after the maximum number of attempts", func() {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer server.Close()
+
+		response, err := newClient(2).Get(server.URL)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.StatusCode).To(Equal(http.StatusTooManyRequests))
+		Expect(attempts).To(Equal(2))
+	})
+
+	It("Doesn't retry other status codes", func() {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer server.Close()
+
+		response, err := newClient(5).Get(server.URL)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.StatusCode).To(Equal(http.StatusBadRequest))
+		Expect(attempts).To(Equal(1))
+	})
+
+	It("Respects the 'Retry-After' header", func() {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts == 1 {
+				w.Header().Set("Retry-After", "1")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		start := time.Now()
+		response, err := newClientWithMaxBackoff(5, 5*time.Second).Get(server.URL)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+		Expect(time.Since(start)).To(BeNumerically(">=", time.Second))
+	})
+
+	It("Doesn't wait more than the max backoff", func() {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts == 1 {
+				w.Header().Set("Retry-After", "3600")
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		start := time.Now()
+		response, err := newClient(5).Get(server.URL)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+		Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+	})
+
+	It("Ignores 'Retry-After' dates that already passed", func() {
+		response := &http.Response{
+			Header: http.Header{
+				"Retry-After": []string{time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)},
+			},
+		}
+		Expect(retryAfter(response)).To(BeZero())
+	})
+
+	It("Doesn't modify the request of the caller", func() {
+		attempts := 0
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			attempts++
+			if attempts < 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		request, err := http.NewRequest(http.MethodPut, server.URL, strings.NewReader("{}"))
+		Expect(err).ToNot(HaveOccurred())
+		body := request.Body
+		response, err := newClient(5).Do(request)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+		Expect(attempts).To(Equal(2))
+		Expect(request.Body).To(BeIdenticalTo(body))
+	})
+
+	It("Retries connection errors only when it is safe", func() {
+		Expect(isRetryableError(io.ErrUnexpectedEOF, true)).To(BeTrue())
+		Expect(isRetryableError(io.ErrUnexpectedEOF, false)).To(BeFalse())
+		Expect(isRetryableError(syscall.ECONNREFUSED, false)).To(BeTrue())
+		Expect(isRetryableError(context.Canceled, true)).To(BeFalse())
+	})
+
+	It("Doubles the backoff without exceeding the maximum", func() {
+		wrapper := NewRetryTransportWrapper(logger, 10, time.Second, 5*time.Second, nil)
+		Expect(wrapper.backoff(1)).To(Equal(time.Second))
+		Expect(wrapper.backoff(2)).To(Equal(2 * time.Second))
+		Expect(wrapper.backoff(3)).To(Equal(4 * time.Second))
+		Expect(wrapper.backoff(4)).To(Equal(5 * time.Second))
+	})
+})
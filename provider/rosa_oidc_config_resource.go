@@ -25,6 +25,8 @@ import (
 	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	cmv1 "github.com/openshift-online/ocm-sdk-go/clustersmgmt/v1"
 	"github.com/openshift-online/ocm-sdk-go/logging"
+	"github.com/terraform-redhat/terraform-provider-ocm/provider/common"
+	"net/http"
 	"strings"
 )
 
@@ -36,6 +38,7 @@ type RosaOidcConfigResource struct {
 	logger           logging.Logger
 	oidcConfigClient *cmv1.OidcConfigsClient
 	clustersClient   *cmv1.ClustersClient
+	httpClient       *http.Client
 }
 
 func (t *RosaOidcConfigResourceType) GetSchema(ctx context.Context) (result tfsdk.Schema,
@@ -99,6 +102,7 @@ func (t *RosaOidcConfigResourceType) NewResource(ctx context.Context,
 		logger:           parent.logger,
 		oidcConfigClient: oidcConfigClient,
 		clustersClient:   clustersClient,
+		httpClient:       parent.httpClient,
 	}
 
 	return
@@ -203,6 +207,12 @@ func (r *RosaOidcConfigResource) Read(ctx context.Context, request tfsdk.ReadRes
 	// Find the oidc config:
 	get, err := r.oidcConfigClient.OidcConfig(state.ID.Value).Get().SendContext(ctx)
 	if err != nil {
+		if common.IsStatusNotFound(err) {
+			r.logger.Warn(withObjectLogField(ctx, state.ID.Value),
+				"OIDC config wasn't found, removing it from the state")
+			response.State.RemoveResource(ctx)
+			return
+		}
 		response.Diagnostics.AddError(
 			"Can't find OIDC config",
 			fmt.Sprintf(
@@ -367,9 +377,9 @@ func (r *RosaOidcConfigResource) populateState(ctx context.Context, object *cmv1
 		Value: oidcEndpointURL,
 	}
 
-	thumbprint, err := getThumbprint(issuerUrl, DefaultHttpClient{})
+	thumbprint, err := getThumbprint(issuerUrl, DefaultHttpClient{r.httpClient})
 	if err != nil {
-		r.logger.Error(ctx, "cannot get thumbprint", err)
+		r.logger.Error(withLogField(ctx, logFieldError, err.Error()), "Can't get thumbprint")
 		state.Thumbprint = types.String{
 			Value: "",
 		}
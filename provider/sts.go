@@ -3,6 +3,7 @@ package provider
 import (
 	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
 	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/openshift-online/ocm-sdk-go/logging"
 )
 
 func stsResource() tfsdk.NestedAttributes {
@@ -57,3 +58,84 @@ func stsResource() tfsdk.NestedAttributes {
 	})
 
 }
+
+// hcpStsResource returns the schema of the STS configuration of hosted control plane clusters.
+// The configurable attributes can't be changed once the cluster is created, and the computed ones
+// keep the value saved in the state, so that updates of other attributes of the cluster don't
+// result in unknown values.
+func hcpStsResource(logger logging.Logger) tfsdk.NestedAttributes {
+	return tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+		"oidc_endpoint_url": {
+			Description: "OIDC Endpoint URL",
+			Type:        types.StringType,
+			Computed:    true,
+			PlanModifiers: []tfsdk.AttributePlanModifier{
+				tfsdk.UseStateForUnknown(),
+			},
+		},
+		"oidc_config_id": {
+			Description: "Identifier of a managed OIDC Configuration",
+			Type:        types.StringType,
+			Required:    true,
+			PlanModifiers: []tfsdk.AttributePlanModifier{
+				ValueCannotBeChangedModifier(logger),
+			},
+		},
+		"thumbprint": {
+			Description: "SHA1-hash value of the root CA of the issuer URL",
+			Type:        types.StringType,
+			Computed:    true,
+			PlanModifiers: []tfsdk.AttributePlanModifier{
+				tfsdk.UseStateForUnknown(),
+			},
+		},
+		"role_arn": {
+			Description: "Installer Role",
+			Type:        types.StringType,
+			Required:    true,
+			PlanModifiers: []tfsdk.AttributePlanModifier{
+				ValueCannotBeChangedModifier(logger),
+			},
+		},
+		"support_role_arn": {
+			Description: "Support Role",
+			Type:        types.StringType,
+			Required:    true,
+			PlanModifiers: []tfsdk.AttributePlanModifier{
+				ValueCannotBeChangedModifier(logger),
+			},
+		},
+		"instance_iam_roles": {
+			Description: "Instance IAM Roles",
+			Attributes: tfsdk.SingleNestedAttributes(map[string]tfsdk.Attribute{
+				"worker_role_arn": {
+					Description: "Worker Node Role ARN",
+					Type:        types.StringType,
+					Required:    true,
+					PlanModifiers: []tfsdk.AttributePlanModifier{
+						ValueCannotBeChangedModifier(logger),
+					},
+				},
+			}),
+			Required: true,
+		},
+		"operator_role_prefix": {
+			Description: "Operator IAM Role prefix",
+			Type:        types.StringType,
+			Required:    true,
+			PlanModifiers: []tfsdk.AttributePlanModifier{
+				ValueCannotBeChangedModifier(logger),
+			},
+		},
+		"operator_iam_roles": {
+			Description: "ARNs of the hosted control plane operator IAM roles",
+			Type: types.ListType{
+				ElemType: types.StringType,
+			},
+			Computed: true,
+			PlanModifiers: []tfsdk.AttributePlanModifier{
+				tfsdk.UseStateForUnknown(),
+			},
+		},
+	})
+}
@@ -0,0 +1,215 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/openshift-online/ocm-sdk-go/logging"
+)
+
+// Names of the tflog subsystems used by the provider. The level of each of them can be changed
+// with the 'TF_LOG_PROVIDER_OCM_<SUBSYSTEM>' environment variables, for example
+// 'TF_LOG_PROVIDER_OCM_HTTP=DEBUG'.
+const (
+	// logSubsystemResources is used by the resources and data sources.
+	logSubsystemResources = "resources"
+
+	// logSubsystemSDK is used by the OCM SDK.
+	logSubsystemSDK = "sdk"
+
+	// logSubsystemHTTP is used for the requests sent to the API.
+	logSubsystemHTTP = "http"
+)
+
+// Names of the fields added to the log messages:
+const (
+	logFieldClusterID    = "ocm_cluster_id"
+	logFieldError        = "error"
+	logFieldObjectID     = "ocm_object_id"
+	logFieldOperationID  = "ocm_operation_id"
+	logFieldResourceType = "ocm_resource_type"
+	logFieldState        = "ocm_state"
+	logFieldVersion      = "ocm_version"
+)
+
+// logFieldsKey is the key used to store in the context the fields added to the log messages.
+type logFieldsKey struct{}
+
+// withLogField returns a copy of the context that adds the given field to all the messages
+// logged with it by the TFLogger.
+func withLogField(ctx context.Context, key string, value interface{}) context.Context {
+	fields := map[string]interface{}{}
+	if current, ok := ctx.Value(logFieldsKey{}).(map[string]interface{}); ok {
+		for k, v := range current {
+			fields[k] = v
+		}
+	}
+	fields[key] = value
+	return context.WithValue(ctx, logFieldsKey{}, fields)
+}
+
+// withClusterLogField returns a copy of the context that adds the identifier of the given cluster
+// to the log messages. Empty identifiers, for clusters that haven't been created yet, are ignored.
+func withClusterLogField(ctx context.Context, clusterID string) context.Context {
+	if clusterID == "" {
+		return ctx
+	}
+	return withLogField(ctx, logFieldClusterID, clusterID)
+}
+
+// withObjectLogField returns a copy of the context that adds the identifier of the object managed
+// by a resource, for example a machine pool, to the log messages. Empty identifiers, for objects
+// that haven't been created yet, are ignored.
+func withObjectLogField(ctx context.Context, objectID string) context.Context {
+	if objectID == "" {
+		return ctx
+	}
+	return withLogField(ctx, logFieldObjectID, objectID)
+}
+
+// withResourceLogField returns a copy of the context that adds the type of the resource or data
+// source, for example 'ocm_cluster', to the log messages. It also records the operation
+// identifiers of the requests sent with the context, so that the last one is added to the log
+// messages of the resources and to their diagnostics.
+func withResourceLogField(ctx context.Context, resourceType string) context.Context {
+	ctx = withOperationIDRecorder(ctx)
+	return withLogField(ctx, logFieldResourceType, resourceType)
+}
+
+// logFields returns the fields stored in the context, sorted by key, as a list of key value
+// pairs.
+func logFields(ctx context.Context) []interface{} {
+	fields, ok := ctx.Value(logFieldsKey{}).(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	result := make([]interface{}, 0, 2*len(keys))
+	for _, key := range keys {
+		value := fields[key]
+		if text, ok := value.(string); ok {
+			value = redact(text)
+		}
+		result = append(result, key, value)
+	}
+	return result
+}
+
+// TFLogger is an implementation of the logger interface of the OCM SDK that sends the messages
+// to a tflog subsystem, so that they are collected by Terraform and filtered by the 'TF_LOG'
+// environment variables. Secrets that appear in the messages are redacted.
+type TFLogger struct {
+	subsystem    string
+	debugEnabled bool
+}
+
+// Make sure that we implement the logger interface of the SDK:
+var _ logging.Logger = &TFLogger{}
+
+// NewTFLogger creates a logger that sends the messages to the given subsystem.
+func NewTFLogger(subsystem string) *TFLogger {
+	return &TFLogger{
+		subsystem:    subsystem,
+		debugEnabled: true,
+	}
+}
+
+// newSDKLogger creates the logger for the OCM SDK. It reports the debug level as disabled because
+// the SDK uses it to decide if it dumps the complete HTTP traffic, and the provider logs the
+// requests itself in the 'http' subsystem.
+func newSDKLogger() *TFLogger {
+	return &TFLogger{
+		subsystem:    logSubsystemSDK,
+		debugEnabled: false,
+	}
+}
+
+func (l *TFLogger) DebugEnabled() bool {
+	return l.debugEnabled
+}
+
+func (l *TFLogger) InfoEnabled() bool {
+	return true
+}
+
+func (l *TFLogger) WarnEnabled() bool {
+	return true
+}
+
+func (l *TFLogger) ErrorEnabled() bool {
+	return true
+}
+
+func (l *TFLogger) Debug(ctx context.Context, format string, args ...interface{}) {
+	l.log(ctx, tflog.SubsystemDebug, format, args...)
+}
+
+func (l *TFLogger) Info(ctx context.Context, format string, args ...interface{}) {
+	l.log(ctx, tflog.SubsystemInfo, format, args...)
+}
+
+func (l *TFLogger) Warn(ctx context.Context, format string, args ...interface{}) {
+	l.log(ctx, tflog.SubsystemWarn, format, args...)
+}
+
+func (l *TFLogger) Error(ctx context.Context, format string, args ...interface{}) {
+	l.log(ctx, tflog.SubsystemError, format, args...)
+}
+
+func (l *TFLogger) Fatal(ctx context.Context, format string, args ...interface{}) {
+	l.log(ctx, tflog.SubsystemError, format, args...)
+	os.Exit(1)
+}
+
+// log sends the message to the subsystem with the given tflog function.
+func (l *TFLogger) log(ctx context.Context,
+	logFunc func(ctx context.Context, subsystem, msg string, args ...interface{}),
+	format string, args ...interface{}) {
+	if ctx == nil {
+		return
+	}
+
+	// The loggers are created by the plugin framework for each request, so contexts that don't
+	// come from a request, like the ones used by the SDK for background tasks, don't have them.
+	// In that case creating the subsystem doesn't change the context and the message is
+	// discarded, like tflog does for the messages sent to the root logger.
+	subsystemCtx := tflog.NewSubsystem(ctx, l.subsystem,
+		tflog.WithLevelFromEnv("TF_LOG_PROVIDER_OCM", l.subsystem))
+	if subsystemCtx == ctx {
+		return
+	}
+	if l.subsystem == logSubsystemResources {
+		operationID := lastOperationID(ctx)
+		if operationID != "" {
+			ctx = withLogField(ctx, logFieldOperationID, operationID)
+		}
+	}
+	msg := format
+	if len(args) > 0 {
+		msg = fmt.Sprintf(format, args...)
+	}
+	logFunc(subsystemCtx, l.subsystem, redact(msg), logFields(ctx)...)
+}
@@ -0,0 +1,62 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+type waitIfNotReadyModifier struct {
+}
+
+// WaitIfNotReadyModifier returns a plan modifier for the computed boolean attribute that indicates
+// if a waiter reached its target. When the refreshed state says that the target isn't reached,
+// for example because the cluster broke or was hibernated after the first apply, the attribute is
+// planned as unknown, so that the next apply updates the resource and waits again.
+func WaitIfNotReadyModifier() tfsdk.AttributePlanModifier {
+	return waitIfNotReadyModifier{}
+}
+
+func (m waitIfNotReadyModifier) Description(ctx context.Context) string {
+	return "The resource waits again when the target was no longer reached during the refresh."
+}
+
+func (m waitIfNotReadyModifier) MarkdownDescription(ctx context.Context) string {
+	return m.Description(ctx)
+}
+
+func (m waitIfNotReadyModifier) Modify(ctx context.Context, req tfsdk.ModifyAttributePlanRequest,
+	resp *tfsdk.ModifyAttributePlanResponse) {
+	if req.AttributeState == nil || req.AttributePlan == nil {
+		// shouldn't happen, but let's not panic if it does
+		return
+	}
+	if req.State.Raw.IsNull() || req.Plan.Raw.IsNull() {
+		// The resource is being created or deleted:
+		return
+	}
+	ready, ok := req.AttributeState.(types.Bool)
+	if !ok || ready.Unknown || ready.Null || ready.Value {
+		return
+	}
+	resp.AttributePlan = types.Bool{
+		Unknown: true,
+	}
+}
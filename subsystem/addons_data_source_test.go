@@ -0,0 +1,106 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2/dsl/core"             // nolint
+	. "github.com/onsi/gomega"                         // nolint
+	. "github.com/onsi/gomega/ghttp"                   // nolint
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("Add-ons data source", func() {
+	It("Can list add-ons", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/addons"),
+				RespondWithJSON(http.StatusOK, `{
+				  "page": 1,
+				  "size": 2,
+				  "total": 2,
+				  "items": [
+				    {
+				      "id": "my-addon",
+				      "name": "My add-on",
+				      "description": "My add-on description",
+				      "enabled": true,
+				      "install_mode": "own_namespace",
+				      "target_namespace": "my-namespace",
+				      "version": {
+				        "id": "1.0.0"
+				      },
+				      "parameters": {
+				        "items": [
+				          {
+				            "id": "size",
+				            "name": "Size",
+				            "value_type": "number",
+				            "required": true,
+				            "editable": true,
+				            "default_value": "1"
+				          },
+				          {
+				            "id": "tier",
+				            "name": "Tier",
+				            "value_type": "string",
+				            "options": [
+				              {
+				                "name": "Basic",
+				                "value": "basic"
+				              },
+				              {
+				                "name": "Premium",
+				                "value": "premium"
+				              }
+				            ]
+				          }
+				        ]
+				      }
+				    },
+				    {
+				      "id": "my-other-addon",
+				      "name": "My other add-on",
+				      "enabled": false
+				    }
+				  ]
+				}`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  data "ocm_addons" "my_addons" {
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_addons", "my_addons")
+		Expect(resource).To(MatchJQ(`.attributes.items | length`, 2))
+		Expect(resource).To(MatchJQ(`.attributes.items[0].id`, "my-addon"))
+		Expect(resource).To(MatchJQ(`.attributes.items[0].enabled`, true))
+		Expect(resource).To(MatchJQ(`.attributes.items[0].version`, "1.0.0"))
+		Expect(resource).To(MatchJQ(`.attributes.items[0].parameters[0].id`, "size"))
+		Expect(resource).To(MatchJQ(`.attributes.items[0].parameters[0].value_type`, "number"))
+		Expect(resource).To(MatchJQ(`.attributes.items[0].parameters[1].options[1].value`, "premium"))
+		Expect(resource).To(MatchJQ(`.attributes.items[1].id`, "my-other-addon"))
+		Expect(resource).To(MatchJQ(`.attributes.items[1].parameters | length`, 0))
+	})
+})
@@ -0,0 +1,474 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2/dsl/core"             // nolint
+	. "github.com/onsi/gomega"                         // nolint
+	. "github.com/onsi/gomega/ghttp"                   // nolint
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+const clusterAddOnDefinition = `{
+  "id": "my-addon",
+  "name": "My add-on",
+  "parameters": {
+    "items": [
+      {
+        "id": "size",
+        "value_type": "number",
+        "required": true,
+        "editable": true
+      },
+      {
+        "id": "tier",
+        "value_type": "string",
+        "editable": false,
+        "options": [
+          {
+            "name": "Basic",
+            "value": "basic"
+          },
+          {
+            "name": "Premium",
+            "value": "premium"
+          }
+        ]
+      }
+    ]
+  }
+}`
+
+var _ = Describe("Cluster add-on creation", func() {
+	BeforeEach(func() {
+		// The first thing that the provider will do when installing an add-on is check
+		// that the cluster is ready, and then get the definition of the add-on:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "123",
+				  "name": "my-cluster",
+				  "state": "ready"
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/addons/my-addon"),
+				RespondWithJSON(http.StatusOK, clusterAddOnDefinition),
+			),
+		)
+	})
+
+	It("Installs the add-on and waits till it is ready", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters/123/addons"),
+				VerifyJQ(`.addon.id`, "my-addon"),
+				VerifyJQ(`.parameters.items[0].id`, "size"),
+				VerifyJQ(`.parameters.items[0].value`, "4"),
+				VerifyJQ(`.parameters.items[1].id`, "tier"),
+				VerifyJQ(`.parameters.items[1].value`, "premium"),
+				RespondWithJSON(http.StatusCreated, `{
+				  "id": "my-addon",
+				  "addon": {
+				    "id": "my-addon"
+				  },
+				  "state": "installing"
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/addons/my-addon"),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "my-addon",
+				  "addon": {
+				    "id": "my-addon"
+				  },
+				  "parameters": {
+				    "items": [
+				      {
+				        "id": "size",
+				        "value": "4"
+				      },
+				      {
+				        "id": "tier",
+				        "value": "premium"
+				      },
+				      {
+				        "id": "other",
+				        "value": "default"
+				      }
+				    ]
+				  },
+				  "state": "ready"
+				}`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster_addon" "my_addon" {
+		    cluster = "123"
+		    addon   = "my-addon"
+		    parameters = {
+		      size = "4"
+		      tier = "premium"
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster_addon", "my_addon")
+		Expect(resource).To(MatchJQ(".attributes.id", "my-addon"))
+		Expect(resource).To(MatchJQ(".attributes.state", "ready"))
+		Expect(resource).To(MatchJQ(".attributes.parameters.size", "4"))
+		Expect(resource).To(MatchJQ(".attributes.parameters | length", 2))
+	})
+
+	It("Fails if the installation fails", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters/123/addons"),
+				RespondWithJSON(http.StatusCreated, `{
+				  "id": "my-addon",
+				  "state": "installing"
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/addons/my-addon"),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "my-addon",
+				  "state": "failed",
+				  "state_description": "Not enough resources"
+				}`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster_addon" "my_addon" {
+		    cluster = "123"
+		    addon   = "my-addon"
+		    parameters = {
+		      size = "4"
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+
+		// The add-on should be in the state, but tainted:
+		resource := terraform.Resource("ocm_cluster_addon", "my_addon")
+		Expect(resource).To(MatchJQ(`.status`, "tainted"))
+		Expect(resource).To(MatchJQ(`.attributes.id`, "my-addon"))
+	})
+
+	It("Fails if a parameter doesn't have the right type", func() {
+		terraform.Source(`
+		  resource "ocm_cluster_addon" "my_addon" {
+		    cluster = "123"
+		    addon   = "my-addon"
+		    parameters = {
+		      size = "big"
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails if a parameter isn't one of the options", func() {
+		terraform.Source(`
+		  resource "ocm_cluster_addon" "my_addon" {
+		    cluster = "123"
+		    addon   = "my-addon"
+		    parameters = {
+		      size = "4"
+		      tier = "gold"
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails if a required parameter is missing", func() {
+		terraform.Source(`
+		  resource "ocm_cluster_addon" "my_addon" {
+		    cluster = "123"
+		    addon   = "my-addon"
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+})
+
+var _ = Describe("Cluster add-on update", func() {
+	const installation = `{
+	  "id": "my-addon",
+	  "addon": {
+	    "id": "my-addon"
+	  },
+	  "parameters": {
+	    "items": [
+	      {
+	        "id": "size",
+	        "value": "4"
+	      },
+	      {
+	        "id": "tier",
+	        "value": "basic"
+	      }
+	    ]
+	  },
+	  "state": "ready"
+	}`
+
+	BeforeEach(func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "123",
+				  "name": "my-cluster",
+				  "state": "ready"
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/addons/my-addon"),
+				RespondWithJSON(http.StatusOK, clusterAddOnDefinition),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters/123/addons"),
+				RespondWithJSON(http.StatusCreated, installation),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/addons/my-addon"),
+				RespondWithJSON(http.StatusOK, installation),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster_addon" "my_addon" {
+		    cluster = "123"
+		    addon   = "my-addon"
+		    parameters = {
+		      size = "4"
+		      tier = "basic"
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+	})
+
+	It("Updates editable parameters in place", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/addons/my-addon"),
+				RespondWithJSON(http.StatusOK, installation),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/addons/my-addon"),
+				RespondWithJSON(http.StatusOK, clusterAddOnDefinition),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123/addons/my-addon"),
+				VerifyJQ(`.parameters.items[0].id`, "size"),
+				VerifyJQ(`.parameters.items[0].value`, "8"),
+				RespondWithJSON(http.StatusOK, installation),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/addons/my-addon"),
+				RespondWithPatchedJSON(http.StatusOK, installation, `[
+				  {
+				    "op": "replace",
+				    "path": "/parameters/items/0/value",
+				    "value": "8"
+				  }
+				]`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster_addon" "my_addon" {
+		    cluster = "123"
+		    addon   = "my-addon"
+		    parameters = {
+		      size = "8"
+		      tier = "basic"
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster_addon", "my_addon")
+		Expect(resource).To(MatchJQ(".attributes.parameters.size", "8"))
+	})
+
+	It("Saves the updated parameters when the waiting fails", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/addons/my-addon"),
+				RespondWithJSON(http.StatusOK, installation),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/addons/my-addon"),
+				RespondWithJSON(http.StatusOK, clusterAddOnDefinition),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123/addons/my-addon"),
+				RespondWithJSON(http.StatusOK, installation),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/addons/my-addon"),
+				RespondWithPatchedJSON(http.StatusOK, installation, `[
+				  {
+				    "op": "replace",
+				    "path": "/state",
+				    "value": "failed"
+				  }
+				]`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster_addon" "my_addon" {
+		    cluster = "123"
+		    addon   = "my-addon"
+		    parameters = {
+		      size = "8"
+		      tier = "basic"
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster_addon", "my_addon")
+		Expect(resource).To(MatchJQ(".attributes.parameters.size", "8"))
+	})
+
+	It("Fails to remove a parameter that isn't editable", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/addons/my-addon"),
+				RespondWithJSON(http.StatusOK, installation),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/addons/my-addon"),
+				RespondWithJSON(http.StatusOK, clusterAddOnDefinition),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster_addon" "my_addon" {
+		    cluster = "123"
+		    addon   = "my-addon"
+		    parameters = {
+		      size = "4"
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails to change a parameter that isn't editable", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/addons/my-addon"),
+				RespondWithJSON(http.StatusOK, installation),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/addons/my-addon"),
+				RespondWithJSON(http.StatusOK, clusterAddOnDefinition),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster_addon" "my_addon" {
+		    cluster = "123"
+		    addon   = "my-addon"
+		    parameters = {
+		      size = "4"
+		      tier = "premium"
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+})
+
+var _ = Describe("Cluster add-on import", func() {
+	It("Can import an add-on installation", func() {
+		// Prepare the server:
+		installation := `{
+		  "id": "my-addon",
+		  "addon": {
+		    "id": "my-addon"
+		  },
+		  "parameters": {
+		    "items": [
+		      {
+		        "id": "size",
+		        "value": "4"
+		      }
+		    ]
+		  },
+		  "state": "ready"
+		}`
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/addons/my-addon"),
+				RespondWithJSON(http.StatusOK, installation),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/addons/my-addon"),
+				RespondWithJSON(http.StatusOK, installation),
+			),
+		)
+
+		// Run the import command:
+		terraform.Source(`
+		  resource "ocm_cluster_addon" "my_addon" {
+		    cluster = "123"
+		    addon   = "my-addon"
+		    parameters = {
+		      size = "4"
+		    }
+		  }
+		`)
+		Expect(terraform.Run("import", "ocm_cluster_addon.my_addon", "123,my-addon")).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster_addon", "my_addon")
+		Expect(resource).To(MatchJQ(".attributes.cluster", "123"))
+		Expect(resource).To(MatchJQ(".attributes.addon", "my-addon"))
+		Expect(resource).To(MatchJQ(".attributes.parameters.size", "4"))
+	})
+})
@@ -0,0 +1,276 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2/dsl/core"             // nolint
+	. "github.com/onsi/gomega"                         // nolint
+	. "github.com/onsi/gomega/ghttp"                   // nolint
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("Cluster ingress", func() {
+	// This is the default ingress as returned by the server once it has been updated with the
+	// configuration used in the tests.
+	const ingress = `{
+	  "kind": "Ingress",
+	  "id": "def",
+	  "default": true,
+	  "dns_name": "apps.my-cluster.example.com",
+	  "listening": "internal",
+	  "route_selectors": {
+	    "shard": "public"
+	  },
+	  "excluded_namespaces": [
+	    "stage"
+	  ],
+	  "route_wildcard_policy": "WildcardsAllowed",
+	  "route_namespace_ownership_policy": "Strict"
+	}`
+
+	const source = `
+	  resource "ocm_cluster_ingress" "default" {
+	    cluster             = "123"
+	    private             = true
+	    route_selectors     = {
+	      shard = "public"
+	    }
+	    excluded_namespaces = ["stage"]
+	    wildcard_policy     = "WildcardsAllowed"
+	  }
+	`
+
+	BeforeEach(func() {
+		// The provider checks that the cluster is ready and then adopts the default ingress:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "123",
+				  "name": "my-cluster",
+				  "state": "ready"
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/ingresses"),
+				RespondWithJSON(http.StatusOK, `{
+				  "kind": "IngressList",
+				  "page": 1,
+				  "size": 2,
+				  "total": 2,
+				  "items": [
+				    {
+				      "kind": "Ingress",
+				      "id": "abc",
+				      "default": false
+				    },
+				    {
+				      "kind": "Ingress",
+				      "id": "def",
+				      "default": true
+				    }
+				  ]
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123/ingresses/def"),
+				VerifyJSON(`{
+				  "listening": "internal",
+				  "route_selectors": {
+				    "shard": "public"
+				  },
+				  "excluded_namespaces": [
+				    "stage"
+				  ],
+				  "route_wildcard_policy": "WildcardsAllowed"
+				}`),
+				RespondWithJSON(http.StatusOK, ingress),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(source)
+		Expect(terraform.Apply()).To(BeZero())
+	})
+
+	It("Adopts the default ingress", func() {
+		resource := terraform.Resource("ocm_cluster_ingress", "default")
+		Expect(resource).To(MatchJQ(".attributes.cluster", "123"))
+		Expect(resource).To(MatchJQ(".attributes.id", "def"))
+		Expect(resource).To(MatchJQ(".attributes.dns_name", "apps.my-cluster.example.com"))
+		Expect(resource).To(MatchJQ(".attributes.private", true))
+		Expect(resource).To(MatchJQ(".attributes.route_selectors.shard", "public"))
+		Expect(resource).To(MatchJQ(".attributes.excluded_namespaces", []interface{}{"stage"}))
+		Expect(resource).To(MatchJQ(".attributes.wildcard_policy", "WildcardsAllowed"))
+		Expect(resource).To(MatchJQ(".attributes.namespace_ownership_policy", "Strict"))
+	})
+
+	It("Patches only the changed attributes", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/ingresses/def"),
+				RespondWithJSON(http.StatusOK, ingress),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123/ingresses/def"),
+				VerifyJSON(`{
+				  "route_namespace_ownership_policy": "InterNamespaceAllowed"
+				}`),
+				RespondWithPatchedJSON(http.StatusOK, ingress, `[
+				  {
+				    "op": "replace",
+				    "path": "/route_namespace_ownership_policy",
+				    "value": "InterNamespaceAllowed"
+				  }
+				]`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster_ingress" "default" {
+		    cluster                    = "123"
+		    private                    = true
+		    route_selectors            = {
+		      shard = "public"
+		    }
+		    excluded_namespaces        = ["stage"]
+		    wildcard_policy            = "WildcardsAllowed"
+		    namespace_ownership_policy = "InterNamespaceAllowed"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster_ingress", "default")
+		Expect(resource).To(MatchJQ(".attributes.namespace_ownership_policy", "InterNamespaceAllowed"))
+	})
+
+	It("Restores the default settings when destroyed", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/ingresses/def"),
+				RespondWithJSON(http.StatusOK, ingress),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123/ingresses/def"),
+				VerifyJSON(`{
+				  "listening": "external",
+				  "route_selectors": {},
+				  "excluded_namespaces": [],
+				  "route_wildcard_policy": "WildcardsDisallowed",
+				  "route_namespace_ownership_policy": "Strict"
+				}`),
+				RespondWithJSON(http.StatusOK, `{
+				  "kind": "Ingress",
+				  "id": "def",
+				  "default": true,
+				  "listening": "external"
+				}`),
+			),
+		)
+
+		// Run the destroy command:
+		Expect(terraform.Destroy()).To(BeZero())
+	})
+
+	It("Removes the ingress from the state if the cluster was deleted", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/ingresses/def"),
+				RespondWithJSON(http.StatusNotFound, `{
+				  "kind": "Error",
+				  "id": "404",
+				  "href": "/api/clusters_mgmt/v1/errors/404",
+				  "code": "CLUSTERS-MGMT-404",
+				  "reason": "Cluster '123' not found"
+				}`),
+			),
+		)
+
+		// Run the destroy command:
+		Expect(terraform.Destroy()).To(BeZero())
+	})
+})
+
+var _ = Describe("Cluster ingress import", func() {
+	const ingress = `{
+	  "kind": "Ingress",
+	  "id": "def",
+	  "default": true,
+	  "dns_name": "apps.my-cluster.example.com",
+	  "listening": "external",
+	  "route_selectors": {
+	    "shard": "public"
+	  }
+	}`
+
+	It("Can import an ingress", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/ingresses/def"),
+				RespondWithJSON(http.StatusOK, ingress),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/ingresses/def"),
+				RespondWithJSON(http.StatusOK, ingress),
+			),
+		)
+
+		// Run the import command:
+		terraform.Source(`
+		  resource "ocm_cluster_ingress" "default" {
+		    cluster = "123"
+		  }
+		`)
+		Expect(terraform.Run("import", "ocm_cluster_ingress.default", "123,def")).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster_ingress", "default")
+		Expect(resource).To(MatchJQ(".attributes.cluster", "123"))
+		Expect(resource).To(MatchJQ(".attributes.id", "def"))
+		Expect(resource).To(MatchJQ(".attributes.private", false))
+		Expect(resource).To(MatchJQ(".attributes.route_selectors.shard", "public"))
+		Expect(resource).To(MatchJQ(".attributes.wildcard_policy", "WildcardsDisallowed"))
+		Expect(resource).To(MatchJQ(".attributes.namespace_ownership_policy", "Strict"))
+	})
+
+	It("Fails to import an ingress with an invalid identifier", func() {
+		terraform.Source(`
+		  resource "ocm_cluster_ingress" "default" {
+		    cluster = "123"
+		  }
+		`)
+		Expect(terraform.Run("import", "ocm_cluster_ingress.default", "def")).ToNot(BeZero())
+	})
+
+	It("Fails with an invalid wildcard policy", func() {
+		terraform.Source(`
+		  resource "ocm_cluster_ingress" "default" {
+		    cluster         = "123"
+		    wildcard_policy = "Sometimes"
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+})
@@ -0,0 +1,344 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2/dsl/core"             // nolint
+	. "github.com/onsi/gomega"                         // nolint
+	. "github.com/onsi/gomega/ghttp"                   // nolint
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("Hosted control plane cluster creation", func() {
+	// This is the cluster that will be returned by the server when asked to create or retrieve
+	// a cluster.
+	const template = `{
+	  "id": "123",
+	  "name": "my-cluster",
+	  "state": "installing",
+	  "region": {
+	    "id": "us-west-1"
+	  },
+	  "multi_az": true,
+	  "hypershift": {
+	    "enabled": true
+	  },
+	  "api": {
+	    "url": "https://my-api.example.com"
+	  },
+	  "console": {
+	    "url": "https://my-console.example.com"
+	  },
+	  "network": {
+	    "machine_cidr": "10.0.0.0/16",
+	    "service_cidr": "172.30.0.0/16",
+	    "pod_cidr": "10.128.0.0/14",
+	    "host_prefix": 23
+	  },
+	  "version": {
+	    "id": "openshift-v4.12.10"
+	  },
+	  "aws": {
+	    "account_id": "123456789012",
+	    "billing_account_id": "210987654321",
+	    "subnet_ids": ["subnet-1", "subnet-2"],
+	    "sts": {
+	      "oidc_endpoint_url": "https://oidc_endpoint_url",
+	      "oidc_config": {
+	        "id": "456"
+	      },
+	      "role_arn": "arn:aws:iam::123456789012:role/installer",
+	      "support_role_arn": "arn:aws:iam::123456789012:role/support",
+	      "instance_iam_roles": {
+	        "worker_role_arn": "arn:aws:iam::123456789012:role/worker"
+	      },
+	      "operator_role_prefix": "test",
+	      "operator_iam_roles": [
+	        {
+	          "name": "cloud-credentials",
+	          "namespace": "openshift-ingress-operator",
+	          "role_arn": "arn:aws:iam::123456789012:role/test-openshift-ingress-operator-cloud-credentials"
+	        }
+	      ]
+	    }
+	  }
+	}`
+
+	const credentialRequests = `{
+	  "kind": "STSCredentialRequestList",
+	  "page": 1,
+	  "size": 1,
+	  "total": 1,
+	  "items": [
+	    {
+	      "kind": "STSCredentialRequest",
+	      "name": "ingress",
+	      "operator": {
+	        "name": "cloud-credentials",
+	        "namespace": "openshift-ingress-operator"
+	      }
+	    }
+	  ]
+	}`
+
+	const config = `
+	  resource "ocm_cluster_rosa_hcp" "my_cluster" {
+	    name                   = "my-cluster"
+	    cloud_region           = "us-west-1"
+	    aws_account_id         = "123456789012"
+	    aws_billing_account_id = "210987654321"
+	    aws_subnet_ids         = ["subnet-1", "subnet-2"]
+	    sts = {
+	      oidc_config_id   = "456"
+	      role_arn         = "arn:aws:iam::123456789012:role/installer"
+	      support_role_arn = "arn:aws:iam::123456789012:role/support"
+	      instance_iam_roles = {
+	        worker_role_arn = "arn:aws:iam::123456789012:role/worker"
+	      }
+	      operator_role_prefix = "test"
+	    }
+	    disable_waiting_in_destroy = true
+	  }
+	`
+
+	It("Creates a cluster with a hosted control plane", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/oidc_configs/456"),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "456",
+				  "managed": true
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/aws_inquiries/sts_credential_requests"),
+				VerifyFormKV("is_hypershift", "true"),
+				RespondWithJSON(http.StatusOK, credentialRequests),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+				VerifyJQ(`.name`, "my-cluster"),
+				VerifyJQ(`.product.id`, "rosa"),
+				VerifyJQ(`.hypershift.enabled`, true),
+				VerifyJQ(`.aws.billing_account_id`, "210987654321"),
+				VerifyJQ(`.aws.subnet_ids`, []interface{}{"subnet-1", "subnet-2"}),
+				VerifyJQ(`.aws.sts.oidc_config.id`, "456"),
+				VerifyJQ(`.aws.sts.instance_iam_roles.worker_role_arn`, "arn:aws:iam::123456789012:role/worker"),
+				VerifyJQ(`.aws.sts.instance_iam_roles.master_role_arn`, nil),
+				VerifyJQ(`.aws.sts.operator_iam_roles[0].role_arn`,
+					"arn:aws:iam::123456789012:role/test-openshift-ingress-operator-cloud-credentials"),
+				VerifyJQ(`.nodes`, nil),
+				RespondWithJSON(http.StatusCreated, template),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(config)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster_rosa_hcp", "my_cluster")
+		Expect(resource).To(MatchJQ(".attributes.id", "123"))
+		Expect(resource).To(MatchJQ(".attributes.aws_billing_account_id", "210987654321"))
+		Expect(resource).To(MatchJQ(".attributes.sts.oidc_endpoint_url", "oidc_endpoint_url"))
+		Expect(resource).To(MatchJQ(".attributes.sts.operator_iam_roles[0]",
+			"arn:aws:iam::123456789012:role/test-openshift-ingress-operator-cloud-credentials"))
+		Expect(resource).To(MatchJQ(".attributes.version", "openshift-v4.12.10"))
+	})
+
+	It("Updates the attributes used by the provider", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/oidc_configs/456"),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "456",
+				  "managed": true
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/aws_inquiries/sts_credential_requests"),
+				RespondWithJSON(http.StatusOK, credentialRequests),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+				RespondWithJSON(http.StatusCreated, template),
+			),
+		)
+
+		// Run the apply command to create the cluster:
+		terraform.Source(config)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Prepare the server for the refresh of the cluster:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, template),
+			),
+		)
+
+		// Run the apply command to update the cluster:
+		terraform.Source(`
+		  resource "ocm_cluster_rosa_hcp" "my_cluster" {
+		    name                   = "my-cluster"
+		    cloud_region           = "us-west-1"
+		    aws_account_id         = "123456789012"
+		    aws_billing_account_id = "210987654321"
+		    aws_subnet_ids         = ["subnet-1", "subnet-2"]
+		    sts = {
+		      oidc_config_id   = "456"
+		      role_arn         = "arn:aws:iam::123456789012:role/installer"
+		      support_role_arn = "arn:aws:iam::123456789012:role/support"
+		      instance_iam_roles = {
+		        worker_role_arn = "arn:aws:iam::123456789012:role/worker"
+		      }
+		      operator_role_prefix = "test"
+		    }
+		    disable_waiting_in_destroy = true
+		    destroy_timeout            = 10
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster_rosa_hcp", "my_cluster")
+		Expect(resource).To(MatchJQ(".attributes.destroy_timeout", 10))
+		Expect(resource).To(MatchJQ(".attributes.sts.oidc_endpoint_url", "oidc_endpoint_url"))
+		Expect(resource).To(MatchJQ(".attributes.sts.operator_iam_roles[0]",
+			"arn:aws:iam::123456789012:role/test-openshift-ingress-operator-cloud-credentials"))
+	})
+
+	It("Fails to change the STS configuration", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/oidc_configs/456"),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "456",
+				  "managed": true
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/aws_inquiries/sts_credential_requests"),
+				RespondWithJSON(http.StatusOK, credentialRequests),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+				RespondWithJSON(http.StatusCreated, template),
+			),
+		)
+
+		// Run the apply command to create the cluster:
+		terraform.Source(config)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Prepare the server for the refresh of the cluster:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, template),
+			),
+		)
+
+		// Run the apply command with a different role:
+		terraform.Source(`
+		  resource "ocm_cluster_rosa_hcp" "my_cluster" {
+		    name                   = "my-cluster"
+		    cloud_region           = "us-west-1"
+		    aws_account_id         = "123456789012"
+		    aws_billing_account_id = "210987654321"
+		    aws_subnet_ids         = ["subnet-1", "subnet-2"]
+		    sts = {
+		      oidc_config_id   = "456"
+		      role_arn         = "arn:aws:iam::123456789012:role/other-installer"
+		      support_role_arn = "arn:aws:iam::123456789012:role/support"
+		      instance_iam_roles = {
+		        worker_role_arn = "arn:aws:iam::123456789012:role/worker"
+		      }
+		      operator_role_prefix = "test"
+		    }
+		    disable_waiting_in_destroy = true
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails if the OIDC configuration isn't managed", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/oidc_configs/456"),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "456",
+				  "managed": false
+				}`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(config)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails without subnets", func() {
+		terraform.Source(`
+		  resource "ocm_cluster_rosa_hcp" "my_cluster" {
+		    name                   = "my-cluster"
+		    cloud_region           = "us-west-1"
+		    aws_account_id         = "123456789012"
+		    aws_billing_account_id = "210987654321"
+		    aws_subnet_ids         = []
+		    sts = {
+		      oidc_config_id   = "456"
+		      role_arn         = "arn:aws:iam::123456789012:role/installer"
+		      support_role_arn = "arn:aws:iam::123456789012:role/support"
+		      instance_iam_roles = {
+		        worker_role_arn = "arn:aws:iam::123456789012:role/worker"
+		      }
+		      operator_role_prefix = "test"
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails with an invalid billing account", func() {
+		terraform.Source(`
+		  resource "ocm_cluster_rosa_hcp" "my_cluster" {
+		    name                   = "my-cluster"
+		    cloud_region           = "us-west-1"
+		    aws_account_id         = "123456789012"
+		    aws_billing_account_id = "1234"
+		    aws_subnet_ids         = ["subnet-1"]
+		    sts = {
+		      oidc_config_id   = "456"
+		      role_arn         = "arn:aws:iam::123456789012:role/installer"
+		      support_role_arn = "arn:aws:iam::123456789012:role/support"
+		      instance_iam_roles = {
+		        worker_role_arn = "arn:aws:iam::123456789012:role/worker"
+		      }
+		      operator_role_prefix = "test"
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+})
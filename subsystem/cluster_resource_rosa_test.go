@@ -17,6 +17,7 @@ limitations under the License.
 package provider
 
 import (
+	"fmt"
 	"net/http"
 
 	. "github.com/onsi/ginkgo/v2/dsl/core"             // nolint
@@ -161,6 +162,649 @@ var _ = Describe("Cluster creation", func() {
 		Expect(terraform.Apply()).To(BeZero())
 	})
 
+	Context("Plan time validation", func() {
+		const source = `
+		  resource "ocm_cluster_rosa_classic" "my_cluster" {
+		    name           = "my-cluster"
+		    cloud_region   = "us-west-1"
+			aws_account_id = "123"
+			aws_subnet_ids = ["subnet-1"]
+			sts = {
+				operator_role_prefix = "test"
+				role_arn = "",
+				support_role_arn = "",
+				instance_iam_roles = {
+					master_role_arn = "",
+					worker_role_arn = "",
+				}
+			}
+		  }
+		`
+
+		BeforeEach(func() {
+			terraform.Setenv("OCM_CLUSTER_DRY_RUN", "true")
+		})
+
+		It("Reports the errors of the dry run in the plan", func() {
+			// Prepare the server:
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+					VerifyFormKV("dryRun", "true"),
+					VerifyJQ(`.name`, "my-cluster"),
+					VerifyJQ(`.aws.subnet_ids`, []interface{}{"subnet-1"}),
+					RespondWithJSON(http.StatusBadRequest, `{
+					  "id": "400",
+					  "code": "CLUSTERS-MGMT-400",
+					  "reason": "The subnet 'subnet-1' doesn't exist in region 'us-west-1'"
+					}`),
+				),
+			)
+
+			// Run the apply command, which shouldn't try to create the cluster:
+			terraform.Source(source)
+			Expect(terraform.Apply()).ToNot(BeZero())
+		})
+
+		It("Creates the cluster when the dry run succeeds", func() {
+			// Prepare the server. The dry run is sent when planning and again when Terraform
+			// plans the resource during the apply:
+			dryRun := CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+				VerifyFormKV("dryRun", "true"),
+				RespondWith(http.StatusNoContent, nil),
+			)
+			server.AppendHandlers(
+				dryRun,
+				dryRun,
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/versions"),
+					RespondWithJSON(http.StatusOK, versionListPage1),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+					VerifyJQ(`.name`, "my-cluster"),
+					RespondWithPatchedJSON(http.StatusCreated, template, `[
+						{
+						  "op": "add",
+						  "path": "/aws",
+						  "value": {
+							  "subnet_ids": ["subnet-1"],
+							  "sts" : {
+								  "oidc_endpoint_url": "https://oidc_endpoint_url",
+								  "thumbprint": "111111",
+								  "role_arn": "",
+								  "support_role_arn": "",
+								  "instance_iam_roles" : {
+									"master_role_arn" : "",
+									"worker_role_arn" : ""
+								  },
+								  "operator_role_prefix" : "test"
+							  }
+						  }
+						},
+						{
+						  "op": "add",
+						  "path": "/nodes",
+						  "value": {
+							"compute": 3,
+							"compute_machine_type": {
+								"id": "r5.xlarge"
+							}
+						  }
+						}]`),
+				),
+			)
+
+			// Run the apply command:
+			terraform.Source(source)
+			Expect(terraform.Apply()).To(BeZero())
+		})
+	})
+
+	Context("Adoption of existing clusters", func() {
+		const source = `
+		  resource "ocm_cluster_rosa_classic" "my_cluster" {
+		    name           = "my-cluster"
+		    cloud_region   = "us-west-1"
+			aws_account_id = "123"
+			adopt_existing = true
+			sts = {
+				operator_role_prefix = "test"
+				role_arn = "",
+				support_role_arn = "",
+				instance_iam_roles = {
+					master_role_arn = "",
+					worker_role_arn = "",
+				}
+			}
+		  }
+		`
+
+		// existing is the list of clusters returned by the server when searching for the
+		// cluster that was created by a previous interrupted apply.
+		const existing = `{
+		  "kind": "ClusterList",
+		  "page": 1,
+		  "size": 1,
+		  "total": 1,
+		  "items": [{
+		    "id": "123",
+		    "name": "my-cluster",
+		    "state": "installing",
+		    "region": {
+		      "id": "%s"
+		    },
+		    "multi_az": true,
+		    "aws": {
+		      "account_id": "123",
+		      "sts": {
+		        "oidc_endpoint_url": "https://oidc_endpoint_url",
+		        "thumbprint": "111111",
+		        "role_arn": "",
+		        "support_role_arn": "",
+		        "instance_iam_roles": {
+		          "master_role_arn": "",
+		          "worker_role_arn": ""
+		        },
+		        "operator_role_prefix": "test"
+		      }
+		    },
+		    "nodes": {
+		      "compute": 3,
+		      "compute_machine_type": {
+		        "id": "r5.xlarge"
+		      }
+		    },
+		    "version": {
+		      "id": "openshift-4.8.0"
+		    }
+		  }]
+		}`
+
+		It("Adopts the existing cluster when it matches the plan", func() {
+			// Prepare the server, which shouldn't receive any request to create the cluster:
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters"),
+					VerifyFormKV("search",
+						"name = 'my-cluster' AND aws.account_id = '123' AND state != 'uninstalling'"),
+					RespondWithJSON(http.StatusOK, fmt.Sprintf(existing, "us-west-1")),
+				),
+			)
+
+			// Run the apply command:
+			terraform.Source(source)
+			Expect(terraform.Apply()).To(BeZero())
+			resource := terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+			Expect(resource).To(MatchJQ(`.attributes.id`, "123"))
+		})
+
+		It("Reports the differences when the existing cluster doesn't match the plan", func() {
+			// Prepare the server:
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters"),
+					RespondWithJSON(http.StatusOK, fmt.Sprintf(existing, "us-east-1")),
+				),
+			)
+
+			// Run the apply command:
+			terraform.Source(source)
+			Expect(terraform.Apply()).ToNot(BeZero())
+		})
+
+		It("Creates the cluster when there is no existing one", func() {
+			// Prepare the server:
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters"),
+					RespondWithJSON(http.StatusOK, `{
+					  "kind": "ClusterList",
+					  "page": 1,
+					  "size": 0,
+					  "total": 0,
+					  "items": []
+					}`),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/versions"),
+					RespondWithJSON(http.StatusOK, versionListPage1),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+					VerifyJQ(`.name`, "my-cluster"),
+					RespondWithPatchedJSON(http.StatusCreated, template, `[
+						{
+						  "op": "add",
+						  "path": "/aws",
+						  "value": {
+							  "account_id": "123",
+							  "sts" : {
+								  "oidc_endpoint_url": "https://oidc_endpoint_url",
+								  "thumbprint": "111111",
+								  "role_arn": "",
+								  "support_role_arn": "",
+								  "instance_iam_roles" : {
+									"master_role_arn" : "",
+									"worker_role_arn" : ""
+								  },
+								  "operator_role_prefix" : "test"
+							  }
+						  }
+						},
+						{
+						  "op": "add",
+						  "path": "/nodes",
+						  "value": {
+							"compute": 3,
+							"compute_machine_type": {
+								"id": "r5.xlarge"
+							}
+						  }
+						}]`),
+				),
+			)
+
+			// Run the apply command:
+			terraform.Source(source)
+			Expect(terraform.Apply()).To(BeZero())
+		})
+	})
+
+	Context("Wait for the cluster to be ready", func() {
+		const source = `
+		  resource "ocm_cluster_rosa_classic" "my_cluster" {
+		    name           = "my-cluster"
+		    cloud_region   = "us-west-1"
+			aws_account_id = "123"
+			wait_for_create_complete = true
+			create_timeout = 30
+			sts = {
+				operator_role_prefix = "test"
+				role_arn = "",
+				support_role_arn = "",
+				instance_iam_roles = {
+					master_role_arn = "",
+					worker_role_arn = "",
+				}
+			}
+		  }
+		`
+
+		const patch = `[
+		  {
+		    "op": "add",
+		    "path": "/aws",
+		    "value": {
+		      "sts" : {
+		        "oidc_endpoint_url": "https://oidc_endpoint_url",
+		        "thumbprint": "111111",
+		        "role_arn": "",
+		        "support_role_arn": "",
+		        "instance_iam_roles" : {
+		          "master_role_arn" : "",
+		          "worker_role_arn" : ""
+		        },
+		        "operator_role_prefix" : "test"
+		      }
+		    }
+		  },
+		  {
+		    "op": "add",
+		    "path": "/nodes",
+		    "value": {
+		      "compute": 3,
+		      "compute_machine_type": {
+		        "id": "r5.xlarge"
+		      }
+		    }
+		  }
+		]`
+
+		BeforeEach(func() {
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/versions"),
+					RespondWithJSON(http.StatusOK, versionListPage1),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+					VerifyJQ(`.name`, "my-cluster"),
+					RespondWithPatchedJSON(http.StatusCreated, template, patch),
+				),
+			)
+		})
+
+		It("Waits till the cluster is ready", func() {
+			// Prepare the server:
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithPatchedJSON(http.StatusOK, templateReadyState, patch),
+				),
+			)
+
+			// Run the apply command:
+			terraform.Source(source)
+			Expect(terraform.Apply()).To(BeZero())
+			resource := terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+			Expect(resource).To(MatchJQ(`.attributes.state`, "ready"))
+		})
+
+		It("Taints the resource when the installation fails", func() {
+			// Prepare the server:
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithPatchedJSON(http.StatusOK, template, `[
+					  {
+					    "op": "add",
+					    "path": "/state",
+					    "value": "error"
+					  },
+					  {
+					    "op": "add",
+					    "path": "/status",
+					    "value": {
+					      "state": "error",
+					      "provision_error_code": "OCM3055",
+					      "provision_error_message": "Invalid subnet"
+					    }
+					  }
+					]`),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/status"),
+					RespondWithJSON(http.StatusOK, `{
+					  "state": "error",
+					  "provision_error_code": "OCM3055",
+					  "provision_error_message": "Invalid subnet"
+					}`),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/logs/install"),
+					RespondWithJSON(http.StatusOK, `{
+					  "content": "level=error msg=\"subnet-1 doesn't exist\""
+					}`),
+				),
+			)
+
+			// Run the apply command:
+			terraform.Source(source)
+			Expect(terraform.Apply()).ToNot(BeZero())
+			resource := terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+			Expect(resource).To(MatchJQ(`.status`, "tainted"))
+			Expect(resource).To(MatchJQ(`.attributes.state`, "error"))
+			Expect(resource).To(MatchJQ(`.attributes.provision_error`, "OCM3055: Invalid subnet"))
+		})
+	})
+
+	Context("Update of mutable attributes", func() {
+		const patch = `[
+		  {
+		    "op": "add",
+		    "path": "/aws",
+		    "value": {
+		      "sts" : {
+		        "oidc_endpoint_url": "https://oidc_endpoint_url",
+		        "thumbprint": "111111",
+		        "role_arn": "",
+		        "support_role_arn": "",
+		        "instance_iam_roles" : {
+		          "master_role_arn" : "",
+		          "worker_role_arn" : ""
+		        },
+		        "operator_role_prefix" : "test"
+		      }
+		    }
+		  },
+		  {
+		    "op": "add",
+		    "path": "/nodes",
+		    "value": {
+		      "compute": 3,
+		      "compute_machine_type": {
+		        "id": "r5.xlarge"
+		      }
+		    }
+		  }
+		]`
+
+		const updatedPatch = `[
+		  {
+		    "op": "add",
+		    "path": "/aws",
+		    "value": {
+		      "sts" : {
+		        "oidc_endpoint_url": "https://oidc_endpoint_url",
+		        "thumbprint": "111111",
+		        "role_arn": "",
+		        "support_role_arn": "",
+		        "instance_iam_roles" : {
+		          "master_role_arn" : "",
+		          "worker_role_arn" : ""
+		        },
+		        "operator_role_prefix" : "test"
+		      }
+		    }
+		  },
+		  {
+		    "op": "add",
+		    "path": "/nodes",
+		    "value": {
+		      "compute": 3,
+		      "compute_machine_type": {
+		        "id": "r5.xlarge"
+		      },
+		      "compute_labels": {
+		        "role": "dev"
+		      }
+		    }
+		  },
+		  {
+		    "op": "add",
+		    "path": "/properties",
+		    "value": {
+		      "team": "dev"
+		    }
+		  },
+		  {
+		    "op": "add",
+		    "path": "/disable_user_workload_monitoring",
+		    "value": true
+		  },
+		  {
+		    "op": "add",
+		    "path": "/proxy",
+		    "value": {
+		      "http_proxy": "http://proxy.com",
+		      "https_proxy": "http://proxy.com",
+		      "no_proxy": "example.com"
+		    }
+		  },
+		  {
+		    "op": "add",
+		    "path": "/additional_trust_bundle",
+		    "value": "123"
+		  }
+		]`
+
+		const source = `
+		  resource "ocm_cluster_rosa_classic" "my_cluster" {
+		    name           = "my-cluster"
+		    cloud_region   = "us-west-1"
+			aws_account_id = "123"
+			sts = {
+				operator_role_prefix = "test"
+				role_arn = "",
+				support_role_arn = "",
+				instance_iam_roles = {
+					master_role_arn = "",
+					worker_role_arn = "",
+				}
+			}
+		  }
+		`
+
+		const updatedSource = `
+		  resource "ocm_cluster_rosa_classic" "my_cluster" {
+		    name           = "my-cluster"
+		    cloud_region   = "us-west-1"
+			aws_account_id = "123"
+			properties = {
+				team = "dev"
+			}
+			default_mp_labels = {
+				role = "dev"
+			}
+			disable_workload_monitoring = true
+			proxy = {
+				http_proxy = "http://proxy.com",
+				https_proxy = "http://proxy.com",
+				no_proxy = "example.com",
+				additional_trust_bundle = "123",
+			}
+			sts = {
+				operator_role_prefix = "test"
+				role_arn = "",
+				support_role_arn = "",
+				instance_iam_roles = {
+					master_role_arn = "",
+					worker_role_arn = "",
+				}
+			}
+		  }
+		`
+
+		BeforeEach(func() {
+			// Create the cluster without any of the mutable attributes:
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/versions"),
+					RespondWithJSON(http.StatusOK, versionListPage1),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+					RespondWithPatchedJSON(http.StatusCreated, template, patch),
+				),
+			)
+			terraform.Source(source)
+			Expect(terraform.Apply()).To(BeZero())
+		})
+
+		It("Patches the changed attributes", func() {
+			// Prepare the server:
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithPatchedJSON(http.StatusOK, template, patch),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123"),
+					VerifyJQ(`.properties.team`, "dev"),
+					VerifyJQ(`.nodes.compute_labels.role`, "dev"),
+					VerifyJQ(`.disable_user_workload_monitoring`, true),
+					VerifyJQ(`.proxy.http_proxy`, "http://proxy.com"),
+					VerifyJQ(`.proxy.https_proxy`, "http://proxy.com"),
+					VerifyJQ(`.proxy.no_proxy`, "example.com"),
+					VerifyJQ(`.additional_trust_bundle`, "123"),
+					RespondWithPatchedJSON(http.StatusOK, template, updatedPatch),
+				),
+			)
+
+			// Run the apply command:
+			terraform.Source(updatedSource)
+			Expect(terraform.Apply()).To(BeZero())
+
+			// Check the state:
+			resource := terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+			Expect(resource).To(MatchJQ(`.attributes.properties.team`, "dev"))
+			Expect(resource).To(MatchJQ(`.attributes.default_mp_labels.role`, "dev"))
+			Expect(resource).To(MatchJQ(`.attributes.disable_workload_monitoring`, true))
+			Expect(resource).To(MatchJQ(`.attributes.proxy.no_proxy`, "example.com"))
+		})
+
+		It("Clears the removed attributes", func() {
+			// The properties aren't removed, as the state is preserved when they aren't
+			// configured:
+			const clearedPatch = `[
+			  {
+			    "op": "add",
+			    "path": "/aws",
+			    "value": {
+			      "sts" : {
+			        "oidc_endpoint_url": "https://oidc_endpoint_url",
+			        "thumbprint": "111111",
+			        "role_arn": "",
+			        "support_role_arn": "",
+			        "instance_iam_roles" : {
+			          "master_role_arn" : "",
+			          "worker_role_arn" : ""
+			        },
+			        "operator_role_prefix" : "test"
+			      }
+			    }
+			  },
+			  {
+			    "op": "add",
+			    "path": "/nodes",
+			    "value": {
+			      "compute": 3,
+			      "compute_machine_type": {
+			        "id": "r5.xlarge"
+			      }
+			    }
+			  },
+			  {
+			    "op": "add",
+			    "path": "/properties",
+			    "value": {
+			      "team": "dev"
+			    }
+			  }
+			]`
+
+			// Prepare the server:
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithPatchedJSON(http.StatusOK, template, patch),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithPatchedJSON(http.StatusOK, template, updatedPatch),
+				),
+			)
+			terraform.Source(updatedSource)
+			Expect(terraform.Apply()).To(BeZero())
+
+			// Remove the labels, the monitoring setting and the proxy:
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithPatchedJSON(http.StatusOK, template, updatedPatch),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123"),
+					VerifyJQ(`.nodes.compute_labels`, map[string]interface{}{}),
+					VerifyJQ(`.disable_user_workload_monitoring`, false),
+					VerifyJQ(`.proxy.http_proxy`, ""),
+					VerifyJQ(`.proxy.https_proxy`, ""),
+					VerifyJQ(`.additional_trust_bundle`, ""),
+					RespondWithPatchedJSON(http.StatusOK, template, clearedPatch),
+				),
+			)
+			terraform.Source(source)
+			Expect(terraform.Apply()).To(BeZero())
+
+			// Check the state:
+			resource := terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+			Expect(resource).To(MatchJQ(`.attributes.default_mp_labels`, nil))
+			Expect(resource).To(MatchJQ(`.attributes.proxy`, nil))
+		})
+	})
+
 	Context("Test destroy cluster", func() {
 		BeforeEach(func() {
 			server.AppendHandlers(
@@ -333,6 +977,80 @@ var _ = Describe("Cluster creation", func() {
 			Expect(terraform.Apply()).To(BeZero())
 			Expect(terraform.Destroy()).To(BeZero())
 		})
+
+		It("Refuses to destroy a protected cluster", func() {
+			terraform.Source(`
+				  resource "ocm_cluster_rosa_classic" "my_cluster" {
+					name           = "my-cluster"
+					cloud_region   = "us-west-1"
+					aws_account_id = "123"
+					deletion_policy = "protect"
+					sts = {
+						operator_role_prefix = "test"
+						role_arn = "",
+						support_role_arn = "",
+						instance_iam_roles = {
+							master_role_arn = "",
+							worker_role_arn = "",
+						}
+					}
+				  }
+			`)
+			Expect(terraform.Apply()).To(BeZero())
+			Expect(terraform.Destroy()).ToNot(BeZero())
+
+			// The cluster should still be in the state:
+			resource := terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+			Expect(resource).To(MatchJQ(`.attributes.id`, "123"))
+		})
+
+		It("Removes an orphaned cluster from the state without deleting it", func() {
+			terraform.Source(`
+				  resource "ocm_cluster_rosa_classic" "my_cluster" {
+					name           = "my-cluster"
+					cloud_region   = "us-west-1"
+					aws_account_id = "123"
+					deletion_policy = "orphan"
+					sts = {
+						operator_role_prefix = "test"
+						role_arn = "",
+						support_role_arn = "",
+						instance_iam_roles = {
+							master_role_arn = "",
+							worker_role_arn = "",
+						}
+					}
+				  }
+			`)
+			Expect(terraform.Apply()).To(BeZero())
+			Expect(terraform.Destroy()).To(BeZero())
+
+			// The delete request should never have been sent:
+			for _, request := range server.ReceivedRequests() {
+				Expect(request.Method).ToNot(Equal(http.MethodDelete))
+			}
+		})
+
+		It("Fails with an invalid deletion policy", func() {
+			terraform.Source(`
+				  resource "ocm_cluster_rosa_classic" "my_cluster" {
+					name           = "my-cluster"
+					cloud_region   = "us-west-1"
+					aws_account_id = "123"
+					deletion_policy = "keep"
+					sts = {
+						operator_role_prefix = "test"
+						role_arn = "",
+						support_role_arn = "",
+						instance_iam_roles = {
+							master_role_arn = "",
+							worker_role_arn = "",
+						}
+					}
+				  }
+			`)
+			Expect(terraform.Apply()).ToNot(BeZero())
+		})
 	})
 
 	It("Creates cluster with http proxy", func() {
@@ -874,6 +1592,247 @@ var _ = Describe("Cluster creation", func() {
 		Expect(terraform.Apply()).To(BeZero())
 	})
 
+	Context("Test upgrade cluster version", func() {
+		const stsPatch = `[
+			{
+			  "op": "add",
+			  "path": "/aws",
+			  "value": {
+				  "sts" : {
+					  "oidc_endpoint_url": "https://oidc_endpoint_url",
+					  "thumbprint": "111111",
+					  "role_arn": "",
+					  "support_role_arn": "",
+					  "instance_iam_roles" : {
+						"master_role_arn" : "",
+						"worker_role_arn" : ""
+					  },
+					  "operator_role_prefix" : "test"
+				  }
+			  }
+			}]`
+
+		BeforeEach(func() {
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/versions"),
+					RespondWithJSON(http.StatusOK, versionListPage1),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+					RespondWithPatchedJSON(http.StatusCreated, template, stsPatch),
+				),
+			)
+			terraform.Source(`
+			  resource "ocm_cluster_rosa_classic" "my_cluster" {
+				name           = "my-cluster"
+				cloud_region   = "us-west-1"
+				aws_account_id = "123"
+				sts = {
+					operator_role_prefix = "test"
+					role_arn = "",
+					support_role_arn = "",
+					instance_iam_roles = {
+						master_role_arn = "",
+						worker_role_arn = "",
+					}
+				}
+			  }
+			`)
+			Expect(terraform.Apply()).To(BeZero())
+			resource := terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+			Expect(resource).To(MatchJQ(`.attributes.current_version`, "openshift-4.8.0"))
+
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithPatchedJSON(http.StatusOK, template, stsPatch),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/versions/openshift-v4.8.1"),
+					RespondWithJSON(http.StatusOK, `{
+					  "kind": "Version",
+					  "id": "openshift-v4.8.1",
+					  "raw_id": "4.8.1"
+					}`),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/versions/openshift-4.8.0"),
+					RespondWithJSON(http.StatusOK, `{
+					  "kind": "Version",
+					  "id": "openshift-4.8.0",
+					  "raw_id": "4.8.0",
+					  "available_upgrades": ["4.8.1"]
+					}`),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/upgrade_policies"),
+					RespondWithJSON(http.StatusOK, `{
+					  "kind": "UpgradePolicyList",
+					  "page": 1,
+					  "size": 0,
+					  "total": 0,
+					  "items": []
+					}`),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters/123/upgrade_policies"),
+					VerifyJQ(`.version`, "4.8.1"),
+					VerifyJQ(`.schedule_type`, "manual"),
+					VerifyJQ(`.upgrade_type`, "OSD"),
+					RespondWithJSON(http.StatusCreated, `{
+					  "kind": "UpgradePolicy",
+					  "id": "456",
+					  "schedule_type": "manual",
+					  "upgrade_type": "OSD",
+					  "version": "4.8.1"
+					}`),
+				),
+			)
+		})
+
+		It("Schedules the upgrade without waiting", func() {
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/upgrade_policies/456/state"),
+					RespondWithJSON(http.StatusOK, `{
+					  "kind": "UpgradePolicyState",
+					  "value": "scheduled"
+					}`),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithPatchedJSON(http.StatusOK, template, stsPatch),
+				),
+			)
+			terraform.Source(`
+			  resource "ocm_cluster_rosa_classic" "my_cluster" {
+				name           = "my-cluster"
+				cloud_region   = "us-west-1"
+				aws_account_id = "123"
+				version        = "openshift-v4.8.1"
+				sts = {
+					operator_role_prefix = "test"
+					role_arn = "",
+					support_role_arn = "",
+					instance_iam_roles = {
+						master_role_arn = "",
+						worker_role_arn = "",
+					}
+				}
+			  }
+			`)
+			Expect(terraform.Apply()).To(BeZero())
+			resource := terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+			Expect(resource).To(MatchJQ(`.attributes.version`, "openshift-v4.8.1"))
+			Expect(resource).To(MatchJQ(`.attributes.current_version`, "openshift-4.8.0"))
+			Expect(resource).To(MatchJQ(`.attributes.upgrade_state`, "scheduled"))
+
+			// The refresh should keep the requested version while the upgrade is in progress:
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithPatchedJSON(http.StatusOK, template, stsPatch),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/upgrade_policies"),
+					RespondWithJSON(http.StatusOK, `{
+					  "kind": "UpgradePolicyList",
+					  "page": 1,
+					  "size": 1,
+					  "total": 1,
+					  "items": [{
+						  "kind": "UpgradePolicy",
+						  "id": "456",
+						  "schedule_type": "manual",
+						  "upgrade_type": "OSD",
+						  "version": "4.8.1"
+					  }]
+					}`),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/upgrade_policies/456/state"),
+					RespondWithJSON(http.StatusOK, `{
+					  "kind": "UpgradePolicyState",
+					  "value": "started"
+					}`),
+				),
+			)
+			Expect(terraform.Apply()).To(BeZero())
+			resource = terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+			Expect(resource).To(MatchJQ(`.attributes.version`, "openshift-v4.8.1"))
+			Expect(resource).To(MatchJQ(`.attributes.upgrade_state`, "started"))
+		})
+
+		It("Schedules the upgrade and waits till it is completed", func() {
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithPatchedJSON(http.StatusOK, template, `[
+					{
+					  "op": "replace",
+					  "path": "/version",
+					  "value": {
+						  "id": "openshift-v4.8.1"
+					  }
+					}]`),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithPatchedJSON(http.StatusOK, template, `[
+					{
+					  "op": "add",
+					  "path": "/aws",
+					  "value": {
+						  "sts" : {
+							  "oidc_endpoint_url": "https://oidc_endpoint_url",
+							  "thumbprint": "111111",
+							  "role_arn": "",
+							  "support_role_arn": "",
+							  "instance_iam_roles" : {
+								"master_role_arn" : "",
+								"worker_role_arn" : ""
+							  },
+							  "operator_role_prefix" : "test"
+						  }
+					  }
+					},
+					{
+					  "op": "replace",
+					  "path": "/version",
+					  "value": {
+						  "id": "openshift-v4.8.1"
+					  }
+					}]`),
+				),
+			)
+			terraform.Source(`
+			  resource "ocm_cluster_rosa_classic" "my_cluster" {
+				name           = "my-cluster"
+				cloud_region   = "us-west-1"
+				aws_account_id = "123"
+				version        = "openshift-v4.8.1"
+				wait_for_upgrade_complete = true
+				upgrade_timeout = 1
+				sts = {
+					operator_role_prefix = "test"
+					role_arn = "",
+					support_role_arn = "",
+					instance_iam_roles = {
+						master_role_arn = "",
+						worker_role_arn = "",
+					}
+				}
+			  }
+			`)
+			Expect(terraform.Apply()).To(BeZero())
+			resource := terraform.Resource("ocm_cluster_rosa_classic", "my_cluster")
+			Expect(resource).To(MatchJQ(`.attributes.version`, "openshift-v4.8.1"))
+			Expect(resource).To(MatchJQ(`.attributes.current_version`, "openshift-v4.8.1"))
+			Expect(resource).To(MatchJQ(`.attributes.upgrade_state`, "completed"))
+		})
+	})
+
 	It("Creates rosa sts cluster with OIDC Configuration ID", func() {
 		// Prepare the server:
 		server.AppendHandlers(
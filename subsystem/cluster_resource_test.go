@@ -308,4 +308,205 @@ var _ = Describe("Cluster creation", func() {
 		`)
 		Expect(terraform.Apply()).ToNot(BeZero())
 	})
+
+	It("Recreates the cluster if it was deleted outside Terraform", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+				RespondWithJSON(http.StatusCreated, template),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name           = "my-cluster"
+		    product        = "osd"
+		    cloud_provider = "aws"
+		    cloud_region   = "us-west-1"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Prepare the server so that the cluster is no longer found, and then created again:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusNotFound, `{
+				  "kind": "Error",
+				  "id": "404",
+				  "href": "/api/clusters_mgmt/v1/errors/404",
+				  "code": "CLUSTERS-MGMT-404",
+				  "reason": "Cluster '123' not found"
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+				RespondWithJSON(http.StatusCreated, template),
+			),
+		)
+
+		// Run the apply command again:
+		Expect(terraform.Apply()).To(BeZero())
+		resource := terraform.Resource("ocm_cluster", "my_cluster")
+		Expect(resource).To(MatchJQ(".attributes.id", "123"))
+	})
+
+	It("Fails to refresh the cluster if the server returns an error", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+				RespondWithJSON(http.StatusCreated, template),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name           = "my-cluster"
+		    product        = "osd"
+		    cloud_provider = "aws"
+		    cloud_region   = "us-west-1"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Prepare the server so that it fails to return the cluster:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusInternalServerError, `{
+				  "kind": "Error",
+				  "id": "500",
+				  "href": "/api/clusters_mgmt/v1/errors/500",
+				  "code": "CLUSTERS-MGMT-500",
+				  "reason": "Internal error"
+				}`),
+			),
+		)
+
+		// Run the apply command again:
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Hibernates and resumes the cluster", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters"),
+				RespondWithJSON(http.StatusCreated, template),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name           = "my-cluster"
+		    product        = "osd"
+		    cloud_provider = "aws"
+		    cloud_region   = "us-west-1"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+		resource := terraform.Resource("ocm_cluster", "my_cluster")
+		Expect(resource).To(MatchJQ(".attributes.hibernating", false))
+
+		// Prepare the server for the hibernation:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, template),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, template),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters/123/hibernate"),
+				RespondWithJSON(http.StatusOK, "{}"),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithPatchedJSON(http.StatusOK, template, `[
+				  {
+				    "op": "replace",
+				    "path": "/state",
+				    "value": "hibernating"
+				  }
+				]`),
+			),
+		)
+
+		// Hibernate the cluster:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name           = "my-cluster"
+		    product        = "osd"
+		    cloud_provider = "aws"
+		    cloud_region   = "us-west-1"
+		    hibernating    = true
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+		resource = terraform.Resource("ocm_cluster", "my_cluster")
+		Expect(resource).To(MatchJQ(".attributes.hibernating", true))
+		Expect(resource).To(MatchJQ(".attributes.state", "hibernating"))
+
+		// Prepare the server for the resume:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithPatchedJSON(http.StatusOK, template, `[
+				  {
+				    "op": "replace",
+				    "path": "/state",
+				    "value": "hibernating"
+				  }
+				]`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters/123/resume"),
+				RespondWithJSON(http.StatusOK, "{}"),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, template),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, template),
+			),
+		)
+
+		// Resume the cluster:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name           = "my-cluster"
+		    product        = "osd"
+		    cloud_provider = "aws"
+		    cloud_region   = "us-west-1"
+		    hibernating    = false
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+		resource = terraform.Resource("ocm_cluster", "my_cluster")
+		Expect(resource).To(MatchJQ(".attributes.hibernating", false))
+		Expect(resource).To(MatchJQ(".attributes.state", "ready"))
+	})
+
+	It("Fails to create a hibernating cluster", func() {
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster" "my_cluster" {
+		    name           = "my-cluster"
+		    product        = "osd"
+		    cloud_provider = "aws"
+		    cloud_region   = "us-west-1"
+		    hibernating    = true
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
 })
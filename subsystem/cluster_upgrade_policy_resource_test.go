@@ -0,0 +1,338 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2/dsl/core"             // nolint
+	. "github.com/onsi/gomega"                         // nolint
+	. "github.com/onsi/gomega/ghttp"                   // nolint
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("Cluster upgrade policy creation", func() {
+	const scheduledState = `{
+	  "kind": "UpgradePolicyState",
+	  "value": "scheduled"
+	}`
+
+	const automaticPolicy = `{
+	  "kind": "UpgradePolicy",
+	  "id": "456",
+	  "schedule_type": "automatic",
+	  "schedule": "0 2 * * 6",
+	  "upgrade_type": "OSD",
+	  "next_run": "2023-04-22T02:00:00Z"
+	}`
+
+	It("Can create a manual upgrade policy with a node drain grace period", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "123",
+				  "name": "my-cluster",
+				  "state": "ready"
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123"),
+				VerifyJSON(`{
+				  "kind": "Cluster",
+				  "node_drain_grace_period": {
+				    "unit": "minutes",
+				    "value": 30
+				  }
+				}`),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "123",
+				  "name": "my-cluster",
+				  "state": "ready"
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters/123/upgrade_policies"),
+				VerifyJSON(`{
+				  "kind": "UpgradePolicy",
+				  "schedule_type": "manual",
+				  "upgrade_type": "OSD",
+				  "version": "4.12.10",
+				  "next_run": "2023-04-20T12:00:00Z"
+				}`),
+				RespondWithJSON(http.StatusCreated, `{
+				  "kind": "UpgradePolicy",
+				  "id": "456",
+				  "schedule_type": "manual",
+				  "upgrade_type": "OSD",
+				  "version": "4.12.10",
+				  "next_run": "2023-04-20T12:00:00Z"
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/upgrade_policies/456/state"),
+				RespondWithJSON(http.StatusOK, scheduledState),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster_upgrade_policy" "my_policy" {
+		    cluster                 = "123"
+		    schedule_type           = "manual"
+		    version                 = "4.12.10"
+		    next_run                = "2023-04-20T12:00:00Z"
+		    node_drain_grace_period = 30
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster_upgrade_policy", "my_policy")
+		Expect(resource).To(MatchJQ(".attributes.cluster", "123"))
+		Expect(resource).To(MatchJQ(".attributes.id", "456"))
+		Expect(resource).To(MatchJQ(".attributes.schedule_type", "manual"))
+		Expect(resource).To(MatchJQ(".attributes.version", "4.12.10"))
+		Expect(resource).To(MatchJQ(".attributes.next_run", "2023-04-20T12:00:00Z"))
+		Expect(resource).To(MatchJQ(".attributes.node_drain_grace_period", 30.0))
+		Expect(resource).To(MatchJQ(".attributes.state", "scheduled"))
+	})
+
+	It("Can create an automatic upgrade policy and update its schedule", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "123",
+				  "name": "my-cluster",
+				  "state": "ready"
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters/123/upgrade_policies"),
+				VerifyJSON(`{
+				  "kind": "UpgradePolicy",
+				  "schedule_type": "automatic",
+				  "schedule": "0 2 * * 6",
+				  "upgrade_type": "OSD"
+				}`),
+				RespondWithJSON(http.StatusCreated, automaticPolicy),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/upgrade_policies/456/state"),
+				RespondWithJSON(http.StatusOK, scheduledState),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster_upgrade_policy" "my_policy" {
+		    cluster       = "123"
+		    schedule_type = "automatic"
+		    schedule      = "0 2 * * 6"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster_upgrade_policy", "my_policy")
+		Expect(resource).To(MatchJQ(".attributes.schedule", "0 2 * * 6"))
+		Expect(resource).To(MatchJQ(".attributes.next_run", "2023-04-22T02:00:00Z"))
+
+		// Prepare the server for the update:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/upgrade_policies/456"),
+				RespondWithJSON(http.StatusOK, automaticPolicy),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/upgrade_policies/456/state"),
+				RespondWithJSON(http.StatusOK, scheduledState),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123/upgrade_policies/456"),
+				VerifyJSON(`{
+				  "kind": "UpgradePolicy",
+				  "schedule": "0 3 * * 0",
+				  "enable_minor_version_upgrades": true
+				}`),
+				RespondWithJSON(http.StatusOK, `{
+				  "kind": "UpgradePolicy",
+				  "id": "456",
+				  "schedule_type": "automatic",
+				  "schedule": "0 3 * * 0",
+				  "upgrade_type": "OSD",
+				  "enable_minor_version_upgrades": true,
+				  "next_run": "2023-04-23T03:00:00Z"
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/upgrade_policies/456/state"),
+				RespondWithJSON(http.StatusOK, scheduledState),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster_upgrade_policy" "my_policy" {
+		    cluster                       = "123"
+		    schedule_type                 = "automatic"
+		    schedule                      = "0 3 * * 0"
+		    enable_minor_version_upgrades = true
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource = terraform.Resource("ocm_cluster_upgrade_policy", "my_policy")
+		Expect(resource).To(MatchJQ(".attributes.schedule", "0 3 * * 0"))
+		Expect(resource).To(MatchJQ(".attributes.enable_minor_version_upgrades", true))
+		Expect(resource).To(MatchJQ(".attributes.next_run", "2023-04-23T03:00:00Z"))
+	})
+
+	It("Ignores upgrade policies already removed by the server when destroying", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "123",
+				  "name": "my-cluster",
+				  "state": "ready"
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters/123/upgrade_policies"),
+				RespondWithJSON(http.StatusCreated, automaticPolicy),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/upgrade_policies/456/state"),
+				RespondWithJSON(http.StatusOK, scheduledState),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster_upgrade_policy" "my_policy" {
+		    cluster       = "123"
+		    schedule_type = "automatic"
+		    schedule      = "0 2 * * 6"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Prepare the server for the destroy, where the policy is removed by the server after
+		// the refresh:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/upgrade_policies/456"),
+				RespondWithJSON(http.StatusOK, automaticPolicy),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/upgrade_policies/456/state"),
+				RespondWithJSON(http.StatusOK, scheduledState),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodDelete, "/api/clusters_mgmt/v1/clusters/123/upgrade_policies/456"),
+				RespondWithJSON(http.StatusNotFound, `{
+				  "kind": "Error",
+				  "id": "404",
+				  "href": "/api/clusters_mgmt/v1/errors/404",
+				  "code": "CLUSTERS-MGMT-404",
+				  "reason": "Upgrade policy '456' not found"
+				}`),
+			),
+		)
+
+		// Run the destroy command:
+		Expect(terraform.Destroy()).To(BeZero())
+	})
+
+	It("Fails to create an automatic upgrade policy without a schedule", func() {
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster_upgrade_policy" "my_policy" {
+		    cluster       = "123"
+		    schedule_type = "automatic"
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails to create an upgrade policy with an invalid schedule type", func() {
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_cluster_upgrade_policy" "my_policy" {
+		    cluster       = "123"
+		    schedule_type = "weekly"
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Can import an upgrade policy", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/upgrade_policies/456"),
+				RespondWithJSON(http.StatusOK, automaticPolicy),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/upgrade_policies/456/state"),
+				RespondWithJSON(http.StatusOK, scheduledState),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/upgrade_policies/456"),
+				RespondWithJSON(http.StatusOK, automaticPolicy),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/upgrade_policies/456/state"),
+				RespondWithJSON(http.StatusOK, scheduledState),
+			),
+		)
+
+		// Run the import command:
+		terraform.Source(`
+		  resource "ocm_cluster_upgrade_policy" "my_policy" {
+		    cluster       = "123"
+		    schedule_type = "automatic"
+		    schedule      = "0 2 * * 6"
+		  }
+		`)
+		Expect(terraform.Run("import", "ocm_cluster_upgrade_policy.my_policy", "123,456")).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_cluster_upgrade_policy", "my_policy")
+		Expect(resource).To(MatchJQ(".attributes.cluster", "123"))
+		Expect(resource).To(MatchJQ(".attributes.id", "456"))
+		Expect(resource).To(MatchJQ(".attributes.schedule_type", "automatic"))
+		Expect(resource).To(MatchJQ(".attributes.schedule", "0 2 * * 6"))
+	})
+
+	It("Fails to import an upgrade policy with an invalid identifier", func() {
+		terraform.Source(`
+		  resource "ocm_cluster_upgrade_policy" "my_policy" {
+		    cluster       = "123"
+		    schedule_type = "automatic"
+		    schedule      = "0 2 * * 6"
+		  }
+		`)
+		Expect(terraform.Run("import", "ocm_cluster_upgrade_policy.my_policy", "456")).ToNot(BeZero())
+	})
+})
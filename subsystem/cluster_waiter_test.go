@@ -146,9 +146,87 @@ var _ = Describe("Cluster creation", func() {
 			`)
 
 			Expect(terraform.Apply()).To(BeZero())
+
+			// The destroy refreshes the state of the cluster:
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithJSON(http.StatusOK, templateReadyState),
+				),
+			)
 			Expect(terraform.Destroy()).To(BeZero())
 		})
 
+		It("Waits again when the cluster is no longer ready", func() {
+			terraform.Source(`
+				resource "ocm_cluster_wait" "rosa_cluster" {
+				  cluster = "123"
+				}
+			`)
+			Expect(terraform.Apply()).To(BeZero())
+
+			// The refresh finds the cluster hibernated, so the apply should wait again:
+			server.AppendHandlers(
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithPatchedJSON(http.StatusOK, templateReadyState, `[
+					  {
+					    "op": "replace",
+					    "path": "/state",
+					    "value": "hibernating"
+					  }
+					]`),
+				),
+				CombineHandlers(
+					VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+					RespondWithJSON(http.StatusOK, templateReadyState),
+				),
+			)
+			Expect(terraform.Apply()).To(BeZero())
+			resource := terraform.Resource("ocm_cluster_wait", "rosa_cluster")
+			Expect(resource).To(MatchJQ(`.attributes.ready`, true))
+		})
+	})
+
+	It("Waits for the target states and conditions", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithPatchedJSON(http.StatusOK, templateReadyState, `[
+				  {
+				    "op": "replace",
+				    "path": "/state",
+				    "value": "hibernating"
+				  }
+				]`),
+			),
+		)
+
+		terraform.Source(`
+				resource "ocm_cluster_wait" "rosa_cluster" {
+				  cluster = "123"
+				  target_states = ["ready", "hibernating"]
+				  conditions = {
+				    console_url_set = true
+				  }
+				}
+			`)
+
+		Expect(terraform.Apply()).To(BeZero())
+		resource := terraform.Resource("ocm_cluster_wait", "rosa_cluster")
+		Expect(resource).To(MatchJQ(`.attributes.ready`, true))
+	})
+
+	It("Rejects unsupported target states", func() {
+		terraform.Source(`
+				resource "ocm_cluster_wait" "rosa_cluster" {
+				  cluster = "123"
+				  target_states = ["uninstalling"]
+				}
+			`)
+
+		Expect(terraform.Apply()).ToNot(BeZero())
 	})
 
 	It("Create cluster with a positive timeout but get cluster not ready", func() {
@@ -179,6 +257,21 @@ var _ = Describe("Cluster creation", func() {
 				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
 				RespondWithJSON(http.StatusOK, templateErrorState),
 			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/status"),
+				RespondWithJSON(http.StatusOK, `{
+				  "state": "error",
+				  "provision_error_code": "OCM3055",
+				  "provision_error_message": "Invalid subnet"
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/logs/install"),
+				VerifyFormKV("tail", "30"),
+				RespondWithJSON(http.StatusOK, `{
+				  "content": "level=error msg=\"subnet-1 doesn't exist\""
+				}`),
+			),
 		)
 
 		terraform.Source(`
@@ -188,8 +281,12 @@ var _ = Describe("Cluster creation", func() {
 				}
 			`)
 
-		Expect(terraform.Apply()).To(BeZero())
+		// The apply should fail, but the resource should be saved in the state with the details
+		// of the error:
+		Expect(terraform.Apply()).ToNot(BeZero())
 		resource := terraform.Resource("ocm_cluster_wait", "rosa_cluster")
 		Expect(resource).To(MatchJQ(`.attributes.ready`, false))
+		Expect(resource).To(MatchJQ(`.attributes.provision_error`, "OCM3055: Invalid subnet"))
+		Expect(resource).To(MatchJQ(`.status`, "tainted"))
 	})
 })
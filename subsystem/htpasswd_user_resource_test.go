@@ -0,0 +1,210 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2/dsl/core"             // nolint
+	. "github.com/onsi/gomega"                         // nolint
+	. "github.com/onsi/gomega/ghttp"                   // nolint
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("Htpasswd user creation", func() {
+	It("Can create a user with a given password", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodPost,
+					"/api/clusters_mgmt/v1/clusters/123/identity_providers/456/htpasswd_users",
+				),
+				VerifyJSON(`{
+				  "kind": "HTPasswdUser",
+				  "username": "my-user",
+				  "password": "my-password"
+				}`),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "789",
+				  "username": "my-user"
+				}`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_htpasswd_user" "my_user" {
+		    cluster           = "123"
+		    identity_provider = "456"
+		    username          = "my-user"
+		    password          = "my-password"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_htpasswd_user", "my_user")
+		Expect(resource).To(MatchJQ(".attributes.id", "789"))
+		Expect(resource).To(MatchJQ(".attributes.username", "my-user"))
+		Expect(resource).To(MatchJQ(".attributes.password", "my-password"))
+	})
+
+	It("Generates a password if it isn't given", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodPost,
+					"/api/clusters_mgmt/v1/clusters/123/identity_providers/456/htpasswd_users",
+				),
+				VerifyJQ(`.username`, "my-user"),
+				VerifyJQ(`.password | length`, 20),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "789",
+				  "username": "my-user"
+				}`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_htpasswd_user" "my_user" {
+		    cluster           = "123"
+		    identity_provider = "456"
+		    username          = "my-user"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_htpasswd_user", "my_user")
+		Expect(resource).To(MatchJQ(".attributes.password | length", 20))
+	})
+})
+
+var _ = Describe("Htpasswd user update", func() {
+	const user = `{
+	  "id": "789",
+	  "username": "my-user"
+	}`
+
+	BeforeEach(func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodPost,
+					"/api/clusters_mgmt/v1/clusters/123/identity_providers/456/htpasswd_users",
+				),
+				RespondWithJSON(http.StatusOK, user),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_htpasswd_user" "my_user" {
+		    cluster           = "123"
+		    identity_provider = "456"
+		    username          = "my-user"
+		    password          = "my-password"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+	})
+
+	It("Changes the password in place", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodGet,
+					"/api/clusters_mgmt/v1/clusters/123/identity_providers/456/htpasswd_users/789",
+				),
+				RespondWithJSON(http.StatusOK, user),
+			),
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodPatch,
+					"/api/clusters_mgmt/v1/clusters/123/identity_providers/456/htpasswd_users/789",
+				),
+				VerifyJQ(`.password`, "my-new-password"),
+				RespondWithJSON(http.StatusOK, user),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_htpasswd_user" "my_user" {
+		    cluster           = "123"
+		    identity_provider = "456"
+		    username          = "my-user"
+		    password          = "my-new-password"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_htpasswd_user", "my_user")
+		Expect(resource).To(MatchJQ(".attributes.id", "789"))
+		Expect(resource).To(MatchJQ(".attributes.password", "my-new-password"))
+	})
+})
+
+var _ = Describe("Htpasswd user import", func() {
+	const user = `{
+	  "id": "789",
+	  "username": "my-user"
+	}`
+
+	It("Can import a user", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodGet,
+					"/api/clusters_mgmt/v1/clusters/123/identity_providers/456/htpasswd_users/789",
+				),
+				RespondWithJSON(http.StatusOK, user),
+			),
+			CombineHandlers(
+				VerifyRequest(
+					http.MethodGet,
+					"/api/clusters_mgmt/v1/clusters/123/identity_providers/456/htpasswd_users/789",
+				),
+				RespondWithJSON(http.StatusOK, user),
+			),
+		)
+
+		// Run the import command:
+		terraform.Source(`
+		  resource "ocm_htpasswd_user" "my_user" {
+		    cluster           = "123"
+		    identity_provider = "456"
+		    username          = "my-user"
+		  }
+		`)
+		Expect(terraform.Run("import", "ocm_htpasswd_user.my_user", "123,456,789")).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_htpasswd_user", "my_user")
+		Expect(resource).To(MatchJQ(".attributes.cluster", "123"))
+		Expect(resource).To(MatchJQ(".attributes.identity_provider", "456"))
+		Expect(resource).To(MatchJQ(".attributes.id", "789"))
+		Expect(resource).To(MatchJQ(".attributes.username", "my-user"))
+	})
+})
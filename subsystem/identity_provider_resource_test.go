@@ -633,3 +633,448 @@ var _ = Describe("Identity provider creation", func() {
 		Expect(terraform.Apply()).ToNot(BeZero())
 	})
 })
+
+var _ = Describe("Identity provider import", func() {
+	const identityProvider = `{
+	  "id": "456",
+	  "name": "my-ip",
+	  "mapping_method": "claim",
+	  "gitlab": {
+	    "ca": "test-ca",
+	    "url": "https://test.gitlab.com",
+	    "client_id": "test-client"
+	  }
+	}`
+
+	It("Can import an identity provider", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/identity_providers/456"),
+				RespondWithJSON(http.StatusOK, identityProvider),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/identity_providers/456"),
+				RespondWithJSON(http.StatusOK, identityProvider),
+			),
+		)
+
+		// Run the import command:
+		terraform.Source(`
+		  resource "ocm_identity_provider" "my_ip" {
+		    cluster = "123"
+		    name    = "my-ip"
+		    gitlab = {
+		      ca            = "test-ca"
+		      url           = "https://test.gitlab.com"
+		      client_id     = "test-client"
+		      client_secret = "test-secret"
+		    }
+		  }
+		`)
+		Expect(terraform.Run("import", "ocm_identity_provider.my_ip", "123,456")).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_identity_provider", "my_ip")
+		Expect(resource).To(MatchJQ(".attributes.cluster", "123"))
+		Expect(resource).To(MatchJQ(".attributes.id", "456"))
+		Expect(resource).To(MatchJQ(".attributes.name", "my-ip"))
+		Expect(resource).To(MatchJQ(".attributes.mapping_method", "claim"))
+		Expect(resource).To(MatchJQ(".attributes.gitlab.url", "https://test.gitlab.com"))
+	})
+
+	It("Fails to import an identity provider with an invalid identifier", func() {
+		terraform.Source(`
+		  resource "ocm_identity_provider" "my_ip" {
+		    cluster = "123"
+		    name    = "my-ip"
+		    gitlab = {
+		      ca            = "test-ca"
+		      url           = "https://test.gitlab.com"
+		      client_id     = "test-client"
+		      client_secret = "test-secret"
+		    }
+		  }
+		`)
+		Expect(terraform.Run("import", "ocm_identity_provider.my_ip", "456")).ToNot(BeZero())
+	})
+})
+
+var _ = Describe("Identity provider update", func() {
+	const cluster = `{
+	  "id": "123",
+	  "name": "my-cluster",
+	  "state": "ready"
+	}`
+
+	const identityProvider = `{
+	  "id": "456",
+	  "name": "my-ip",
+	  "mapping_method": "claim",
+	  "github": {
+	    "client_id": "test-client",
+	    "organizations": ["my-org"]
+	  }
+	}`
+
+	BeforeEach(func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, cluster),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters/123/identity_providers"),
+				RespondWithJSON(http.StatusOK, identityProvider),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_identity_provider" "my_ip" {
+		    cluster = "123"
+		    name    = "my-ip"
+		    github = {
+		      client_id     = "test-client"
+		      client_secret = "test-secret"
+		      organizations = ["my-org"]
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+	})
+
+	It("Updates the details of the identity provider in place", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/identity_providers/456"),
+				RespondWithJSON(http.StatusOK, identityProvider),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123/identity_providers/456"),
+				VerifyJQ(`.name`, nil),
+				VerifyJQ(`.type`, "GithubIdentityProvider"),
+				VerifyJQ(`.github.client_secret`, "new-secret"),
+				VerifyJQ(`.github.organizations`, []interface{}{"my-org", "my-other-org"}),
+				RespondWithPatchedJSON(http.StatusOK, identityProvider, `[
+				  {
+				    "op": "replace",
+				    "path": "/github/organizations",
+				    "value": ["my-org", "my-other-org"]
+				  }
+				]`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_identity_provider" "my_ip" {
+		    cluster = "123"
+		    name    = "my-ip"
+		    github = {
+		      client_id     = "test-client"
+		      client_secret = "new-secret"
+		      organizations = ["my-org", "my-other-org"]
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_identity_provider", "my_ip")
+		Expect(resource).To(MatchJQ(".attributes.id", "456"))
+		Expect(resource).To(MatchJQ(".attributes.github.client_secret", "new-secret"))
+	})
+
+	It("Replaces the identity provider when the type changes", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/identity_providers/456"),
+				RespondWithJSON(http.StatusOK, identityProvider),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodDelete, "/api/clusters_mgmt/v1/clusters/123/identity_providers/456"),
+				RespondWithJSON(http.StatusNoContent, "{}"),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, cluster),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters/123/identity_providers"),
+				VerifyJQ(`.type`, "GitlabIdentityProvider"),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "789",
+				  "name": "my-ip",
+				  "mapping_method": "claim",
+				  "gitlab": {
+				    "url": "https://test.gitlab.com",
+				    "client_id": "test-client"
+				  }
+				}`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_identity_provider" "my_ip" {
+		    cluster = "123"
+		    name    = "my-ip"
+		    gitlab = {
+		      url           = "https://test.gitlab.com"
+		      client_id     = "test-client"
+		      client_secret = "test-secret"
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_identity_provider", "my_ip")
+		Expect(resource).To(MatchJQ(".attributes.id", "789"))
+	})
+})
+
+var _ = Describe("Htpasswd identity provider users", func() {
+	const cluster = `{
+	  "id": "123",
+	  "name": "my-cluster",
+	  "state": "ready"
+	}`
+
+	const identityProvider = `{
+	  "id": "456",
+	  "name": "my-ip",
+	  "mapping_method": "claim",
+	  "htpasswd": {}
+	}`
+
+	BeforeEach(func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, cluster),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters/123/identity_providers"),
+				VerifyJQ(`.htpasswd.users.items[0].username`, "my-user"),
+				VerifyJQ(`.htpasswd.users.items[0].password`, "my-password"),
+				VerifyJQ(`.htpasswd.users.items[1].username`, "my-other-user"),
+				VerifyJQ(`.htpasswd.users.items[1].password`, "my-other-password"),
+				RespondWithJSON(http.StatusOK, identityProvider),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_identity_provider" "my_ip" {
+		    cluster = "123"
+		    name    = "my-ip"
+		    htpasswd = {
+		      users = [
+		        {
+		          username = "my-user"
+		          password = "my-password"
+		        },
+		        {
+		          username = "my-other-user"
+		          password = "my-other-password"
+		        },
+		      ]
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+	})
+
+	It("Adds, updates and removes users to match the configuration", func() {
+		// Prepare the server:
+		users := `{
+		  "page": 1,
+		  "size": 3,
+		  "total": 3,
+		  "items": [
+		    {
+		      "id": "u1",
+		      "username": "my-user"
+		    },
+		    {
+		      "id": "u2",
+		      "username": "my-other-user"
+		    },
+		    {
+		      "id": "u3",
+		      "username": "added-user"
+		    }
+		  ]
+		}`
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/identity_providers/456"),
+				RespondWithJSON(http.StatusOK, identityProvider),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/identity_providers/456/htpasswd_users"),
+				RespondWithJSON(http.StatusOK, users),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/identity_providers/456/htpasswd_users"),
+				RespondWithJSON(http.StatusOK, users),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123/identity_providers/456/htpasswd_users/u1"),
+				VerifyJQ(`.password`, "my-new-password"),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "u1",
+				  "username": "my-user"
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters/123/identity_providers/456/htpasswd_users"),
+				VerifyJQ(`.username`, "my-new-user"),
+				VerifyJQ(`.password`, "my-new-user-password"),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "u4",
+				  "username": "my-new-user"
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodDelete, "/api/clusters_mgmt/v1/clusters/123/identity_providers/456/htpasswd_users/u3"),
+				RespondWithJSON(http.StatusNoContent, "{}"),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodDelete, "/api/clusters_mgmt/v1/clusters/123/identity_providers/456/htpasswd_users/u2"),
+				RespondWithJSON(http.StatusNoContent, "{}"),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123/identity_providers/456"),
+				VerifyJQ(`.htpasswd.users`, nil),
+				RespondWithJSON(http.StatusOK, identityProvider),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_identity_provider" "my_ip" {
+		    cluster = "123"
+		    name    = "my-ip"
+		    htpasswd = {
+		      users = [
+		        {
+		          username = "my-user"
+		          password = "my-new-password"
+		        },
+		        {
+		          username = "my-new-user"
+		          password = "my-new-user-password"
+		        },
+		      ]
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_identity_provider", "my_ip")
+		Expect(resource).To(MatchJQ(`.attributes.htpasswd.users | length`, 2))
+		Expect(resource).To(MatchJQ(".attributes.htpasswd.users[1].username", "my-new-user"))
+	})
+
+	It("Generates the passwords of the users that don't have one", func() {
+		// Prepare the server:
+		users := `{
+		  "page": 1,
+		  "size": 2,
+		  "total": 2,
+		  "items": [
+		    {
+		      "id": "u1",
+		      "username": "my-user"
+		    },
+		    {
+		      "id": "u2",
+		      "username": "my-other-user"
+		    }
+		  ]
+		}`
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/identity_providers/456"),
+				RespondWithJSON(http.StatusOK, identityProvider),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/identity_providers/456/htpasswd_users"),
+				RespondWithJSON(http.StatusOK, users),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/identity_providers/456/htpasswd_users"),
+				RespondWithJSON(http.StatusOK, users),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters/123/identity_providers/456/htpasswd_users"),
+				VerifyJQ(`.username`, "my-new-user"),
+				VerifyJQ(`.password | length`, 20),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "u3",
+				  "username": "my-new-user"
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodDelete, "/api/clusters_mgmt/v1/clusters/123/identity_providers/456/htpasswd_users/u2"),
+				RespondWithJSON(http.StatusNoContent, "{}"),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123/identity_providers/456"),
+				RespondWithJSON(http.StatusOK, identityProvider),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_identity_provider" "my_ip" {
+		    cluster = "123"
+		    name    = "my-ip"
+		    htpasswd = {
+		      users = [
+		        {
+		          username = "my-user"
+		        },
+		        {
+		          username = "my-new-user"
+		        },
+		      ]
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_identity_provider", "my_ip")
+		Expect(resource).To(MatchJQ(".attributes.htpasswd.users[0].password", "my-password"))
+		Expect(resource).To(MatchJQ(".attributes.htpasswd.users[1].password | length", 20))
+	})
+
+	It("Fails if both a single user and a list of users are given", func() {
+		terraform.Source(`
+		  resource "ocm_identity_provider" "my_ip" {
+		    cluster = "123"
+		    name    = "my-ip"
+		    htpasswd = {
+		      username = "my-user"
+		      password = "my-password"
+		      users = [
+		        {
+		          username = "my-other-user"
+		          password = "my-other-password"
+		        },
+		      ]
+		    }
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+})
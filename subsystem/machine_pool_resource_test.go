@@ -401,4 +401,146 @@ var _ = Describe("Machine pool creation", func() {
 		Expect(resource).To(MatchJQ(`.attributes.labels | length`, 2))
 		Expect(resource).To(MatchJQ(".attributes.use_spot_instances", true))
 	})
+
+	It("Recreates the machine pool if it was deleted outside Terraform", func() {
+		const machinePool = `{
+		  "id": "my-pool",
+		  "instance_type": "r5.xlarge",
+		  "replicas": 10
+		}`
+
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters/123/machine_pools"),
+				RespondWithJSON(http.StatusOK, machinePool),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_machine_pool" "my_pool" {
+		    cluster      = "123"
+		    name         = "my-pool"
+		    machine_type = "r5.xlarge"
+		    replicas     = 10
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Prepare the server so that the machine pool is no longer found, and then created again:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/machine_pools/my-pool"),
+				RespondWithJSON(http.StatusNotFound, `{
+				  "kind": "Error",
+				  "id": "404",
+				  "href": "/api/clusters_mgmt/v1/errors/404",
+				  "code": "CLUSTERS-MGMT-404",
+				  "reason": "Machine pool 'my-pool' not found"
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "123",
+				  "name": "my-cluster",
+				  "state": "ready"
+				}`),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters/123/machine_pools"),
+				RespondWithJSON(http.StatusOK, machinePool),
+			),
+		)
+
+		// Run the apply command again:
+		Expect(terraform.Apply()).To(BeZero())
+		resource := terraform.Resource("ocm_machine_pool", "my_pool")
+		Expect(resource).To(MatchJQ(".attributes.id", "my-pool"))
+	})
+})
+
+var _ = Describe("Machine pool import", func() {
+	const machinePool = `{
+	  "id": "my-pool",
+	  "instance_type": "r5.xlarge",
+	  "replicas": 10,
+	  "labels": {
+	    "label_key1": "label_value1"
+	  }
+	}`
+
+	It("Can import a machine pool", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/machine_pools/my-pool"),
+				RespondWithJSON(http.StatusOK, machinePool),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/machine_pools/my-pool"),
+				RespondWithJSON(http.StatusOK, machinePool),
+			),
+		)
+
+		// Run the import command:
+		terraform.Source(`
+		  resource "ocm_machine_pool" "my_pool" {
+		    cluster      = "123"
+		    name         = "my-pool"
+		    machine_type = "r5.xlarge"
+		    replicas     = 10
+		  }
+		`)
+		Expect(terraform.Run("import", "ocm_machine_pool.my_pool", "123,my-pool")).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_machine_pool", "my_pool")
+		Expect(resource).To(MatchJQ(".attributes.cluster", "123"))
+		Expect(resource).To(MatchJQ(".attributes.id", "my-pool"))
+		Expect(resource).To(MatchJQ(".attributes.name", "my-pool"))
+		Expect(resource).To(MatchJQ(".attributes.machine_type", "r5.xlarge"))
+		Expect(resource).To(MatchJQ(".attributes.replicas", 10.0))
+		Expect(resource).To(MatchJQ(".attributes.labels.label_key1", "label_value1"))
+	})
+
+	It("Fails to import a machine pool that doesn't exist", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/machine_pools/my-pool"),
+				RespondWithJSON(http.StatusNotFound, `{
+				  "kind": "Error",
+				  "id": "404",
+				  "href": "/api/clusters_mgmt/v1/errors/404",
+				  "code": "CLUSTERS-MGMT-404",
+				  "reason": "Machine pool 'my-pool' not found"
+				}`),
+			),
+		)
+
+		// Run the import command:
+		terraform.Source(`
+		  resource "ocm_machine_pool" "my_pool" {
+		    cluster      = "123"
+		    name         = "my-pool"
+		    machine_type = "r5.xlarge"
+		    replicas     = 10
+		  }
+		`)
+		Expect(terraform.Run("import", "ocm_machine_pool.my_pool", "123,my-pool")).ToNot(BeZero())
+	})
+
+	It("Fails to import a machine pool with an invalid identifier", func() {
+		terraform.Source(`
+		  resource "ocm_machine_pool" "my_pool" {
+		    cluster      = "123"
+		    name         = "my-pool"
+		    machine_type = "r5.xlarge"
+		    replicas     = 10
+		  }
+		`)
+		Expect(terraform.Run("import", "ocm_machine_pool.my_pool", "my-pool")).ToNot(BeZero())
+	})
 })
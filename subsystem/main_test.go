@@ -167,6 +167,10 @@ func (b *TerraformRunnerBuilder) Build() *TerraformRunner {
 	// Enable verbose debug:
 	envMap["TF_LOG"] = "DEBUG"
 
+	// Disable the dry run of clusters while planning, as most tests don't expect the additional
+	// requests. Tests that need it can enable it with the 'Setenv' method of the runner.
+	envMap["OCM_CLUSTER_DRY_RUN"] = "false"
+
 	// Reconstruct the environment list:
 	envList := make([]string, 0, len(envMap))
 	for name, value := range envMap {
@@ -203,6 +207,18 @@ func (r *TerraformRunner) Source(text string) {
 	ExpectWithOffset(1, err).ToNot(HaveOccurred())
 }
 
+// Setenv sets an environment variable for the commands that will be executed by the runner.
+func (r *TerraformRunner) Setenv(name, value string) {
+	prefix := name + "="
+	for i, text := range r.env {
+		if strings.HasPrefix(text, prefix) {
+			r.env[i] = prefix + value
+			return
+		}
+	}
+	r.env = append(r.env, prefix+value)
+}
+
 // Run runs a command.
 func (r *TerraformRunner) Run(args ...string) int {
 	var err error
@@ -0,0 +1,309 @@
+/*
+Copyright (c) 2021 Red Hat, Inc.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+  http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package provider
+
+import (
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2/dsl/core"             // nolint
+	. "github.com/onsi/gomega"                         // nolint
+	. "github.com/onsi/gomega/ghttp"                   // nolint
+	. "github.com/openshift-online/ocm-sdk-go/testing" // nolint
+)
+
+var _ = Describe("Node pool creation", func() {
+	const cluster = `{
+	  "id": "123",
+	  "name": "my-cluster",
+	  "state": "ready",
+	  "hypershift": {
+	    "enabled": true
+	  },
+	  "version": {
+	    "id": "openshift-v4.12.10"
+	  }
+	}`
+
+	const nodePool = `{
+	  "id": "my-pool",
+	  "subnet": "subnet-1",
+	  "availability_zone": "us-east-1a",
+	  "aws_node_pool": {
+	    "instance_type": "m5.xlarge"
+	  },
+	  "replicas": 2,
+	  "labels": {
+	    "label_key1": "label_value1"
+	  },
+	  "taints": [
+	    {
+	      "key": "key1",
+	      "value": "value1",
+	      "effect": "NoSchedule"
+	    }
+	  ],
+	  "version": {
+	    "id": "openshift-v4.12.8"
+	  },
+	  "auto_repair": true
+	}`
+
+	It("Can create a node pool and upgrade it", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, cluster),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPost, "/api/clusters_mgmt/v1/clusters/123/node_pools"),
+				VerifyJQ(`.id`, "my-pool"),
+				VerifyJQ(`.subnet`, "subnet-1"),
+				VerifyJQ(`.aws_node_pool.instance_type`, "m5.xlarge"),
+				VerifyJQ(`.replicas`, 2.0),
+				VerifyJQ(`.labels.label_key1`, "label_value1"),
+				VerifyJQ(`.taints[0].effect`, "NoSchedule"),
+				VerifyJQ(`.version.id`, "openshift-v4.12.8"),
+				RespondWithJSON(http.StatusCreated, nodePool),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_node_pool" "my_pool" {
+		    cluster       = "123"
+		    name          = "my-pool"
+		    subnet_id     = "subnet-1"
+		    instance_type = "m5.xlarge"
+		    replicas      = 2
+		    labels = {
+		      "label_key1" = "label_value1"
+		    }
+		    taints = [
+		      {
+		        key           = "key1"
+		        value         = "value1"
+		        schedule_type = "NoSchedule"
+		      }
+		    ]
+		    version = "openshift-v4.12.8"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_node_pool", "my_pool")
+		Expect(resource).To(MatchJQ(".attributes.id", "my-pool"))
+		Expect(resource).To(MatchJQ(".attributes.availability_zone", "us-east-1a"))
+		Expect(resource).To(MatchJQ(".attributes.version", "openshift-v4.12.8"))
+		Expect(resource).To(MatchJQ(".attributes.auto_repair", true))
+
+		// Prepare the server for the update:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/node_pools/my-pool"),
+				RespondWithJSON(http.StatusOK, nodePool),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, cluster),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodPatch, "/api/clusters_mgmt/v1/clusters/123/node_pools/my-pool"),
+				VerifyJSON(`{
+				  "kind": "NodePool",
+				  "id": "my-pool",
+				  "autoscaling": {
+				    "kind": "NodePoolAutoscaling",
+				    "min_replica": 2,
+				    "max_replica": 4
+				  },
+				  "labels": {},
+				  "taints": [],
+				  "version": {
+				    "kind": "Version",
+				    "id": "openshift-v4.12.10"
+				  }
+				}`),
+				RespondWithPatchedJSON(http.StatusOK, nodePool, `[
+				  {
+				    "op": "remove",
+				    "path": "/replicas"
+				  },
+				  {
+				    "op": "remove",
+				    "path": "/labels"
+				  },
+				  {
+				    "op": "remove",
+				    "path": "/taints"
+				  },
+				  {
+				    "op": "add",
+				    "path": "/autoscaling",
+				    "value": {
+				      "min_replica": 2,
+				      "max_replica": 4
+				    }
+				  },
+				  {
+				    "op": "replace",
+				    "path": "/version/id",
+				    "value": "openshift-v4.12.10"
+				  }
+				]`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_node_pool" "my_pool" {
+		    cluster             = "123"
+		    name                = "my-pool"
+		    subnet_id           = "subnet-1"
+		    instance_type       = "m5.xlarge"
+		    autoscaling_enabled = true
+		    min_replicas        = 2
+		    max_replicas        = 4
+		    version             = "openshift-v4.12.10"
+		  }
+		`)
+		Expect(terraform.Apply()).To(BeZero())
+
+		// Check the state:
+		resource = terraform.Resource("ocm_node_pool", "my_pool")
+		Expect(resource).To(MatchJQ(".attributes.min_replicas", 2.0))
+		Expect(resource).To(MatchJQ(".attributes.max_replicas", 4.0))
+		Expect(resource).To(MatchJQ(".attributes.version", "openshift-v4.12.10"))
+		Expect(resource).To(MatchJQ(".attributes.labels", nil))
+	})
+
+	It("Fails to upgrade a node pool beyond the control plane version", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, cluster),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_node_pool" "my_pool" {
+		    cluster       = "123"
+		    name          = "my-pool"
+		    instance_type = "m5.xlarge"
+		    replicas      = 2
+		    version       = "openshift-v4.13.0"
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails to create a node pool in a classic cluster", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, `{
+				  "id": "123",
+				  "name": "my-cluster",
+				  "state": "ready"
+				}`),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_node_pool" "my_pool" {
+		    cluster       = "123"
+		    name          = "my-pool"
+		    instance_type = "m5.xlarge"
+		    replicas      = 2
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Fails to create a node pool with both replicas and autoscaling", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123"),
+				RespondWithJSON(http.StatusOK, cluster),
+			),
+		)
+
+		// Run the apply command:
+		terraform.Source(`
+		  resource "ocm_node_pool" "my_pool" {
+		    cluster             = "123"
+		    name                = "my-pool"
+		    instance_type       = "m5.xlarge"
+		    replicas            = 2
+		    autoscaling_enabled = true
+		    min_replicas        = 2
+		    max_replicas        = 4
+		  }
+		`)
+		Expect(terraform.Apply()).ToNot(BeZero())
+	})
+
+	It("Can import a node pool", func() {
+		// Prepare the server:
+		server.AppendHandlers(
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/node_pools/my-pool"),
+				RespondWithJSON(http.StatusOK, nodePool),
+			),
+			CombineHandlers(
+				VerifyRequest(http.MethodGet, "/api/clusters_mgmt/v1/clusters/123/node_pools/my-pool"),
+				RespondWithJSON(http.StatusOK, nodePool),
+			),
+		)
+
+		// Run the import command:
+		terraform.Source(`
+		  resource "ocm_node_pool" "my_pool" {
+		    cluster       = "123"
+		    name          = "my-pool"
+		    instance_type = "m5.xlarge"
+		    replicas      = 2
+		  }
+		`)
+		Expect(terraform.Run("import", "ocm_node_pool.my_pool", "123,my-pool")).To(BeZero())
+
+		// Check the state:
+		resource := terraform.Resource("ocm_node_pool", "my_pool")
+		Expect(resource).To(MatchJQ(".attributes.cluster", "123"))
+		Expect(resource).To(MatchJQ(".attributes.id", "my-pool"))
+		Expect(resource).To(MatchJQ(".attributes.subnet_id", "subnet-1"))
+		Expect(resource).To(MatchJQ(".attributes.instance_type", "m5.xlarge"))
+	})
+
+	It("Fails to import a node pool with an invalid identifier", func() {
+		terraform.Source(`
+		  resource "ocm_node_pool" "my_pool" {
+		    cluster       = "123"
+		    name          = "my-pool"
+		    instance_type = "m5.xlarge"
+		    replicas      = 2
+		  }
+		`)
+		Expect(terraform.Run("import", "ocm_node_pool.my_pool", "my-pool")).ToNot(BeZero())
+	})
+})